@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -17,6 +18,40 @@ import (
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
 	"github.com/oapi-codegen/runtime"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	ApiKeyScopes     = "apiKey.Scopes"
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for AuditEntryEntityType.
+const (
+	AuditEntryEntityTypeAppearance AuditEntryEntityType = "appearance"
+	AuditEntryEntityTypeCharacter  AuditEntryEntityType = "character"
+	AuditEntryEntityTypeFranchise  AuditEntryEntityType = "franchise"
+	AuditEntryEntityTypeMovie      AuditEntryEntityType = "movie"
+)
+
+// Defines values for BatchOperationOp.
+const (
+	CreateCharacter  BatchOperationOp = "create_character"
+	CreateMovie      BatchOperationOp = "create_movie"
+	DeleteCharacter  BatchOperationOp = "delete_character"
+	DeleteMovie      BatchOperationOp = "delete_movie"
+	LinkAppearance   BatchOperationOp = "link_appearance"
+	UnlinkAppearance BatchOperationOp = "unlink_appearance"
+	UpdateCharacter  BatchOperationOp = "update_character"
+	UpdateMovie      BatchOperationOp = "update_movie"
+)
+
+// Defines values for CatalogRecordType.
+const (
+	CatalogRecordTypeAppearance CatalogRecordType = "appearance"
+	CatalogRecordTypeCharacter  CatalogRecordType = "character"
+	CatalogRecordTypeFranchise  CatalogRecordType = "franchise"
+	CatalogRecordTypeMovie      CatalogRecordType = "movie"
 )
 
 // Defines values for CertificateType.
@@ -26,12 +61,200 @@ const (
 	CertificateTypeMovie     CertificateType = "Movie"
 )
 
+// Defines values for EventType.
+const (
+	AppearanceLinked   EventType = "appearance.linked"
+	AppearanceUnlinked EventType = "appearance.unlinked"
+	CharacterCreated   EventType = "character.created"
+	CharacterDeleted   EventType = "character.deleted"
+	CharacterRestored  EventType = "character.restored"
+	CharacterUpdated   EventType = "character.updated"
+	FranchiseCreated   EventType = "franchise.created"
+	FranchiseDeleted   EventType = "franchise.deleted"
+	FranchiseUpdated   EventType = "franchise.updated"
+	MovieCreated       EventType = "movie.created"
+	MovieDeleted       EventType = "movie.deleted"
+	MovieRestored      EventType = "movie.restored"
+	MovieUpdated       EventType = "movie.updated"
+)
+
+// Defines values for FranchiseInstallmentKind.
+const (
+	Original FranchiseInstallmentKind = "original"
+	Prequel  FranchiseInstallmentKind = "prequel"
+	Sequel   FranchiseInstallmentKind = "sequel"
+	SpinOff  FranchiseInstallmentKind = "spin-off"
+)
+
+// Defines values for MoviesPerYearBy.
+const (
+	MoviesPerYearByDecade MoviesPerYearBy = "decade"
+	MoviesPerYearByYear   MoviesPerYearBy = "year"
+)
+
+// Defines values for ReportFormat.
+const (
+	ReportFormatCsv  ReportFormat = "csv"
+	ReportFormatJson ReportFormat = "json"
+)
+
+// Defines values for Role.
+const (
+	Admin  Role = "admin"
+	Editor Role = "editor"
+	Viewer Role = "viewer"
+)
+
+// Defines values for GetAuditParamsEntityType.
+const (
+	GetAuditParamsEntityTypeAppearance GetAuditParamsEntityType = "appearance"
+	GetAuditParamsEntityTypeCharacter  GetAuditParamsEntityType = "character"
+	GetAuditParamsEntityTypeFranchise  GetAuditParamsEntityType = "franchise"
+	GetAuditParamsEntityTypeMovie      GetAuditParamsEntityType = "movie"
+)
+
+// Defines values for GetExportParamsFormat.
+const (
+	GetExportParamsFormatCsv    GetExportParamsFormat = "csv"
+	GetExportParamsFormatJson   GetExportParamsFormat = "json"
+	GetExportParamsFormatNdjson GetExportParamsFormat = "ndjson"
+)
+
+// Defines values for GetStatsMoviesPerYearParamsBy.
+const (
+	GetStatsMoviesPerYearParamsByDecade GetStatsMoviesPerYearParamsBy = "decade"
+	GetStatsMoviesPerYearParamsByYear   GetStatsMoviesPerYearParamsBy = "year"
+)
+
+// ApiKey defines model for ApiKey.
+type ApiKey struct {
+	CreatedAt time.Time          `json:"created_at"`
+	Id        openapi_types.UUID `json:"id"`
+	Name      string             `json:"name"`
+
+	// Prefix Leading hex digits of the SHA-256 hash of the key to tell keys apart without revealing them
+	Prefix string `json:"prefix"`
+	Role   Role   `json:"role"`
+}
+
 // Appearance defines model for Appearance.
 type Appearance struct {
-	CharacterId string `json:"character_id"`
-	MovieId     string `json:"movie_id"`
+	CharacterId openapi_types.UUID `json:"character_id"`
+	MovieId     openapi_types.UUID `json:"movie_id"`
+}
+
+// AuditChange defines model for AuditChange.
+type AuditChange struct {
+	After  *interface{} `json:"after"`
+	Before *interface{} `json:"before"`
+}
+
+// AuditEntry defines model for AuditEntry.
+type AuditEntry struct {
+	// Actor Subject of the caller, anonymous without credentials
+	Actor string `json:"actor"`
+
+	// Diff Changed fields by name
+	Diff map[string]AuditChange `json:"diff"`
+
+	// EntityId The movie of an appearance
+	EntityId   openapi_types.UUID   `json:"entity_id"`
+	EntityType AuditEntryEntityType `json:"entity_type"`
+	Id         int64                `json:"id"`
+	Operation  string               `json:"operation"`
+
+	// RelatedId The character of an appearance
+	RelatedId *openapi_types.UUID `json:"related_id,omitempty"`
+	Time      time.Time           `json:"time"`
+}
+
+// AuditEntryEntityType defines model for AuditEntry.EntityType.
+type AuditEntryEntityType string
+
+// AuditLog defines model for AuditLog.
+type AuditLog struct {
+	Entries []AuditEntry `json:"entries"`
+}
+
+// BatchOperation defines model for BatchOperation.
+type BatchOperation struct {
+	// CharacterId An ID or "$ref"
+	CharacterId *string `json:"character_id,omitempty"`
+
+	// Id Movie or character to update or delete, an ID or "$ref"
+	Id *string `json:"id,omitempty"`
+
+	// Movie Star Wars checks that SWAPI knows a created character
+	Movie *string `json:"movie,omitempty"`
+
+	// MovieId An ID or "$ref"
+	MovieId *string          `json:"movie_id,omitempty"`
+	Name    *string          `json:"name,omitempty"`
+	Op      BatchOperationOp `json:"op"`
+
+	// Ref Names the movie or character a create makes, for later operations
+	Ref         *string `json:"ref,omitempty"`
+	ReleaseYear *int    `json:"release_year,omitempty"`
+	Title       *string `json:"title,omitempty"`
+
+	// Version Version an update or delete is based on, 0 skips the check
+	Version *int64 `json:"version,omitempty"`
+}
+
+// BatchOperationOp defines model for BatchOperation.Op.
+type BatchOperationOp string
+
+// BatchRequest defines model for BatchRequest.
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchResponse defines model for BatchResponse.
+type BatchResponse struct {
+	Results []BatchResult `json:"results"`
+}
+
+// BatchResult defines model for BatchResult.
+type BatchResult struct {
+	Appearance *Appearance `json:"appearance,omitempty"`
+
+	// Character The created or updated character
+	Character *map[string]interface{} `json:"character,omitempty"`
+
+	// Movie The created or updated movie
+	Movie *map[string]interface{} `json:"movie,omitempty"`
+	Op    string                  `json:"op"`
+	Ref   *string                 `json:"ref,omitempty"`
+
+	// Status Status the REST operation answers with
+	Status int `json:"status"`
+}
+
+// CatalogRecord defines model for CatalogRecord.
+type CatalogRecord struct {
+	// Character Key or ID of the character of an appearance
+	Character *string `json:"character,omitempty"`
+
+	// Key Names a movie or character for the appearances of the file
+	Key *string `json:"key,omitempty"`
+
+	// Movie Key or ID of the movie of an appearance
+	Movie *string `json:"movie,omitempty"`
+
+	// Name Character name
+	Name *string `json:"name,omitempty"`
+
+	// ReleaseYear Movie release year, 1900 or later
+	ReleaseYear *int `json:"release_year,omitempty"`
+
+	// Title Movie title
+	Title *string           `json:"title,omitempty"`
+	Type  CatalogRecordType `json:"type"`
 }
 
+// CatalogRecordType defines model for CatalogRecord.Type.
+type CatalogRecordType string
+
 // Certificate defines model for Certificate.
 type Certificate struct {
 	Id       string          `json:"id"`
@@ -44,11 +267,252 @@ type Certificate struct {
 // CertificateType defines model for Certificate.Type.
 type CertificateType string
 
+// CertificateSigningRequest defines model for CertificateSigningRequest.
+type CertificateSigningRequest struct {
+	// Csr PEM encoded PKCS#10 request
+	Csr string `json:"csr"`
+
+	// Id ID of an existing movie or character
+	Id openapi_types.UUID `json:"id"`
+
+	// MovieId Movie whose certificate signs a character CSR
+	MovieId *openapi_types.UUID `json:"movie_id,omitempty"`
+}
+
 // Character defines model for Character.
 type Character struct {
 	Description *string `json:"description,omitempty"`
-	Movie       *string `json:"movie,omitempty"`
-	Name        string  `json:"name"`
+
+	// FranchiseId Links the new character to the franchise; Star Wars characters are checked against SWAPI
+	FranchiseId *openapi_types.UUID `json:"franchise_id,omitempty"`
+	Movie       *string             `json:"movie,omitempty"`
+	Name        string              `json:"name"`
+}
+
+// CharacterCount defines model for CharacterCount.
+type CharacterCount struct {
+	ID     openapi_types.UUID `json:"ID"`
+	Movies int                `json:"movies"`
+	Name   string             `json:"name"`
+}
+
+// CharacterNode defines model for CharacterNode.
+type CharacterNode struct {
+	ID   openapi_types.UUID `json:"ID"`
+	Name string             `json:"name"`
+}
+
+// CharacterPath defines model for CharacterPath.
+type CharacterPath struct {
+	// Characters From the first character to the last
+	Characters []CharacterNode `json:"characters"`
+
+	// Degrees Number of movies on the path
+	Degrees int `json:"degrees"`
+
+	// Movies The movie at i has the characters at i and i+1
+	Movies []MovieNode `json:"movies"`
+}
+
+// CharacterTimeline defines model for CharacterTimeline.
+type CharacterTimeline struct {
+	Franchises []TimelineFranchise `json:"franchises"`
+}
+
+// CoStar defines model for CoStar.
+type CoStar struct {
+	Character CharacterNode `json:"character"`
+
+	// Movies The shared movies, the oldest first
+	Movies []MovieNode `json:"movies"`
+
+	// Shared Number of shared movies
+	Shared int `json:"shared"`
+}
+
+// CoStars defines model for CoStars.
+type CoStars struct {
+	CoStars []CoStar `json:"co_stars"`
+}
+
+// ConsistencyProof defines model for ConsistencyProof.
+type ConsistencyProof struct {
+	Consistency [][]byte `json:"consistency"`
+	First       int      `json:"first"`
+	Second      int      `json:"second"`
+}
+
+// CreatedApiKey defines model for CreatedApiKey.
+type CreatedApiKey struct {
+	Key ApiKey `json:"key"`
+
+	// Secret Send as X-API-Key, it cannot be retrieved again
+	Secret string `json:"secret"`
+}
+
+// CreatedWebhook defines model for CreatedWebhook.
+type CreatedWebhook struct {
+	// Secret Key of the HMAC-SHA256 in X-Webhook-Signature, it cannot be retrieved again
+	Secret  string  `json:"secret"`
+	Webhook Webhook `json:"webhook"`
+}
+
+// DeadLetter defines model for DeadLetter.
+type DeadLetter struct {
+	Attempts   int                `json:"attempts"`
+	DeliveryId openapi_types.UUID `json:"delivery_id"`
+	Event      Event              `json:"event"`
+	FailedAt   time.Time          `json:"failed_at"`
+	LastError  string             `json:"last_error"`
+}
+
+// EmptyMovies defines model for EmptyMovies.
+type EmptyMovies struct {
+	Movies []MovieNode `json:"movies"`
+}
+
+// Event defines model for Event.
+type Event struct {
+	// Data The movie, character or appearance after the change
+	Data *interface{} `json:"data,omitempty"`
+	Id   int64        `json:"id"`
+	Time time.Time    `json:"time"`
+
+	// Type An EventType, or reset when missed events are no longer buffered
+	Type string `json:"type"`
+}
+
+// EventType defines model for EventType.
+type EventType string
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Franchise defines model for Franchise.
+type Franchise struct {
+	ID openapi_types.UUID `json:"ID"`
+
+	// Characters Characters linked to the franchise as a whole
+	Characters   []openapi_types.UUID   `json:"characters"`
+	Installments []FranchiseInstallment `json:"installments"`
+	Name         string                 `json:"name"`
+	Version      int64                  `json:"version"`
+}
+
+// FranchiseCharacter defines model for FranchiseCharacter.
+type FranchiseCharacter struct {
+	Character    CharacterNode     `json:"character"`
+	Installments []InstallmentNode `json:"installments"`
+
+	// Linked Linked to the franchise as a whole
+	Linked bool `json:"linked"`
+}
+
+// FranchiseCharacterLink defines model for FranchiseCharacterLink.
+type FranchiseCharacterLink struct {
+	CharacterId openapi_types.UUID `json:"character_id"`
+}
+
+// FranchiseCharacters defines model for FranchiseCharacters.
+type FranchiseCharacters struct {
+	Characters []FranchiseCharacter `json:"characters"`
+}
+
+// FranchiseInstallment defines model for FranchiseInstallment.
+type FranchiseInstallment struct {
+	Kind    FranchiseInstallmentKind `json:"kind"`
+	MovieId openapi_types.UUID       `json:"movie_id"`
+}
+
+// FranchiseInstallmentKind defines model for FranchiseInstallment.Kind.
+type FranchiseInstallmentKind string
+
+// FranchiseList defines model for FranchiseList.
+type FranchiseList struct {
+	Franchises []Franchise `json:"franchises"`
+}
+
+// GraphComponent defines model for GraphComponent.
+type GraphComponent struct {
+	Characters []CharacterNode `json:"characters"`
+	Movies     []MovieNode     `json:"movies"`
+}
+
+// GraphComponents defines model for GraphComponents.
+type GraphComponents struct {
+	Components []GraphComponent `json:"components"`
+}
+
+// GraphQLError defines model for GraphQLError.
+type GraphQLError struct {
+	Extensions *GraphQLError_Extensions `json:"extensions,omitempty"`
+	Locations  *[]struct {
+		Column *int `json:"column,omitempty"`
+		Line   *int `json:"line,omitempty"`
+	} `json:"locations,omitempty"`
+	Message string         `json:"message"`
+	Path    *[]interface{} `json:"path,omitempty"`
+}
+
+// GraphQLError_Extensions defines model for GraphQLError.Extensions.
+type GraphQLError_Extensions struct {
+	// Code BAD_USER_INPUT, UNAUTHENTICATED, FORBIDDEN, NOT_FOUND, PRECONDITION_FAILED, BAD_GATEWAY, COMPLEXITY_LIMIT or INTERNAL
+	Code                 *string                `json:"code,omitempty"`
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
+// GraphQLRequest defines model for GraphQLRequest.
+type GraphQLRequest struct {
+	OperationName *string                 `json:"operationName"`
+	Query         string                  `json:"query"`
+	Variables     *map[string]interface{} `json:"variables"`
+}
+
+// GraphQLResponse defines model for GraphQLResponse.
+type GraphQLResponse struct {
+	Data       *map[string]interface{} `json:"data"`
+	Errors     *[]GraphQLError         `json:"errors,omitempty"`
+	Extensions *map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// ImportError defines model for ImportError.
+type ImportError struct {
+	// Line Line of the file where the record starts
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// ImportReport defines model for ImportReport.
+type ImportReport struct {
+	Appearances int           `json:"appearances"`
+	Characters  int           `json:"characters"`
+	DryRun      bool          `json:"dry_run"`
+	Errors      []ImportError `json:"errors"`
+
+	// Ids IDs of the created movies and characters by key
+	Ids *map[string]openapi_types.UUID `json:"ids,omitempty"`
+
+	// Movies Movies created, or to be created on a dry run
+	Movies int `json:"movies"`
+}
+
+// InclusionProof defines model for InclusionProof.
+type InclusionProof struct {
+	AuditPath [][]byte `json:"audit_path"`
+	LeafIndex int      `json:"leaf_index"`
+	TreeSize  int      `json:"tree_size"`
+}
+
+// InstallmentNode defines model for InstallmentNode.
+type InstallmentNode struct {
+	Kind  string    `json:"kind"`
+	Movie MovieNode `json:"movie"`
+
+	// Position Place in the franchise, from 1
+	Position int `json:"position"`
 }
 
 // Movie defines model for Movie.
@@ -57,56 +521,665 @@ type Movie struct {
 	Title       string `json:"title"`
 }
 
+// MovieNode defines model for MovieNode.
+type MovieNode struct {
+	ID    openapi_types.UUID `json:"ID"`
+	Title string             `json:"title"`
+	Year  int                `json:"year"`
+}
+
+// MoviePatch defines model for MoviePatch.
+type MoviePatch struct {
+	ReleaseYear *int    `json:"release_year,omitempty"`
+	Title       *string `json:"title,omitempty"`
+}
+
+// MoviesPerYear defines model for MoviesPerYear.
+type MoviesPerYear struct {
+	By     MoviesPerYearBy `json:"by"`
+	Counts []YearCount     `json:"counts"`
+}
+
+// MoviesPerYearBy defines model for MoviesPerYear.By.
+type MoviesPerYearBy string
+
+// NewApiKey defines model for NewApiKey.
+type NewApiKey struct {
+	Name string `json:"name"`
+	Role Role   `json:"role"`
+}
+
+// NewFranchise defines model for NewFranchise.
+type NewFranchise struct {
+	// Installments The movies in order
+	Installments *[]FranchiseInstallment `json:"installments,omitempty"`
+	Name         string                  `json:"name"`
+}
+
+// NewWebhook defines model for NewWebhook.
+type NewWebhook struct {
+	Events *[]EventType `json:"events,omitempty"`
+	Url    string       `json:"url"`
+}
+
+// OrphanCharacters defines model for OrphanCharacters.
+type OrphanCharacters struct {
+	Characters []CharacterNode `json:"characters"`
+}
+
+// Problem defines model for Problem.
+type Problem struct {
+	Detail *string `json:"detail,omitempty"`
+
+	// Errors Per-field validation failures
+	Errors   *[]FieldError `json:"errors,omitempty"`
+	Instance *string       `json:"instance,omitempty"`
+	Status   int           `json:"status"`
+	Title    string        `json:"title"`
+
+	// Type URI reference identifying the problem type
+	Type string `json:"type"`
+}
+
+// ReportFormat defines model for ReportFormat.
+type ReportFormat string
+
+// Role defines model for Role.
+type Role string
+
+// SignedCertificate defines model for SignedCertificate.
+type SignedCertificate struct {
+	Certificate Certificate `json:"certificate"`
+	Pem         string      `json:"pem"`
+}
+
+// SignedTreeHead defines model for SignedTreeHead.
+type SignedTreeHead struct {
+	RootHash []byte `json:"root_hash"`
+
+	// Signature RSA PKCS#1 v1.5 SHA-256 signature by the CA key over the RFC 6962 tree head
+	Signature []byte `json:"signature"`
+
+	// Timestamp Milliseconds since the Unix epoch
+	Timestamp int64 `json:"timestamp"`
+	TreeSize  int   `json:"tree_size"`
+}
+
+// StatsSummary defines model for StatsSummary.
+type StatsSummary struct {
+	Appearances int `json:"appearances"`
+
+	// AverageCast Mean number of characters per movie
+	AverageCast float64 `json:"average_cast"`
+	Characters  int     `json:"characters"`
+
+	// EmptyMovies Movies without characters
+	EmptyMovies int `json:"empty_movies"`
+	Movies      int `json:"movies"`
+
+	// OrphanCharacters Characters appearing in no movie
+	OrphanCharacters int `json:"orphan_characters"`
+}
+
+// TimelineFranchise defines model for TimelineFranchise.
+type TimelineFranchise struct {
+	ID           openapi_types.UUID `json:"ID"`
+	Installments []InstallmentNode  `json:"installments"`
+
+	// Linked Linked to the franchise as a whole
+	Linked bool   `json:"linked"`
+	Name   string `json:"name"`
+}
+
+// TopCharacters defines model for TopCharacters.
+type TopCharacters struct {
+	Characters []CharacterCount `json:"characters"`
+}
+
+// Trash defines model for Trash.
+type Trash struct {
+	Characters []TrashedCharacter `json:"characters"`
+	Movies     []TrashedMovie     `json:"movies"`
+}
+
+// TrashedCharacter defines model for TrashedCharacter.
+type TrashedCharacter struct {
+	ID openapi_types.UUID `json:"ID"`
+
+	// Appearances Appearance links a restore brings back
+	Appearances int       `json:"appearances"`
+	DeletedAt   time.Time `json:"deleted_at"`
+	Movie       *string   `json:"movie,omitempty"`
+	Name        string    `json:"name"`
+	Version     int64     `json:"version"`
+}
+
+// TrashedMovie defines model for TrashedMovie.
+type TrashedMovie struct {
+	ID openapi_types.UUID `json:"ID"`
+
+	// Appearances Appearance links a restore brings back
+	Appearances int       `json:"appearances"`
+	DeletedAt   time.Time `json:"deleted_at"`
+	Title       string    `json:"title"`
+	Version     int64     `json:"version"`
+	Year        int       `json:"year"`
+}
+
+// Webhook defines model for Webhook.
+type Webhook struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// Events Event types delivered, empty for all
+	Events []EventType        `json:"events"`
+	Id     openapi_types.UUID `json:"id"`
+	Url    string             `json:"url"`
+}
+
+// WebhookDelivery defines model for WebhookDelivery.
+type WebhookDelivery struct {
+	Attempt     int       `json:"attempt"`
+	DeliveredAt time.Time `json:"delivered_at"`
+	DurationMs  int64     `json:"duration_ms"`
+
+	// Error Missing for successful deliveries
+	Error     *string   `json:"error,omitempty"`
+	EventId   int64     `json:"event_id"`
+	EventType EventType `json:"event_type"`
+
+	// Id Sent as X-Webhook-Delivery, the same for every attempt
+	Id openapi_types.UUID `json:"id"`
+
+	// StatusCode Missing when the receiver did not answer
+	StatusCode *int `json:"status_code,omitempty"`
+}
+
+// YearCount defines model for YearCount.
+type YearCount struct {
+	Movies int `json:"movies"`
+
+	// Year The year, or the first year of the decade
+	Year int `json:"year"`
+}
+
+// AuditLimit defines model for AuditLimit.
+type AuditLimit = int
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
+// IfNoneMatch defines model for IfNoneMatch.
+type IfNoneMatch = string
+
+// StatsFormat defines model for StatsFormat.
+type StatsFormat = ReportFormat
+
+// BadGateway defines model for BadGateway.
+type BadGateway = Problem
+
+// BadRequest defines model for BadRequest.
+type BadRequest = Problem
+
+// Forbidden defines model for Forbidden.
+type Forbidden = Problem
+
+// NotFound defines model for NotFound.
+type NotFound = Problem
+
+// PreconditionFailed defines model for PreconditionFailed.
+type PreconditionFailed = Problem
+
+// Unauthorized defines model for Unauthorized.
+type Unauthorized = Problem
+
+// DeleteAppearancesParams defines parameters for DeleteAppearances.
+type DeleteAppearancesParams struct {
+	MovieId     openapi_types.UUID `form:"movie_id" json:"movie_id"`
+	CharacterId openapi_types.UUID `form:"character_id" json:"character_id"`
+}
+
+// GetAuditParams defines parameters for GetAudit.
+type GetAuditParams struct {
+	Actor *string `form:"actor,omitempty" json:"actor,omitempty"`
+
+	// Operation e.g. character.updated or movie.purged
+	Operation  *string                   `form:"operation,omitempty" json:"operation,omitempty"`
+	EntityType *GetAuditParamsEntityType `form:"entity_type,omitempty" json:"entity_type,omitempty"`
+
+	// EntityId Also matches the appearances of the movie or character
+	EntityId *openapi_types.UUID `form:"entity_id,omitempty" json:"entity_id,omitempty"`
+	Since    *time.Time          `form:"since,omitempty" json:"since,omitempty"`
+	Until    *time.Time          `form:"until,omitempty" json:"until,omitempty"`
+
+	// Before Only entries with a lower id, to page back
+	Before *int64      `form:"before,omitempty" json:"before,omitempty"`
+	Limit  *AuditLimit `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetAuditParamsEntityType defines parameters for GetAudit.
+type GetAuditParamsEntityType string
+
+// PutCharactersParams defines parameters for PutCharacters.
+type PutCharactersParams struct {
+	Id openapi_types.UUID `form:"id" json:"id"`
+
+	// IfMatch ETag of the version the change is based on, or * for any version
+	IfMatch IfMatch `json:"If-Match"`
+}
+
 // GetCharactersByMovieParams defines parameters for GetCharactersByMovie.
 type GetCharactersByMovieParams struct {
 	Title string `form:"title" json:"title"`
 }
 
-// DeleteMoviesParams defines parameters for DeleteMovies.
-type DeleteMoviesParams struct {
-	Id string `form:"id" json:"id"`
+// DeleteCharactersIdParams defines parameters for DeleteCharactersId.
+type DeleteCharactersIdParams struct {
+	// IfMatch ETag of the version the change is based on, or * for any version
+	IfMatch IfMatch `json:"If-Match"`
 }
 
-// GetMoviesByCharacterParams defines parameters for GetMoviesByCharacter.
-type GetMoviesByCharacterParams struct {
-	Name string `form:"name" json:"name"`
+// GetCharactersIdParams defines parameters for GetCharactersId.
+type GetCharactersIdParams struct {
+	// IfNoneMatch ETags the client has cached
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
 }
 
-// PostAppearancesJSONRequestBody defines body for PostAppearances for application/json ContentType.
-type PostAppearancesJSONRequestBody = Appearance
+// GetCharactersIdCoStarsParams defines parameters for GetCharactersIdCoStars.
+type GetCharactersIdCoStarsParams struct {
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
 
-// PostCharactersJSONRequestBody defines body for PostCharacters for application/json ContentType.
-type PostCharactersJSONRequestBody = Character
+// GetCharactersIdHistoryParams defines parameters for GetCharactersIdHistory.
+type GetCharactersIdHistoryParams struct {
+	Limit *AuditLimit `form:"limit,omitempty" json:"limit,omitempty"`
+}
 
-// PutCharactersJSONRequestBody defines body for PutCharacters for application/json ContentType.
-type PutCharactersJSONRequestBody = Character
+// GetCharactersIdPathParams defines parameters for GetCharactersIdPath.
+type GetCharactersIdPathParams struct {
+	To openapi_types.UUID `form:"to" json:"to"`
 
-// PostMoviesJSONRequestBody defines body for PostMovies for application/json ContentType.
-type PostMoviesJSONRequestBody = Movie
+	// MaxDepth Most movies the path may go through
+	MaxDepth *int `form:"max_depth,omitempty" json:"max_depth,omitempty"`
+}
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// Add a character appearance in a movie
-	// (POST /appearances)
-	PostAppearances(ctx echo.Context) error
-	// List all certificates
-	// (GET /certificates)
-	GetCertificates(ctx echo.Context) error
-	// List all characters
-	// (GET /characters)
-	GetCharacters(ctx echo.Context) error
-	// Create a new character
-	// (POST /characters)
+// GetEventsParams defines parameters for GetEvents.
+type GetEventsParams struct {
+	LastEventID *int64 `json:"Last-Event-ID,omitempty"`
+}
+
+// GetEventsWsParams defines parameters for GetEventsWs.
+type GetEventsWsParams struct {
+	// LastEventId Resume after this event, as Last-Event-ID does for /events
+	LastEventId *int64 `form:"last_event_id,omitempty" json:"last_event_id,omitempty"`
+}
+
+// GetExportParams defines parameters for GetExport.
+type GetExportParams struct {
+	Format *GetExportParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetExportParamsFormat defines parameters for GetExport.
+type GetExportParamsFormat string
+
+// DeleteFranchisesIdParams defines parameters for DeleteFranchisesId.
+type DeleteFranchisesIdParams struct {
+	// IfMatch ETag of the version the change is based on, or * for any version
+	IfMatch IfMatch `json:"If-Match"`
+}
+
+// GetFranchisesIdParams defines parameters for GetFranchisesId.
+type GetFranchisesIdParams struct {
+	// IfNoneMatch ETags the client has cached
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// PutFranchisesIdParams defines parameters for PutFranchisesId.
+type PutFranchisesIdParams struct {
+	// IfMatch ETag of the version the change is based on, or * for any version
+	IfMatch IfMatch `json:"If-Match"`
+}
+
+// DeleteFranchisesIdCharactersParams defines parameters for DeleteFranchisesIdCharacters.
+type DeleteFranchisesIdCharactersParams struct {
+	CharacterId openapi_types.UUID `form:"character_id" json:"character_id"`
+}
+
+// PostImportJSONBody defines parameters for PostImport.
+type PostImportJSONBody = []map[string]interface{}
+
+// PostImportParams defines parameters for PostImport.
+type PostImportParams struct {
+	// DryRun Only check the file and report what it would create
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+}
+
+// GetLogProofConsistencyParams defines parameters for GetLogProofConsistency.
+type GetLogProofConsistencyParams struct {
+	First int `form:"first" json:"first"`
+
+	// Second Defaults to the current tree size
+	Second *int `form:"second,omitempty" json:"second,omitempty"`
+}
+
+// GetLogProofInclusionParams defines parameters for GetLogProofInclusion.
+type GetLogProofInclusionParams struct {
+	// Hash Base64 encoded leaf hash of the certificate
+	Hash *string `form:"hash,omitempty" json:"hash,omitempty"`
+
+	// Serial Serial number of the certificate
+	Serial *string `form:"serial,omitempty" json:"serial,omitempty"`
+
+	// TreeSize Tree size to prove against, defaults to the current size
+	TreeSize *int `form:"tree_size,omitempty" json:"tree_size,omitempty"`
+}
+
+// DeleteMoviesParams defines parameters for DeleteMovies.
+type DeleteMoviesParams struct {
+	Id openapi_types.UUID `form:"id" json:"id"`
+
+	// IfMatch ETag of the version the change is based on, or * for any version
+	IfMatch IfMatch `json:"If-Match"`
+}
+
+// GetMoviesByCharacterParams defines parameters for GetMoviesByCharacter.
+type GetMoviesByCharacterParams struct {
+	Name string `form:"name" json:"name"`
+}
+
+// GetMoviesIdParams defines parameters for GetMoviesId.
+type GetMoviesIdParams struct {
+	// IfNoneMatch ETags the client has cached
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// PatchMoviesIdParams defines parameters for PatchMoviesId.
+type PatchMoviesIdParams struct {
+	// IfMatch ETag of the version the change is based on, or * for any version
+	IfMatch IfMatch `json:"If-Match"`
+}
+
+// GetMoviesIdHistoryParams defines parameters for GetMoviesIdHistory.
+type GetMoviesIdHistoryParams struct {
+	Limit *AuditLimit `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetStatsParams defines parameters for GetStats.
+type GetStatsParams struct {
+	Format *StatsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetStatsEmptyMoviesParams defines parameters for GetStatsEmptyMovies.
+type GetStatsEmptyMoviesParams struct {
+	Format *StatsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetStatsMoviesPerYearParams defines parameters for GetStatsMoviesPerYear.
+type GetStatsMoviesPerYearParams struct {
+	By     *GetStatsMoviesPerYearParamsBy `form:"by,omitempty" json:"by,omitempty"`
+	Format *StatsFormat                   `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetStatsMoviesPerYearParamsBy defines parameters for GetStatsMoviesPerYear.
+type GetStatsMoviesPerYearParamsBy string
+
+// GetStatsOrphanCharactersParams defines parameters for GetStatsOrphanCharacters.
+type GetStatsOrphanCharactersParams struct {
+	Format *StatsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetStatsTopCharactersParams defines parameters for GetStatsTopCharacters.
+type GetStatsTopCharactersParams struct {
+	Limit  *int         `form:"limit,omitempty" json:"limit,omitempty"`
+	Format *StatsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// PostAdminApiKeysJSONRequestBody defines body for PostAdminApiKeys for application/json ContentType.
+type PostAdminApiKeysJSONRequestBody = NewApiKey
+
+// PostAdminWebhooksJSONRequestBody defines body for PostAdminWebhooks for application/json ContentType.
+type PostAdminWebhooksJSONRequestBody = NewWebhook
+
+// PostAppearancesJSONRequestBody defines body for PostAppearances for application/json ContentType.
+type PostAppearancesJSONRequestBody = Appearance
+
+// PostBatchJSONRequestBody defines body for PostBatch for application/json ContentType.
+type PostBatchJSONRequestBody = BatchRequest
+
+// PostCertificatesCsrJSONRequestBody defines body for PostCertificatesCsr for application/json ContentType.
+type PostCertificatesCsrJSONRequestBody = CertificateSigningRequest
+
+// PostCharactersJSONRequestBody defines body for PostCharacters for application/json ContentType.
+type PostCharactersJSONRequestBody = Character
+
+// PutCharactersJSONRequestBody defines body for PutCharacters for application/json ContentType.
+type PutCharactersJSONRequestBody = Character
+
+// PostFranchisesJSONRequestBody defines body for PostFranchises for application/json ContentType.
+type PostFranchisesJSONRequestBody = NewFranchise
+
+// PutFranchisesIdJSONRequestBody defines body for PutFranchisesId for application/json ContentType.
+type PutFranchisesIdJSONRequestBody = NewFranchise
+
+// PostFranchisesIdCharactersJSONRequestBody defines body for PostFranchisesIdCharacters for application/json ContentType.
+type PostFranchisesIdCharactersJSONRequestBody = FranchiseCharacterLink
+
+// PostGraphqlJSONRequestBody defines body for PostGraphql for application/json ContentType.
+type PostGraphqlJSONRequestBody = GraphQLRequest
+
+// PostImportJSONRequestBody defines body for PostImport for application/json ContentType.
+type PostImportJSONRequestBody = PostImportJSONBody
+
+// PostMoviesJSONRequestBody defines body for PostMovies for application/json ContentType.
+type PostMoviesJSONRequestBody = Movie
+
+// PatchMoviesIdJSONRequestBody defines body for PatchMoviesId for application/json ContentType.
+type PatchMoviesIdJSONRequestBody = MoviePatch
+
+// Getter for additional properties for GraphQLError_Extensions. Returns the specified
+// element and whether it was found
+func (a GraphQLError_Extensions) Get(fieldName string) (value interface{}, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for GraphQLError_Extensions
+func (a *GraphQLError_Extensions) Set(fieldName string, value interface{}) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]interface{})
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for GraphQLError_Extensions to handle AdditionalProperties
+func (a *GraphQLError_Extensions) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if raw, found := object["code"]; found {
+		err = json.Unmarshal(raw, &a.Code)
+		if err != nil {
+			return fmt.Errorf("error reading 'code': %w", err)
+		}
+		delete(object, "code")
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]interface{})
+		for fieldName, fieldBuf := range object {
+			var fieldVal interface{}
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for GraphQLError_Extensions to handle AdditionalProperties
+func (a GraphQLError_Extensions) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	if a.Code != nil {
+		object["code"], err = json.Marshal(a.Code)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'code': %w", err)
+		}
+	}
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// List API keys
+	// (GET /admin/api-keys)
+	GetAdminApiKeys(ctx echo.Context) error
+	// Create an API key
+	// (POST /admin/api-keys)
+	PostAdminApiKeys(ctx echo.Context) error
+	// Revoke an API key
+	// (DELETE /admin/api-keys/{id})
+	DeleteAdminApiKeysId(ctx echo.Context, id openapi_types.UUID) error
+	// List webhooks
+	// (GET /admin/webhooks)
+	GetAdminWebhooks(ctx echo.Context) error
+	// Subscribe a URL to change events
+	// (POST /admin/webhooks)
+	PostAdminWebhooks(ctx echo.Context) error
+	// Delete a webhook
+	// (DELETE /admin/webhooks/{id})
+	DeleteAdminWebhooksId(ctx echo.Context, id openapi_types.UUID) error
+	// Get a webhook
+	// (GET /admin/webhooks/{id})
+	GetAdminWebhooksId(ctx echo.Context, id openapi_types.UUID) error
+	// Events a webhook could not be delivered
+	// (GET /admin/webhooks/{id}/dead-letters)
+	GetAdminWebhooksIdDeadLetters(ctx echo.Context, id openapi_types.UUID) error
+	// Send a dead letter again
+	// (POST /admin/webhooks/{id}/dead-letters/{delivery_id}/redeliver)
+	PostAdminWebhooksIdDeadLettersDeliveryIdRedeliver(ctx echo.Context, id openapi_types.UUID, deliveryId openapi_types.UUID) error
+	// Latest delivery attempts of a webhook, newest first
+	// (GET /admin/webhooks/{id}/deliveries)
+	GetAdminWebhooksIdDeliveries(ctx echo.Context, id openapi_types.UUID) error
+	// Remove a character appearance from a movie
+	// (DELETE /appearances)
+	DeleteAppearances(ctx echo.Context, params DeleteAppearancesParams) error
+	// Add a character appearance in a movie
+	// (POST /appearances)
+	PostAppearances(ctx echo.Context) error
+	// Search the audit trail of changes, the latest first
+	// (GET /audit)
+	GetAudit(ctx echo.Context, params GetAuditParams) error
+	// Run several changes as one transaction
+	// (POST /batch)
+	PostBatch(ctx echo.Context) error
+	// List all certificates
+	// (GET /certificates)
+	GetCertificates(ctx echo.Context) error
+	// Sign a certificate signing request for a movie or character
+	// (POST /certificates/csr)
+	PostCertificatesCsr(ctx echo.Context) error
+	// List all characters
+	// (GET /characters)
+	GetCharacters(ctx echo.Context) error
+	// Create a new character
+	// (POST /characters)
 	PostCharacters(ctx echo.Context) error
 	// Update a character
 	// (PUT /characters)
-	PutCharacters(ctx echo.Context) error
+	PutCharacters(ctx echo.Context, params PutCharactersParams) error
 	// Get characters by movie title
 	// (GET /characters/by-movie)
 	GetCharactersByMovie(ctx echo.Context, params GetCharactersByMovieParams) error
 	// Delete a character
 	// (DELETE /characters/{id})
-	DeleteCharactersId(ctx echo.Context, id string) error
+	DeleteCharactersId(ctx echo.Context, id openapi_types.UUID, params DeleteCharactersIdParams) error
+	// Get a character
+	// (GET /characters/{id})
+	GetCharactersId(ctx echo.Context, id openapi_types.UUID, params GetCharactersIdParams) error
+	// List the characters sharing a movie with a character, the most shared movies first
+	// (GET /characters/{id}/co-stars)
+	GetCharactersIdCoStars(ctx echo.Context, id openapi_types.UUID, params GetCharactersIdCoStarsParams) error
+	// List the changes of a character and its appearances, the latest first
+	// (GET /characters/{id}/history)
+	GetCharactersIdHistory(ctx echo.Context, id openapi_types.UUID, params GetCharactersIdHistoryParams) error
+	// Find the shortest chain of shared movies from one character to another
+	// (GET /characters/{id}/path)
+	GetCharactersIdPath(ctx echo.Context, id openapi_types.UUID, params GetCharactersIdPathParams) error
+	// Restore a deleted character with its appearances
+	// (POST /characters/{id}/restore)
+	PostCharactersIdRestore(ctx echo.Context, id openapi_types.UUID) error
+	// List the franchises of a character with the installments it appears in, in order
+	// (GET /characters/{id}/timeline)
+	GetCharactersIdTimeline(ctx echo.Context, id openapi_types.UUID) error
+	// Stream changes to movies, characters and appearances as server-sent events
+	// (GET /events)
+	GetEvents(ctx echo.Context, params GetEventsParams) error
+	// Stream change events as JSON WebSocket messages
+	// (GET /events/ws)
+	GetEventsWs(ctx echo.Context, params GetEventsWsParams) error
+	// Download every movie, character and appearance as one consistent snapshot
+	// (GET /export)
+	GetExport(ctx echo.Context, params GetExportParams) error
+	// List all franchises, by name
+	// (GET /franchises)
+	GetFranchises(ctx echo.Context) error
+	// Create a franchise of ordered movies
+	// (POST /franchises)
+	PostFranchises(ctx echo.Context) error
+	// Delete a franchise, keeping its movies and characters
+	// (DELETE /franchises/{id})
+	DeleteFranchisesId(ctx echo.Context, id openapi_types.UUID, params DeleteFranchisesIdParams) error
+	// Get a franchise
+	// (GET /franchises/{id})
+	GetFranchisesId(ctx echo.Context, id openapi_types.UUID, params GetFranchisesIdParams) error
+	// Replace the name and installments of a franchise
+	// (PUT /franchises/{id})
+	PutFranchisesId(ctx echo.Context, id openapi_types.UUID, params PutFranchisesIdParams) error
+	// Unlink a character from a franchise, keeping its appearances
+	// (DELETE /franchises/{id}/characters)
+	DeleteFranchisesIdCharacters(ctx echo.Context, id openapi_types.UUID, params DeleteFranchisesIdCharactersParams) error
+	// List the characters of a franchise with the installments they appear in
+	// (GET /franchises/{id}/characters)
+	GetFranchisesIdCharacters(ctx echo.Context, id openapi_types.UUID) error
+	// Link a character to a franchise as a whole
+	// (POST /franchises/{id}/characters)
+	PostFranchisesIdCharacters(ctx echo.Context, id openapi_types.UUID) error
+	// Split the characters and movies into groups linked through appearances, the largest first
+	// (GET /graph/components)
+	GetGraphComponents(ctx echo.Context) error
+	// GraphiQL, an in-browser IDE for the GraphQL endpoint
+	// (GET /graphql)
+	GetGraphql(ctx echo.Context) error
+	// Run a GraphQL query or mutation over movies, characters and appearances
+	// (POST /graphql)
+	PostGraphql(ctx echo.Context) error
+	// Import movies, characters and appearances from a JSON, NDJSON or CSV file
+	// (POST /import)
+	PostImport(ctx echo.Context, params PostImportParams) error
+	// Get a consistency proof between two tree sizes
+	// (GET /log/proof/consistency)
+	GetLogProofConsistency(ctx echo.Context, params GetLogProofConsistencyParams) error
+	// Get an inclusion proof for a logged certificate
+	// (GET /log/proof/inclusion)
+	GetLogProofInclusion(ctx echo.Context, params GetLogProofInclusionParams) error
+	// Get the signed tree head of the certificate transparency log
+	// (GET /log/sth)
+	GetLogSth(ctx echo.Context) error
 	// Delete a movie
 	// (DELETE /movies)
 	DeleteMovies(ctx echo.Context, params DeleteMoviesParams) error
@@ -119,89 +1192,1036 @@ type ServerInterface interface {
 	// Get movies by character name
 	// (GET /movies/by-character)
 	GetMoviesByCharacter(ctx echo.Context, params GetMoviesByCharacterParams) error
+	// Get a movie
+	// (GET /movies/{id})
+	GetMoviesId(ctx echo.Context, id openapi_types.UUID, params GetMoviesIdParams) error
+	// Change the title or release year of a movie
+	// (PATCH /movies/{id})
+	PatchMoviesId(ctx echo.Context, id openapi_types.UUID, params PatchMoviesIdParams) error
+	// List the changes of a movie and its appearances, the latest first
+	// (GET /movies/{id}/history)
+	GetMoviesIdHistory(ctx echo.Context, id openapi_types.UUID, params GetMoviesIdHistoryParams) error
+	// Restore a deleted movie with its appearances
+	// (POST /movies/{id}/restore)
+	PostMoviesIdRestore(ctx echo.Context, id openapi_types.UUID) error
+	// Count the movies, characters and appearances, the average cast and what is not linked
+	// (GET /stats)
+	GetStats(ctx echo.Context, params GetStatsParams) error
+	// List the movies without characters, the earliest first
+	// (GET /stats/empty-movies)
+	GetStatsEmptyMovies(ctx echo.Context, params GetStatsEmptyMoviesParams) error
+	// Count the movies by release year or decade, the earliest first
+	// (GET /stats/movies-per-year)
+	GetStatsMoviesPerYear(ctx echo.Context, params GetStatsMoviesPerYearParams) error
+	// List the characters appearing in no movie, by name
+	// (GET /stats/orphan-characters)
+	GetStatsOrphanCharacters(ctx echo.Context, params GetStatsOrphanCharactersParams) error
+	// List the characters appearing in the most movies
+	// (GET /stats/top-characters)
+	GetStatsTopCharacters(ctx echo.Context, params GetStatsTopCharactersParams) error
+	// List the deleted movies and characters that can still be restored
+	// (GET /trash)
+	GetTrash(ctx echo.Context) error
+}
+
+// ServerInterfaceWrapper converts echo contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+// GetAdminApiKeys converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAdminApiKeys(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAdminApiKeys(ctx)
+	return err
+}
+
+// PostAdminApiKeys converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAdminApiKeys(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAdminApiKeys(ctx)
+	return err
+}
+
+// DeleteAdminApiKeysId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteAdminApiKeysId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteAdminApiKeysId(ctx, id)
+	return err
+}
+
+// GetAdminWebhooks converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAdminWebhooks(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAdminWebhooks(ctx)
+	return err
+}
+
+// PostAdminWebhooks converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAdminWebhooks(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAdminWebhooks(ctx)
+	return err
+}
+
+// DeleteAdminWebhooksId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteAdminWebhooksId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteAdminWebhooksId(ctx, id)
+	return err
+}
+
+// GetAdminWebhooksId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAdminWebhooksId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAdminWebhooksId(ctx, id)
+	return err
+}
+
+// GetAdminWebhooksIdDeadLetters converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAdminWebhooksIdDeadLetters(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAdminWebhooksIdDeadLetters(ctx, id)
+	return err
+}
+
+// PostAdminWebhooksIdDeadLettersDeliveryIdRedeliver converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAdminWebhooksIdDeadLettersDeliveryIdRedeliver(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "delivery_id" -------------
+	var deliveryId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "delivery_id", runtime.ParamLocationPath, ctx.Param("delivery_id"), &deliveryId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter delivery_id: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAdminWebhooksIdDeadLettersDeliveryIdRedeliver(ctx, id, deliveryId)
+	return err
+}
+
+// GetAdminWebhooksIdDeliveries converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAdminWebhooksIdDeliveries(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAdminWebhooksIdDeliveries(ctx, id)
+	return err
+}
+
+// DeleteAppearances converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteAppearances(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteAppearancesParams
+	// ------------- Required query parameter "movie_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "movie_id", ctx.QueryParams(), &params.MovieId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter movie_id: %s", err))
+	}
+
+	// ------------- Required query parameter "character_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "character_id", ctx.QueryParams(), &params.CharacterId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter character_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteAppearances(ctx, params)
+	return err
+}
+
+// PostAppearances converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAppearances(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAppearances(ctx)
+	return err
+}
+
+// GetAudit converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAudit(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAuditParams
+	// ------------- Optional query parameter "actor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "actor", ctx.QueryParams(), &params.Actor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter actor: %s", err))
+	}
+
+	// ------------- Optional query parameter "operation" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "operation", ctx.QueryParams(), &params.Operation)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter operation: %s", err))
+	}
+
+	// ------------- Optional query parameter "entity_type" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "entity_type", ctx.QueryParams(), &params.EntityType)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter entity_type: %s", err))
+	}
+
+	// ------------- Optional query parameter "entity_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "entity_id", ctx.QueryParams(), &params.EntityId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter entity_id: %s", err))
+	}
+
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", ctx.QueryParams(), &params.Since)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter since: %s", err))
+	}
+
+	// ------------- Optional query parameter "until" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "until", ctx.QueryParams(), &params.Until)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter until: %s", err))
+	}
+
+	// ------------- Optional query parameter "before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "before", ctx.QueryParams(), &params.Before)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter before: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAudit(ctx, params)
+	return err
+}
+
+// PostBatch converts echo context to params.
+func (w *ServerInterfaceWrapper) PostBatch(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostBatch(ctx)
+	return err
+}
+
+// GetCertificates converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCertificates(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCertificates(ctx)
+	return err
+}
+
+// PostCertificatesCsr converts echo context to params.
+func (w *ServerInterfaceWrapper) PostCertificatesCsr(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostCertificatesCsr(ctx)
+	return err
+}
+
+// GetCharacters converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCharacters(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCharacters(ctx)
+	return err
+}
+
+// PostCharacters converts echo context to params.
+func (w *ServerInterfaceWrapper) PostCharacters(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostCharacters(ctx)
+	return err
+}
+
+// PutCharacters converts echo context to params.
+func (w *ServerInterfaceWrapper) PutCharacters(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutCharactersParams
+	// ------------- Required query parameter "id" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "id", ctx.QueryParams(), &params.Id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = IfMatch
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter If-Match is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutCharacters(ctx, params)
+	return err
+}
+
+// GetCharactersByMovie converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCharactersByMovie(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCharactersByMovieParams
+	// ------------- Required query parameter "title" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "title", ctx.QueryParams(), &params.Title)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter title: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCharactersByMovie(ctx, params)
+	return err
+}
+
+// DeleteCharactersId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteCharactersId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteCharactersIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = IfMatch
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter If-Match is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteCharactersId(ctx, id, params)
+	return err
+}
+
+// GetCharactersId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCharactersId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCharactersIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-None-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-None-Match", runtime.ParamLocationHeader, valueList[0], &IfNoneMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-None-Match: %s", err))
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCharactersId(ctx, id, params)
+	return err
+}
+
+// GetCharactersIdCoStars converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCharactersIdCoStars(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCharactersIdCoStarsParams
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCharactersIdCoStars(ctx, id, params)
+	return err
+}
+
+// GetCharactersIdHistory converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCharactersIdHistory(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCharactersIdHistoryParams
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCharactersIdHistory(ctx, id, params)
+	return err
+}
+
+// GetCharactersIdPath converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCharactersIdPath(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCharactersIdPathParams
+	// ------------- Required query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// ------------- Optional query parameter "max_depth" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "max_depth", ctx.QueryParams(), &params.MaxDepth)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max_depth: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCharactersIdPath(ctx, id, params)
+	return err
+}
+
+// PostCharactersIdRestore converts echo context to params.
+func (w *ServerInterfaceWrapper) PostCharactersIdRestore(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostCharactersIdRestore(ctx, id)
+	return err
+}
+
+// GetCharactersIdTimeline converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCharactersIdTimeline(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCharactersIdTimeline(ctx, id)
+	return err
+}
+
+// GetEvents converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEvents(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEventsParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Last-Event-ID" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Last-Event-ID")]; found {
+		var LastEventID int64
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Last-Event-ID, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Last-Event-ID", runtime.ParamLocationHeader, valueList[0], &LastEventID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Last-Event-ID: %s", err))
+		}
+
+		params.LastEventID = &LastEventID
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEvents(ctx, params)
+	return err
+}
+
+// GetEventsWs converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEventsWs(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEventsWsParams
+	// ------------- Optional query parameter "last_event_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "last_event_id", ctx.QueryParams(), &params.LastEventId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter last_event_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEventsWs(ctx, params)
+	return err
+}
+
+// GetExport converts echo context to params.
+func (w *ServerInterfaceWrapper) GetExport(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetExportParams
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetExport(ctx, params)
+	return err
+}
+
+// GetFranchises converts echo context to params.
+func (w *ServerInterfaceWrapper) GetFranchises(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetFranchises(ctx)
+	return err
+}
+
+// PostFranchises converts echo context to params.
+func (w *ServerInterfaceWrapper) PostFranchises(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostFranchises(ctx)
+	return err
+}
+
+// DeleteFranchisesId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteFranchisesId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteFranchisesIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = IfMatch
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter If-Match is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteFranchisesId(ctx, id, params)
+	return err
+}
+
+// GetFranchisesId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetFranchisesId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetFranchisesIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-None-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-None-Match", runtime.ParamLocationHeader, valueList[0], &IfNoneMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-None-Match: %s", err))
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetFranchisesId(ctx, id, params)
+	return err
+}
+
+// PutFranchisesId converts echo context to params.
+func (w *ServerInterfaceWrapper) PutFranchisesId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutFranchisesIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = IfMatch
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter If-Match is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutFranchisesId(ctx, id, params)
+	return err
 }
 
-// ServerInterfaceWrapper converts echo contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler ServerInterface
+// DeleteFranchisesIdCharacters converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteFranchisesIdCharacters(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteFranchisesIdCharactersParams
+	// ------------- Required query parameter "character_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "character_id", ctx.QueryParams(), &params.CharacterId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter character_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteFranchisesIdCharacters(ctx, id, params)
+	return err
 }
 
-// PostAppearances converts echo context to params.
-func (w *ServerInterfaceWrapper) PostAppearances(ctx echo.Context) error {
+// GetFranchisesIdCharacters converts echo context to params.
+func (w *ServerInterfaceWrapper) GetFranchisesIdCharacters(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PostAppearances(ctx)
+	err = w.Handler.GetFranchisesIdCharacters(ctx, id)
 	return err
 }
 
-// GetCertificates converts echo context to params.
-func (w *ServerInterfaceWrapper) GetCertificates(ctx echo.Context) error {
+// PostFranchisesIdCharacters converts echo context to params.
+func (w *ServerInterfaceWrapper) PostFranchisesIdCharacters(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetCertificates(ctx)
+	err = w.Handler.PostFranchisesIdCharacters(ctx, id)
 	return err
 }
 
-// GetCharacters converts echo context to params.
-func (w *ServerInterfaceWrapper) GetCharacters(ctx echo.Context) error {
+// GetGraphComponents converts echo context to params.
+func (w *ServerInterfaceWrapper) GetGraphComponents(ctx echo.Context) error {
 	var err error
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetCharacters(ctx)
+	err = w.Handler.GetGraphComponents(ctx)
 	return err
 }
 
-// PostCharacters converts echo context to params.
-func (w *ServerInterfaceWrapper) PostCharacters(ctx echo.Context) error {
+// GetGraphql converts echo context to params.
+func (w *ServerInterfaceWrapper) GetGraphql(ctx echo.Context) error {
 	var err error
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PostCharacters(ctx)
+	err = w.Handler.GetGraphql(ctx)
 	return err
 }
 
-// PutCharacters converts echo context to params.
-func (w *ServerInterfaceWrapper) PutCharacters(ctx echo.Context) error {
+// PostGraphql converts echo context to params.
+func (w *ServerInterfaceWrapper) PostGraphql(ctx echo.Context) error {
 	var err error
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PutCharacters(ctx)
+	err = w.Handler.PostGraphql(ctx)
 	return err
 }
 
-// GetCharactersByMovie converts echo context to params.
-func (w *ServerInterfaceWrapper) GetCharactersByMovie(ctx echo.Context) error {
+// PostImport converts echo context to params.
+func (w *ServerInterfaceWrapper) PostImport(ctx echo.Context) error {
 	var err error
 
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetCharactersByMovieParams
-	// ------------- Required query parameter "title" -------------
+	var params PostImportParams
+	// ------------- Optional query parameter "dry_run" -------------
 
-	err = runtime.BindQueryParameter("form", true, true, "title", ctx.QueryParams(), &params.Title)
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", ctx.QueryParams(), &params.DryRun)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter title: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter dry_run: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetCharactersByMovie(ctx, params)
+	err = w.Handler.PostImport(ctx, params)
 	return err
 }
 
-// DeleteCharactersId converts echo context to params.
-func (w *ServerInterfaceWrapper) DeleteCharactersId(ctx echo.Context) error {
+// GetLogProofConsistency converts echo context to params.
+func (w *ServerInterfaceWrapper) GetLogProofConsistency(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "id" -------------
-	var id string
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetLogProofConsistencyParams
+	// ------------- Required query parameter "first" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "first", ctx.QueryParams(), &params.First)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter first: %s", err))
+	}
+
+	// ------------- Optional query parameter "second" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "second", ctx.QueryParams(), &params.Second)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter second: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetLogProofConsistency(ctx, params)
+	return err
+}
+
+// GetLogProofInclusion converts echo context to params.
+func (w *ServerInterfaceWrapper) GetLogProofInclusion(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetLogProofInclusionParams
+	// ------------- Optional query parameter "hash" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "hash", ctx.QueryParams(), &params.Hash)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter hash: %s", err))
+	}
+
+	// ------------- Optional query parameter "serial" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "serial", ctx.QueryParams(), &params.Serial)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter serial: %s", err))
+	}
+
+	// ------------- Optional query parameter "tree_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tree_size", ctx.QueryParams(), &params.TreeSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tree_size: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.DeleteCharactersId(ctx, id)
+	err = w.Handler.GetLogProofInclusion(ctx, params)
+	return err
+}
+
+// GetLogSth converts echo context to params.
+func (w *ServerInterfaceWrapper) GetLogSth(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetLogSth(ctx)
 	return err
 }
 
@@ -209,6 +2229,10 @@ func (w *ServerInterfaceWrapper) DeleteCharactersId(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) DeleteMovies(ctx echo.Context) error {
 	var err error
 
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
 	// Parameter object where we will unmarshal all parameters from the context
 	var params DeleteMoviesParams
 	// ------------- Required query parameter "id" -------------
@@ -218,6 +2242,25 @@ func (w *ServerInterfaceWrapper) DeleteMovies(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = IfMatch
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter If-Match is required, but not found"))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.DeleteMovies(ctx, params)
 	return err
@@ -236,6 +2279,10 @@ func (w *ServerInterfaceWrapper) GetMovies(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) PostMovies(ctx echo.Context) error {
 	var err error
 
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.PostMovies(ctx)
 	return err
@@ -259,6 +2306,250 @@ func (w *ServerInterfaceWrapper) GetMoviesByCharacter(ctx echo.Context) error {
 	return err
 }
 
+// GetMoviesId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetMoviesId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetMoviesIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-None-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-None-Match", runtime.ParamLocationHeader, valueList[0], &IfNoneMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-None-Match: %s", err))
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetMoviesId(ctx, id, params)
+	return err
+}
+
+// PatchMoviesId converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchMoviesId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchMoviesIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = IfMatch
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter If-Match is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PatchMoviesId(ctx, id, params)
+	return err
+}
+
+// GetMoviesIdHistory converts echo context to params.
+func (w *ServerInterfaceWrapper) GetMoviesIdHistory(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetMoviesIdHistoryParams
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetMoviesIdHistory(ctx, id, params)
+	return err
+}
+
+// PostMoviesIdRestore converts echo context to params.
+func (w *ServerInterfaceWrapper) PostMoviesIdRestore(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostMoviesIdRestore(ctx, id)
+	return err
+}
+
+// GetStats converts echo context to params.
+func (w *ServerInterfaceWrapper) GetStats(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsParams
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetStats(ctx, params)
+	return err
+}
+
+// GetStatsEmptyMovies converts echo context to params.
+func (w *ServerInterfaceWrapper) GetStatsEmptyMovies(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsEmptyMoviesParams
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetStatsEmptyMovies(ctx, params)
+	return err
+}
+
+// GetStatsMoviesPerYear converts echo context to params.
+func (w *ServerInterfaceWrapper) GetStatsMoviesPerYear(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsMoviesPerYearParams
+	// ------------- Optional query parameter "by" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "by", ctx.QueryParams(), &params.By)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter by: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetStatsMoviesPerYear(ctx, params)
+	return err
+}
+
+// GetStatsOrphanCharacters converts echo context to params.
+func (w *ServerInterfaceWrapper) GetStatsOrphanCharacters(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsOrphanCharactersParams
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetStatsOrphanCharacters(ctx, params)
+	return err
+}
+
+// GetStatsTopCharacters converts echo context to params.
+func (w *ServerInterfaceWrapper) GetStatsTopCharacters(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsTopCharactersParams
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetStatsTopCharacters(ctx, params)
+	return err
+}
+
+// GetTrash converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTrash(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{})
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetTrash(ctx)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -287,36 +2578,212 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/admin/api-keys", wrapper.GetAdminApiKeys)
+	router.POST(baseURL+"/admin/api-keys", wrapper.PostAdminApiKeys)
+	router.DELETE(baseURL+"/admin/api-keys/:id", wrapper.DeleteAdminApiKeysId)
+	router.GET(baseURL+"/admin/webhooks", wrapper.GetAdminWebhooks)
+	router.POST(baseURL+"/admin/webhooks", wrapper.PostAdminWebhooks)
+	router.DELETE(baseURL+"/admin/webhooks/:id", wrapper.DeleteAdminWebhooksId)
+	router.GET(baseURL+"/admin/webhooks/:id", wrapper.GetAdminWebhooksId)
+	router.GET(baseURL+"/admin/webhooks/:id/dead-letters", wrapper.GetAdminWebhooksIdDeadLetters)
+	router.POST(baseURL+"/admin/webhooks/:id/dead-letters/:delivery_id/redeliver", wrapper.PostAdminWebhooksIdDeadLettersDeliveryIdRedeliver)
+	router.GET(baseURL+"/admin/webhooks/:id/deliveries", wrapper.GetAdminWebhooksIdDeliveries)
+	router.DELETE(baseURL+"/appearances", wrapper.DeleteAppearances)
 	router.POST(baseURL+"/appearances", wrapper.PostAppearances)
+	router.GET(baseURL+"/audit", wrapper.GetAudit)
+	router.POST(baseURL+"/batch", wrapper.PostBatch)
 	router.GET(baseURL+"/certificates", wrapper.GetCertificates)
+	router.POST(baseURL+"/certificates/csr", wrapper.PostCertificatesCsr)
 	router.GET(baseURL+"/characters", wrapper.GetCharacters)
 	router.POST(baseURL+"/characters", wrapper.PostCharacters)
 	router.PUT(baseURL+"/characters", wrapper.PutCharacters)
 	router.GET(baseURL+"/characters/by-movie", wrapper.GetCharactersByMovie)
 	router.DELETE(baseURL+"/characters/:id", wrapper.DeleteCharactersId)
+	router.GET(baseURL+"/characters/:id", wrapper.GetCharactersId)
+	router.GET(baseURL+"/characters/:id/co-stars", wrapper.GetCharactersIdCoStars)
+	router.GET(baseURL+"/characters/:id/history", wrapper.GetCharactersIdHistory)
+	router.GET(baseURL+"/characters/:id/path", wrapper.GetCharactersIdPath)
+	router.POST(baseURL+"/characters/:id/restore", wrapper.PostCharactersIdRestore)
+	router.GET(baseURL+"/characters/:id/timeline", wrapper.GetCharactersIdTimeline)
+	router.GET(baseURL+"/events", wrapper.GetEvents)
+	router.GET(baseURL+"/events/ws", wrapper.GetEventsWs)
+	router.GET(baseURL+"/export", wrapper.GetExport)
+	router.GET(baseURL+"/franchises", wrapper.GetFranchises)
+	router.POST(baseURL+"/franchises", wrapper.PostFranchises)
+	router.DELETE(baseURL+"/franchises/:id", wrapper.DeleteFranchisesId)
+	router.GET(baseURL+"/franchises/:id", wrapper.GetFranchisesId)
+	router.PUT(baseURL+"/franchises/:id", wrapper.PutFranchisesId)
+	router.DELETE(baseURL+"/franchises/:id/characters", wrapper.DeleteFranchisesIdCharacters)
+	router.GET(baseURL+"/franchises/:id/characters", wrapper.GetFranchisesIdCharacters)
+	router.POST(baseURL+"/franchises/:id/characters", wrapper.PostFranchisesIdCharacters)
+	router.GET(baseURL+"/graph/components", wrapper.GetGraphComponents)
+	router.GET(baseURL+"/graphql", wrapper.GetGraphql)
+	router.POST(baseURL+"/graphql", wrapper.PostGraphql)
+	router.POST(baseURL+"/import", wrapper.PostImport)
+	router.GET(baseURL+"/log/proof/consistency", wrapper.GetLogProofConsistency)
+	router.GET(baseURL+"/log/proof/inclusion", wrapper.GetLogProofInclusion)
+	router.GET(baseURL+"/log/sth", wrapper.GetLogSth)
 	router.DELETE(baseURL+"/movies", wrapper.DeleteMovies)
 	router.GET(baseURL+"/movies", wrapper.GetMovies)
 	router.POST(baseURL+"/movies", wrapper.PostMovies)
 	router.GET(baseURL+"/movies/by-character", wrapper.GetMoviesByCharacter)
+	router.GET(baseURL+"/movies/:id", wrapper.GetMoviesId)
+	router.PATCH(baseURL+"/movies/:id", wrapper.PatchMoviesId)
+	router.GET(baseURL+"/movies/:id/history", wrapper.GetMoviesIdHistory)
+	router.POST(baseURL+"/movies/:id/restore", wrapper.PostMoviesIdRestore)
+	router.GET(baseURL+"/stats", wrapper.GetStats)
+	router.GET(baseURL+"/stats/empty-movies", wrapper.GetStatsEmptyMovies)
+	router.GET(baseURL+"/stats/movies-per-year", wrapper.GetStatsMoviesPerYear)
+	router.GET(baseURL+"/stats/orphan-characters", wrapper.GetStatsOrphanCharacters)
+	router.GET(baseURL+"/stats/top-characters", wrapper.GetStatsTopCharacters)
+	router.GET(baseURL+"/trash", wrapper.GetTrash)
 
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/8xWXW/bIBT9K+huj26crnvKW5pJVaVF6sueqqoi5qalsoEC7mRF/u8TkNg4/kjaddKe",
-	"bMMFzjnce653kMlCSYHCGljswGTPWFD/ulQKqaYiQ/eltFSoLUc/lz1TTTOL+pEz920rhbAAYzUXT1An",
-	"UMg3jsOTdQIaX0uukcHivrtVtPAhOSyUmxfMrNt15RBseUbtAKYRJNyYEtkjtW52K3Xh3oBRixeWFwjJ",
-	"6JJNNbWhlYOzYWAHKMrC0VstIYG1IwUJrA5cI3YjsngxfEh8YowtpjYoVnNYTyqGJtNcWS7F+O0Nzgha",
-	"4Ok79VFDmNaHjbt4NOZIDT5WSHW0OxcWn1B7WbnNzzg4hCXdDftA3DIutuEGw84BGmlEI8u7W0jgDbXx",
-	"KsHlbD6bOyhSoaCKwwKuZvPZFSSgqH32PFLa1Iz/VtL4tHNcqVP7lsEC7qSxyygwUEBjryXzKZdJYVH4",
-	"lVSp3OU7lyJ9MeG6Qo26t68at7CAL2lbxOm+gtOofOuuTFaX6AeMksIEpN/ml73EiByAUMaQeblNWRRU",
-	"V26aMUJJU7+kJU+4IJSELHJr0qwtXH/cEw7ocoN2Fcf1IM7fJQ63WJhTKsWO0lQvUK1pFWQ7UoTk3Fgi",
-	"t6TDqKvLTxdC87wfkzZiTavQRg1rMAqqXTgGKYpIJvLzCMLnp2frTh/NzrZUM43U9tJz5UcJJQJ/t7w9",
-	"7XKIdfnfkZ5PkS4VGyD9y4/GZXmceOmmumj8/XQGXleH5qWopgWG3L3fAXdwXkvUrhOFthDZb0wsidQ5",
-	"tu+Hd5E2RKMttejRvkEbpTbZVMF8SAB0rMCOszqckmP4k+gK8MOPt6feshH6zvhb9r5l/w3171P3HcAe",
-	"Ew9Q+/ft6ZvTJNch7qzb/Rf8Qtc9wW3fSJLRdG1YvMcs9xKNGOVhdsoko2M/3yvW++75MXMMup5hjFGT",
-	"DpSdP2Txr+O06NdVa2pnZZF/fLpFBDBT9hDYOWto/1g8lrqu6z8BAAD//3oExD4HDQAA",
+	"H4sIAAAAAAAC/+x9aXPcuJLgX0FwNmIjdqjDfn49+9yfZB1tTcuyWpLHr6NfhwJFZlVhxAJoAFSpxqH/",
+	"voGLBEnwKB0luVefpCJBIJFIJPJC5vcoYYucUaBSRO+/R3PAKXD97+Elnqm/KYiEk1wSRqP30W8Fk5Ci",
+	"G+CCMIrYFMk5IA6CFTyBKI5EMocFVh/KVQ7R+0hITugsuru7i6Mcc7wAaUfYK1IiT8iCSPWLqO6/FcBX",
+	"URxRvFDfZvql32kKU1xkMnr/Znc3jhb4liyKhf6lfhJqf8ZudEIlzIBHavTj6Scsk3l7UmqqbipuZur/",
+	"ZI7pDBARaIIFpIjRGDGO/g+aMo4wXbnGUWygN9irwD+ebpkR44jDt4JwSKP3khfQhyYF5ymj0AOrMNBl",
+	"BKhEcyxQgpM5pD1gqA5LWPrGvpBYiiPGF7hzUabmrd/P/+Iwjd5H/7ZTUdOOeSt2ziFnXNouNRVwEDmj",
+	"AjQRfMDpL1jCEq/Ur4RRCVQPjfM8IwlW097JOZtksPj3/xYKB99HjnxmvjKD1rH4JReSA14gAfyGJICm",
+	"mGSQRnexAugcvhUg5CYBOqY3OCMp4mboGOmfejBkBxMoI0LqpWfTKdCU0BmaEshSoeA+YnxC0hToJsG+",
+	"VHSIswz4/xaIswzU/rCEmQCXZKqGBrTAK0SZRAtM8QzqPOMujk6ZPGIFTTcJ+rkdX8M11aMbSD6xlEwJ",
+	"pO29Z2artlrJJohAScG5AjgOcc8QcLbZjm6jITvjkDCaEjXOkaHETdKeZVMoZSA0OtRONzzGzM1NN9Kw",
+	"mo42COAh54wj82wCKcICnR/to//4v7v/4TYHSkFikumd8IXiQs4ZJ/+zWTzuc0iBSoIzgTAHtCBCqD3K",
+	"OCJme2sWa3vSJ2BOfgXN+HLOcrVfDFNMOGAJ6ZVhwpbhvo9SLGFLkgVEcZN1xxFJa22LgqShZoaJf2+/",
+	"yDlMyW2b6E8Aa04zh1uUkhmRwh2VFx/3tt7+/Sd1As3ds2tYIcmQhCxT/wuEc8wlWhI5Z4VEHG4AZ6o7",
+	"OYfFv2gIQsVGBg8V1ebuzj9X/4j0hPX8bCflpGIfo3+WY7LJf0Mi1Zh7eQ6YY5pAYDHmmONEAr8aieIF",
+	"uyEwrnFjBrWhvI6CICvpaV9LKG2Y8VQCV//QIsvwJAMjd9zF0QSmjEPgVQMU2y62XXVCcEglD1AwTiTj",
+	"bWK6KPTXjlrM0REjTBldLVghSkJJqr0UQnFKplM9TGqYJs7OasP30Y6PuPYe1s9Te66iyQpZgmrNXkEn",
+	"V3aZ2+eEXjs1T0wRrqgrHiYf27F5/j0CqiTaPwwxRHFFI2ptah2rf+ZEQPRnoNcGORIqf3oXtcXkOFJo",
+	"xGYiAS7BIdP7qGvaJXT3mrrmbSM5Xmjz25aG+Pyp1LHqL56lpU76PmGzNnUDldz+SyQsxpGc2Sl35UCY",
+	"c7xqzcN1HQLogzqlP/vr08+p6suzR9HxgTqM/qVh/VfUfYrUP/xkSJl7iysZKnK1OEgfzBlIULt4xACG",
+	"jNuMQWKOvmIuUDKH5FppOFiii697Z8fomrKlQBhZHo78HdDLfO8xf3c8Lgg9ATqTc1+ZrJqx3N+aBrAr",
+	"t0MNZsqfBj3lT9vYn4T9wH9kP/IfZYReX9X2U0Gbz0I7X9NlExmneAFGjVy0V9ehGi3wNYhYq7tq23NU",
+	"7igRxUM44pABFnC1AswtSq12/o/d3bK9x3kkkdkY5DthtDWp/zIvFCE2qbOuxO8icU1yq0YreoviNmss",
+	"4d0NWhP8Tcvy7v3qKZP13erhciwfaTCAO20BOTZfvrEWEPdzgM94o/eAbhT1NuwcRJHJNQE/1x8NckDX",
+	"dx9Yqp+2zFET4nr5cdXyzj9Rw0eaZTuMW6oKcqAKxg4O19GVYwutbgyL6drNredCYlmIIGOVhaH088OL",
+	"y2oHI0zFErgRuaIxJF4OElqZfSxxxmbnkDCe9hxNbQh/hZVCiOLNU2d265YhWhO/hlW7T8PecIi5KWam",
+	"Rql6LRWaKclgjTOrBXinzNd5zLSETwtkXejs5qmhg9q2QapNjBSzRY5/R71sN9SZeRmA5FHF0wat6bdB",
+	"IqtMSm0SM2d+W6IRolhXlTafTFZ9HUoWfNvEy/5eFBtcRnG1wMM4qARkf0QfNn9qA8i6IDNK6KzzKEpE",
+	"gJrODj8hoAlLIUVnv+5f/NubXWeeHD78QwKY2SiYIrglQiojQHt7jtESukU8Q7PLORNQMz8KMqNagiw3",
+	"2P7F+fBQoSVRqApi22dwdezWYAyQTLkzgnM6IfTa8G8Ky7oIrh6WH/+MfBnatjKGKC3iKLvZDBMqrFg9",
+	"GtNBmEfJyQ386W96cbfPChogz+OD8XYX4YHrMbkOs1cDwuODyoJke+uF95SlcG9w1wWpF5IzLOc9p26b",
+	"DqMjzhb21ONCtgkrw3qfjxLv6ghpCXhxlMKMQ2A3RKfFYmIOeoNvZF1vOQ4KJf4id9lcsEREe8VqsoQw",
+	"zzFNEfn3N2MnphlKeFKNdXIzjH2kj6OiS7KAjNAAJZWbe7yk7To7cp8OQu4NEgSTKb4yINGtRRx9Syjm",
+	"mDupWMTG2ZWlIKQh00dYtzgyY/TRYg2KYdnYP71s5/0rr1EqAjhlV8K9GbfxzOIMLXHZbxgaKoiQQJPV",
+	"GWdsGgKrbFGDrGRxk5XsERIrzJs1DDJooT1goXdNcrV0YD+Ia9AF52eUri5fi9Uf+tVF/amBkoMMaFlA",
+	"tVvqn1t7Z8dbv8IqRkSiBFPlTZsA4iA5gRt3Bg+KGgqocrSeSX2FyZyx6/asugDVOovRVj5+2tvfuvi4",
+	"p1w4hKJ/btnOtpS0iGXBYd1JxNGygqcPoQ7s5rTd571TPwCcnoAMilpYSljkskMISCEjN8BXYx05cGPd",
+	"hn1zOdSNFHFrx+1aioY6Y6+Ac+Mq6ScJH3gHWlxNuNaZD0wIhYeLXK4+lWy4jsOKPT/yEdnDEQ8dphuS",
+	"M5a456iPfVMB91RupJ1WXgzPWh6QdbwQlcLXMjbrOV2uctBBQxwESLScA9V+YUiRXkIjoFOGMkZnwNGk",
+	"mE7BHCDj9UMNWidaL0Oa+rY1RbmDatvao8rfxmxa/eYgJDOAlUj3OqmeVR1Vz6rOqmdeh9XCbSuDdvNZ",
+	"QcunpaziDV09q4aunrmhQ5bxI+XkO3TbryF6qXdB1WcBQuDZCNHddFF9EFqhSky7rxrRJ+OXopdABoUt",
+	"rVEdWlhpzNrK0z7Zu0ZtnuxKr8RZtnCBhKNYRzn54+rrUOedMQueJ2BwX/eoejXgGwK8G6J38XqU//uL",
+	"yfdCqYfJLvHXbqagoWGQQmxnE8YywLRXEi43bW0i4/CoYHlwJEZfcMU4MMSQSr0emZf9Dgvs1Si9kPob",
+	"py3ZEpr6jJ9xMiMUZ1q8+lZAZgJk7H8iJ3SL1VzhjxHSUn4ZG3h6p3NCQtbJe2jBj6b9/sJxPt93AzwK",
+	"OQwaS55MABtrmahPOqin+u9GQdlA5LDGWo7QCeFvJx1nN9xKoMJ5VsMxQiYMuzmtNCDKfdg7uPpycXh+",
+	"dXx69uUyRl9O975cfjw8vTze37s8PIjR0efzD8cHB4enMTr9fHl19PnL6UGMzs4P9z+fHhxfHn8+vTra",
+	"Oz5RTVVfv+xdHn7d+z1G+58/nZ0c/vP48verk+NPx5famXR6eXh+uncS3FctNGQsCXiQm5PKigUNK0TO",
+	"6hQ4K1tjtai0UwyKo9xaIx1Ig5pBj4Rkl3rYjX6KF4G4tgAzM/HsI+IMMCeqo0Eq6hjRzaExWTN871S7",
+	"3O5OH3oAMHGkVcQ1N67bagE6GL/Z2mhpwXa8yBmXHdvaUWtLbAHfgat0LO5CzJUzGgmJuRRha/JYWV6P",
+	"3S/KG9jNdYe+4IQO40T9JGm/T/nqihf+Ri6FsLXX1EdzSJhPRV9w5aBy0HT7lQ52F/9gbf3KGF9NWwVb",
+	"GrtXOKRCdPj7hOtW69mSKVOVG0iFOaCUr5DC3KBO4FBcDtjQBfw1LHEeJAWaZIXaEx0GVaziAa+aXHJ9",
+	"e2oGeHpFaAq3YZqRHOBKkP+BEXZVry//w9gHNjzXusbRKYZ2exdHSzc5E0QGQ7/OMpyAMmPWdJcYTZWX",
+	"683w0pc9WzHVQRea8ScHdzMm6kni3RqAmo8akSCdUD7IQVnC13rjpjhGx3YA9wN65m67LQj1uc2beGNY",
+	"DgMmzoD/DiEH2GTlK1caHsX6EpyG4zAT5dwez6PVoMYfPiQ96VgQ23sIwaew7HJ+jAx3vfeVDP82Rgdo",
+	"PRawpgWkwwws1L5n3Nx5fBLD06NEOZzCstNdY2zBo2mjsusGgC54ti7M6pMQyJ95Psf0kc0hA/rvepYQ",
+	"7zZaM+hG3QcLcq9KWGqcIcC3tM3Wv3ypHCkF1wf+ONKqbMpdZlIboNoTwtnDxUb6H76cHyMOU+Cg3CFE",
+	"36WZruzFq/LSnPMfDEfjVXy8Jwa0dtXXv6wd6Tt2ccks7c9E3AQ55bllN675DYGl3tyQEnOzA6cLQoPf",
+	"KuclpL2Bgkn9ZS+tek2V/GEobcDO6H1jPgnhysB5yQE+Ag4EzHLG5JW6WTdKMBTOZdumhPOLPRu+h27e",
+	"bP+9vLVXfqLkbkUU+3v69h67sZ4zdcXyp3/89BZJDoDUpdUoHoZEkgUIiRd5QFonWUaM214gQRRhqnG+",
+	"UHKLIGfJ3O+/xz23hlDry7EVZLGHXR93wXWSWIqLYrHAwWtuQ1odvgGOZ3CVYBHwxn8CTBEtw048XSgH",
+	"XoaGV35IVkx8K7z5cozyCMrhezWgRpV373ydpy8Cq/2O6QPjaqRPymBPMSVClQ+0EQvftahjlLMa3hvz",
+	"D4EZWvp2KNV9JekfwpVznyDJ8T6eS5Y/lRwxTkoeWmxuee2D4dI9Qdrj7FnbxG+7NIrnyDCLeNSMfTjv",
+	"S90NJtgIhChfahe0IkDr+UcT1YG6lpVcV93Ww3Vg3VvwwxHLT+VEdt3U4K4jp2cVOmwKP+AKdEuq6yD6",
+	"ATaGey9Fp3Z2n3QMlUbXyGWhnmvBWyAb0qUsl/qAMnmFsmysrtGrBo6McbPa4ohgI9WynNdgRgWLzAMz",
+	"xVVnqF5vpN6aOE8L4wm6WoiRRFbG3jUlVZO5Q62HKJIEhJgWmVuvWkxwY8WvRkeZmeZOexu9zKErIheK",
+	"pHT0qQvhdGg3EdQCL0DPBdQz5DA/4gKIUfauwo5RhyUd1Gb9LaCGRSlJdR4Zc8VwmI9WIY1edKO7q1+B",
+	"669vg0ZCJFhZ0HpiHLsZT9vaZG7U2fuD5tqEeuS8G9b2Nzhby6U6HfAmzrjgRK4uFAk4VcOZ8IJJvsrI",
+	"42p8XIYtTwBz4HuF8TeYX05Nj/7z62XU9Nl8vFAqomTXQLVagEQxiRHc5tpng02qpyTDZOHygGlRUndc",
+	"ATCXMjeJcgidmuty5nCwF7Wq+47mPlJ5QERvtne3d202CopzEr2P/ra9u/23yPiWNUJ2tBFgB+dkS2Wa",
+	"UY9mJuS5dAkfp9H76BeQe6qlsYGKqJGA7O3ubk+SoHZyoHEJH0rUN0S1dm4jvSWvVcS407+IFMjGP9/F",
+	"0bvdN12jlfPYqaU80h/9bfijKmPYXVzZaoa+qhIgVWQavf+jItA//ryLv9dI7o8/7/6MI+GU6EiF+SCd",
+	"2cGsx+2WMTBbs45x9ARW8oyJ9lLqsIAPLF2ttYp9i1fZy+/qW9em0GmQz5tHG7h+UyFALCp6v3R2as6u",
+	"yUQlN2A0WyEOsuAUUjQHDoYOdodX1Mt499ekN4NXdfPUEl2A5u7iJkPZ+U7SO3MQZCChTY4H+rlPkMdp",
+	"VE9y+Yfl1vY6m+XV+ozrzgg5FGD3Z4sE34UvenC4YdeQbnBV3+2+G/6izPa3YTI41+gYSwb2LsrwufLV",
+	"NdzEwVLenBl7sthp/P92uiyrRVn7dKkt6JMcL9X9p+c4X2qj12nGvmocMiZ7wOthM4r8LoqJyVOJMPpy",
+	"fqKMsDaPcKk6DzOddc4eR64v6PBxZGTNLzH6VkABqadA62tPKWd5/no+Ocoxa6os9OU1yDbvGnUUPRcp",
+	"7D4aq+rhUUoTX1bH4Cvl6PXvJZsOBrOTAk63MpDOvzGSuKobuOJHoLNRglU1pzGylWqNLOLieoaCV5LU",
+	"JHlob7c6qkQJKzJjFZxAZfm+J7HufPeuQ9/tcLA/1dRGSnc1Mnb20uP0vOxqA5QdBzut3/R+zH3zNlTQ",
+	"QZ/MU5MpkRidQVn8MJpyEHMkQKfrLS+av9I3X5UZH1BaMYIyK8J4gi7dCevw3qzyQfw1WG/TVTSC/37J",
+	"lVz9Zne3ItpXAm2owViqQ6mFH72ZK7MAhaWfXSdIu03vcq9K4jUOE2ijnIh3QfThjLTRdyOf+hNrPdXU",
+	"EYcFu3Fk9VK14hdurFMYrOXsq+jQXOvAZcBYSbU2QLTfxlOj0Kew8FQjjLPw9NMSTtNXSnoAJe2laRcZ",
+	"EdpHRJr3qctPvcezbjCK07m09D31l5pkANuzbdTKraJc0CYrS17wWVX4qTGen/6+d8zQx/V8+dXnj5Tv",
+	"tUXvmWBooS4B2bTkgfS8wWyhPbCTtAb5Pc8QHagc7qi3OkG4t4JKkt2rtzq+PisLrK0V4OT1jC2BI6Ks",
+	"tgzlqs6SDewKAVLW9whA0sqAHqynFt661T7Y8eq7PamtqKzT0GEs0mSlDNgWX6+26kqJwTyZm/2mkIgk",
+	"xySzoeh05jIwZlgOyYgTd3/PnbsNcnWsSKibuOV9LbScqxvbTM6BuyHREhO5jfaqGgQrrYNixGFqykI0",
+	"axGgwsQ2u8IOqv9cXwg1+YaPD7bRVxWkhGn1lb7Zo8tdadogwgKQ6hAX/5YM1cnEiVTdmrtDPxuQl0SA",
+	"jamqup1jG2FZZHIbGaFYIApgOtU40/Ez2+gccCpQyrRVRE3bBAAijcwYCYYYBYUwasKsFK6IRAlbLJQX",
+	"z2AGkK6wxKbas2eRuK1LK7Vlnw9eOcLHlnpq1Q5GyT27jz226b3bGVqtko7qg7+eYPXuzdsxnKNVbu4u",
+	"jv6++3YUFly5xk0rBAVFQm02nJWsAgu9QyTHVODEijpBOc67lNVrbdn3223C+NG4YTZk+NgzRSAVh/Yh",
+	"vfda1L3mOMsa/TZxt2NTxYfZvAnr89ur9KHagewJ4P57zeWcg9nVa9i/OEeWd4gG04xtAKJpOANpKMDr",
+	"8r3m1AJdQ66DYuW89rouTKrOzGmyf3FunJIcpoWAVJ9A4UKW1vuteUF9sq62RFtS1eeHqX7ZyZx92tsX",
+	"/InYdHdZgA1HI7RvaoZqKnq4N+UOPIa96eKsii51RLgpYrcgQouWmz8PNjXxQOWvWrXUd7v/2CQ4p8zQ",
+	"QI2FIHyDic54FKPa9sMZB5yutEDGKGxctlZMD7fKUCg+53iHkfaCam34EKtdRus8wqpW4QOs80SpPtyc",
+	"TSdwANXA6LbjNeb5BJyyusE3mjN2FfSp0tXeu0jwy5VSX7jk6MJ/6wVUOszGRYjaijqxjbD1PdyfMWBc",
+	"ccXsjWXl2Yn/XR/xV3mZ/4LEv0kVbZO75oteNIT7d0z9WNqZrLbKG7jD59OHlSuMNWJHVYmvujbVSOd1",
+	"ZzoCF9D6HKefiuCqp8JbePXXmmgeFx1aTW0z8YDrMq3xDMQlcX9lBM/ACMqw1CAjGAxMfYlUeMoodFLi",
+	"7kBl64ccY38bSSOfWEqm5AEUv3nu5dNHiF/tJGyrLG40hlhclaQNxuE1jpxM+678jko8mWq7+Nb6x3Z3",
+	"dwfcZU/pAHOY6vB/lYj/MYhJK4KN2m1ibvIEOW3ZejzLFrHV+4Ws1+5yMbkhepwTIRlfjSXHj7b5i2Bh",
+	"L8W1anHYqtz7Gq7ysOtrVeUiG6/nha+oCoZS+AES/V7aDlFd7wGXgnjMBjgzJP5czFiyh/batG0K6biE",
+	"qzipzfszlTWLs2I27widWODbqxRyPeXA0fCTfzC8fc5joVYctGMHiznjmm70Gv4YJ8QRsR76Evhkjglt",
+	"VW40YYLaP+RXN8VUe+/DO8KmRuq/SOBvi3P7wfNFY3dpTV6FrVe7y6ajVzXq9QUBrbp6FKhllwYH74iq",
+	"aRKn9CrFjmHZZWXZH/w2YLtUbgc3cwh6PIHkmWTfqixR8/zX5KOa+CkXlXfZkJNAhMZVQmxNRlUuslmo",
+	"JqiJjdGNUIK5DilU9KkiCQXTwUwJoxQSXbNdX+y6YAXXYe6iWIDwyi1mWPmSqI5TEni5jdSqFHkOHE2I",
+	"vXxAEUnRnGXKj58D38o5S0AIkwPWDKmbaO5t4hQRRgL4jWVqmEvj+se2rKJkTPnrzQ8zkQVgag52W26x",
+	"jBkJ1lssg76sz1/M9XU1DhnDachh/wvIQ3efO7S3mvmZTrCQW/qLLZ2ybkzY5e69BAYJt9Is+ZaQHPCi",
+	"vs+a+7YdKkXTTC2H+bgKBnT31++7k37apKtYizZmAlUBW6EjDh5hm17Yni1JSVYWrPbrjdPU7kkTxYyF",
+	"peEtoSjMYbPaoDvLXtuIIbevAYJrJHzWm7Lck0SYoWIFQI0KUcpszMpOmZogaAbRhWWr7GyPRbpvQi7T",
+	"iyWx0bo2e+1XmFyw5BqkiseULGHZPSnwMde8LN4q0H9efD71gLQVgtzC3rpqQEHOe64LFAkV0VkVL9E4",
+	"RTtE1+hBHHAq4o6KOdewgtTm7yYcHR847mnGRaYPdSRUYq9jpNiEtIou5mYgH+WasWQQ1IY6Ur/TtDcH",
+	"/GYuHu5jiTM2M6sQzKvrD3K7RdP2QIE6Ior/qmmtx3Y/VSXvvWA596AuK3ri/L7ByNYBEX51nJ6Bn2X3",
+	"HLAlVUepDZdulW+uM0sX2FmWdpdIUJyLObO2vHpJyC6OeVS1ekLhtF69suMs8gB+xFCZqtdYcQG9HXtj",
+	"ZhoYeZLkRkdV7c3NBhQ2Bq4vQvnyrx2K86K14jIQp8pIz6ZGUSltNl2Wy/KTsd7vitR/TO93RbCv3u+X",
+	"4P32ispdA+T6zpAUYcFsLQf5SyTUYQf503Ps2rH56nTvcLpP/UrXXdGLL50XvgQ5ZHfTcshrVOQPeByc",
+	"g7lYqjR1tVeMO9a3xLJpbVeOE2d2mhWTxko2g3HJm3DNbjbNihdXTG39oVeR/N5RvhqFNQeDza3SIe90",
+	"OK2qCPoxYs5mqXYjEow3pa4wrLJFWa7cP7t/0MisOrvr8E7JOaws5aChFMzPTCePL4q0SUSVZ3uEaxWv",
+	"zO/BoVYN1idZjZhr5fKCx/iM43zuwddnjfxFtd2vmj4hV2oO1RkYqr26yjnqtX24pyTPSItPKDmprNYs",
+	"GZpxVuTC0rALuAoFtfGZl1u1xPm3bBDV37JonK90LhfZmsZ6hT09CPntRKfaeQx9zvYXa/c33ZpwthSg",
+	"vDqH5cVy3ea3EwQ0zRmh0melTdcSTo2zOy8mGUlitCikTZdSJiPRZXzsYXR+eHHpJcjQLHtBOGd8Gx3Z",
+	"IsgqsCDJilQJA0oG1JaPicrTplebLfIMbolcIR05HevhyzTxWFTgq27LvEUJSwHBrQQqCKMmZMBdkZX4",
+	"GoyfH6emHtHPzoVgp4NMyWasEki51ktOJJjmLmGAipBGXzEX1v5bsVGMLr6qghC6edfdfJ+iHv+IsHh5",
+	"puQp5ejd6VMuPdozqW2exwmrkoDgEhKthuhkaI4WdPHiYV+8Yecq35Ym1Oh9pOjLsBfjM+3OcWGiZbj2",
+	"Gao8E9g5s/yDRF3s9p122+hT0IGr4k0TzFV6TlMDqqDkW2HEKOcPJupmu8575M1ApzySzOigcg6Ln+sD",
+	"6p71ntAtMDKhgIHL5spxpAR7lSRJp1ZX550e1eREgkQxaBeGQ1dVuiRXI0LHCOkP9Ia2m9u8rhIsqZnb",
+	"oJ13b9/qS+e2HPlCgZARCl2b73gR9kUHUqJpeEu86UG5rkqOlgqDRKKlDuwx0HUEPKR8dcULGnZoT3Em",
+	"oF099yHSY0PA873SyBSFq5WfbxW6u4fbuok6QPVhcyNfhqvDhz3czbQCxlyFOFsqGlRrXR9C823n39aJ",
+	"rqqhOVsGRt4oVzREZ0rad7FEbuM3lsCh2g+MWyKbAGKKX6WKYRT0hUvqb99uFHd6f6rcHIZfxCWjWOKS",
+	"t2xaITBwjwnmskYSFfwTo9MD9Vet+/7Ff+mJdekLGZupWDc23SlDG5LeG0gnbHam2u97zcfF5NhrIIPX",
+	"pWsZHZtVDjTqhQvFSgrOdfVaDoBsVf3Q2KbGf9Q72NPehyuRpZEXTCxUtUG5afRseYVKfIrHu4vZnB6a",
+	"gFwCUCSXrDZgnSy1fO+qNA8R5XHZeOBo/oAF/PQOAVWivsqXj6dq68/LMG0vCVSYpFTr9VLnXgAnOEO0",
+	"WEyAjx9I6M/WG+rSoVNtlJzrvNWqFoCQMUo79lDP9pEc4Mq+f6YdVK5s5/4pWzz77jGExD2utPlEWX6a",
+	"MhVsbGX3jM0ea0dTROoYt9mjMjZTOUuTWho1u6VF/+W+Eza7kPOntEOZFG9qe3w0+lUbceV2UC3NGs51",
+	"20dBmytf53cd4AUmg2SOueaXetUUDqsq0f0OOaPbvcjEQEPWZA36a4TRi4gwaiWiHwwdKglvnTRvlqqf",
+	"M8VbBUK3H8ib2+Mb3HTn907rZjbNXzqO9Nnys/VWY9AvdZap6mJfzwFnSOjDqsplNopF6z+PnmjKGgCf",
+	"O8mU9cFMVn5OTzVhH8EuwrYfsT92Ph9HaK9hhSH90WBHMWiX2L/BodXjl0YGTxtSqGd7ZoYYbYoMHRyv",
+	"gX8/oJS2b+79ybnNyac0Tg4ZYAFoBZibQJgxp9fY5ENud70mHhpOPFSyq9dImEdMOlTlyL93wiGf5kel",
+	"VnFU/9LSqhjW/ZpS5QWlVPFSwY1LpyIk7g/RutANWiQ3wBP1V0eGyJ6UKeqBLixGHnDVWMd+GV8smpJZ",
+	"we9dE+uBsuY+K6isGHivy83wHHwDXFU2S7AyJdDUuvV1mEEZEFmu9Q4scmlTEg8v/KFq3GXLeyk04AP5",
+	"QBKwqqDaQKyQrfT/myaG8gzqhMuQAGCekUZMoFlt8+GWyimjRLLBBTdoPAP+u2o9yjYwWXWkOViZLlya",
+	"A/szhQSnXRUQXwpJ1dHw4BQGOoDDiMQcWQS8CO6ijB51kd3BN0RYjOdzTLfG1d/Q6/ZZf9EXv/5SVr8F",
+	"6UMPloqDux3sW5yfjav4J4s+VXQYG1W5oGwAX5lBoVp4yfJ1Vv2S5esWiujNMbxeiuEXxFLqiHg0ivLS",
+	"/Nbz+75IymrAauhKcix6vbKXusFTro0eoAPXGryaeqelbRNnzf+yLohyLWu6RStcV0fh6op2kmQZmtQU",
+	"wrb+e3f3/wYAafUGS2DlAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,190 @@
+// Package audit keeps an append-only trail of every change to movies,
+// characters, appearances and franchises: who made it, when, and what it
+// changed.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"time"
+
+	"example.com/go_basics/go/auth"
+	"github.com/google/uuid"
+)
+
+// Anonymous is the actor of changes made without credentials.
+const Anonymous = "anonymous"
+
+// Entity types.
+const (
+	Movie      = "movie"
+	Character  = "character"
+	Appearance = "appearance"
+	Franchise  = "franchise"
+)
+
+// Entry is one change. Appearances are entered under their movie, with the
+// character as RelatedID.
+type Entry struct {
+	ID         uint64     `json:"id"`
+	Time       time.Time  `json:"time"`
+	Actor      string     `json:"actor"`
+	Operation  string     `json:"operation"`
+	EntityType string     `json:"entity_type"`
+	EntityID   uuid.UUID  `json:"entity_id"`
+	RelatedID  *uuid.UUID `json:"related_id,omitempty"`
+	// Diff holds the fields that changed by their JSON name.
+	Diff map[string]Change `json:"diff"`
+}
+
+// Change is the value of a field before and after a change, null where
+// the entity did not exist.
+type Change struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// Concerns reports whether the entry is about the movie or character id.
+func (e Entry) Concerns(id uuid.UUID) bool {
+	return e.EntityID == id || (e.RelatedID != nil && *e.RelatedID == id)
+}
+
+type actorKey struct{}
+
+// WithActor names the actor of changes made outside a request, such as
+// the trash purge.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor returns who makes the changes of ctx: the actor set by WithActor,
+// the authenticated caller or Anonymous.
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok {
+		return actor
+	}
+	if p, ok := auth.PrincipalFrom(ctx); ok {
+		return p.Subject
+	}
+	return Anonymous
+}
+
+// NewEntry describes a change of an entity from before to after, either of
+// which is nil when the entity did not exist. It gets its ID once appended.
+func NewEntry(ctx context.Context, operation, entityType string, entityID uuid.UUID, before, after any) Entry {
+	return Entry{
+		Time:       time.Now().UTC(),
+		Actor:      Actor(ctx),
+		Operation:  operation,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Diff:       diff(fields(before), fields(after)),
+	}
+}
+
+// fields returns the JSON fields of v without the ID, which every entry
+// names already.
+func fields(v any) map[string]any {
+	m := map[string]any{}
+	if v == nil {
+		return m
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return m
+	}
+	_ = json.Unmarshal(data, &m)
+	delete(m, "ID")
+	return m
+}
+
+func diff(before, after map[string]any) map[string]Change {
+	changes := map[string]Change{}
+	for name, v := range before {
+		if w, ok := after[name]; !ok || !reflect.DeepEqual(v, w) {
+			changes[name] = Change{Before: v, After: w}
+		}
+	}
+	for name, v := range after {
+		if _, ok := before[name]; !ok {
+			changes[name] = Change{After: v}
+		}
+	}
+	return changes
+}
+
+// Log holds the entries in the order they were made. Entries are never
+// changed or removed.
+type Log struct {
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+func New() *Log {
+	return &Log{}
+}
+
+// Append numbers the entries and adds them to the log. It does nothing on a
+// nil log.
+func (l *Log) Append(entries ...Entry) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, e := range entries {
+		e.ID = uint64(len(l.entries)) + 1
+		l.entries = append(l.entries, e)
+	}
+}
+
+// Record appends the entry NewEntry describes.
+func (l *Log) Record(ctx context.Context, operation, entityType string, entityID uuid.UUID, before, after any) {
+	l.Append(NewEntry(ctx, operation, entityType, entityID, before, after))
+}
+
+// Filter selects entries. Zero fields match every entry.
+type Filter struct {
+	Actor      string
+	Operation  string
+	EntityType string
+	// EntityID also matches the appearances of a movie or character.
+	EntityID uuid.UUID
+	Since    time.Time
+	Until    time.Time
+	// Before only matches entries with a lower ID, to page back in time.
+	Before uint64
+	Limit  int
+}
+
+func (f Filter) match(e Entry) bool {
+	return (f.Actor == "" || e.Actor == f.Actor) &&
+		(f.Operation == "" || e.Operation == f.Operation) &&
+		(f.EntityType == "" || e.EntityType == f.EntityType) &&
+		(f.EntityID == uuid.Nil || e.Concerns(f.EntityID)) &&
+		(f.Since.IsZero() || !e.Time.Before(f.Since)) &&
+		(f.Until.IsZero() || e.Time.Before(f.Until)) &&
+		(f.Before == 0 || e.ID < f.Before)
+}
+
+// Find returns the matching entries, the latest first, at most Limit of
+// them when it is positive.
+func (l *Log) Find(f Filter) []Entry {
+	result := []Entry{}
+	if l == nil {
+		return result
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		if f.Limit > 0 && len(result) == f.Limit {
+			break
+		}
+		if f.match(l.entries[i]) {
+			result = append(result, l.entries[i])
+		}
+	}
+	return result
+}
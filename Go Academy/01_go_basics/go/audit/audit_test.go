@@ -0,0 +1,125 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/audit"
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/entity"
+	"example.com/go_basics/go/handlers"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/routes"
+	"example.com/go_basics/go/validation"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestEntries(t *testing.T) {
+	l := audit.New()
+	ctx := auth.WithPrincipal(t.Context(), auth.Principal{Subject: "alice", Role: auth.Editor})
+	donkey := entity.NewCharacter(entity.WithName("Donkey"))
+	renamed := donkey
+	renamed.Name, renamed.Version = "Donkey the Brave", 2
+	l.Record(t.Context(), "character.created", audit.Character, donkey.ID, nil, donkey)
+	l.Record(ctx, "character.updated", audit.Character, donkey.ID, donkey, renamed)
+	l.Record(audit.WithActor(ctx, "trash purge"), "movie.purged", audit.Movie, uuid.New(), nil, nil)
+
+	all := l.Find(audit.Filter{})
+	require.Len(t, all, 3)
+	assert.Equal(t, []uint64{3, 2, 1}, []uint64{all[0].ID, all[1].ID, all[2].ID})
+	assert.Equal(t, "trash purge", all[0].Actor)
+	assert.Equal(t, audit.Anonymous, all[2].Actor)
+	assert.Equal(t, map[string]audit.Change{
+		"name":    {Before: "Donkey", After: "Donkey the Brave"},
+		"version": {Before: float64(1), After: float64(2)},
+	}, all[1].Diff)
+	assert.Equal(t, audit.Change{After: "Donkey"}, all[2].Diff["name"])
+	assert.NotContains(t, all[2].Diff, "ID")
+
+	renames := l.Find(audit.Filter{Actor: "alice", Operation: "character.updated", EntityID: donkey.ID})
+	require.Len(t, renames, 1)
+	assert.Equal(t, uint64(2), renames[0].ID)
+	assert.Len(t, l.Find(audit.Filter{EntityType: audit.Character, Limit: 1}), 1)
+	assert.Len(t, l.Find(audit.Filter{Before: 2}), 1)
+	assert.Empty(t, l.Find(audit.Filter{Since: time.Now().Add(time.Minute)}))
+	assert.Len(t, l.Find(audit.Filter{Until: time.Now().Add(time.Minute)}), 3)
+
+	var none *audit.Log
+	none.Record(context.Background(), "movie.created", audit.Movie, uuid.New(), nil, nil)
+	assert.Empty(t, none.Find(audit.Filter{}))
+}
+
+func TestHistory(t *testing.T) {
+	cfg := config.Default()
+	cfg.Auth.JWTSecret = "0123456789abcdef0123456789abcdef"
+	keys := auth.NewKeyStore(cfg)
+	repo := repository.New(db.New(), nil, nil, audit.New())
+	e := routes.NewEchoRouter(handlers.New(repo, &pki.Authority{Dir: t.TempDir()}, nil, nil, keys, nil, nil), nil, zap.NewNop(), nil, nil, auth.New(cfg, keys), nil)
+	spec, err := api.GetSwagger()
+	require.NoError(t, err)
+	e.Use(validation.ResponseMiddleware(spec))
+	token := func(subject string, role auth.Role) string {
+		s, err := auth.NewJWT([]byte(cfg.Auth.JWTSecret), "").Sign(subject, role, time.Minute)
+		require.NoError(t, err)
+		return "Bearer " + s
+	}
+	call := func(method, target, body, authorization string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", authorization)
+		for i := 0; i < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	rec := call(http.MethodPost, "/appearances", `{"movie_id":"`+shrek.ID.String()+`","character_id":"`+donkey.ID.String()+`"}`, token("bob", auth.Editor))
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	rec = call(http.MethodPut, "/characters?id="+donkey.ID.String(), `{"name":"Donkey the Brave"}`, token("alice", auth.Editor), "If-Match", `"1"`)
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+	rec = call(http.MethodGet, "/characters/"+donkey.ID.String()+"/history", "", token("carol", auth.Editor))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var history api.AuditLog
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+	require.Len(t, history.Entries, 3)
+	renamed := history.Entries[0]
+	assert.Equal(t, "jwt:alice", renamed.Actor)
+	assert.Equal(t, "character.updated", renamed.Operation)
+	require.Contains(t, renamed.Diff, "name")
+	assert.Equal(t, "Donkey", *renamed.Diff["name"].Before)
+	assert.Equal(t, "Donkey the Brave", *renamed.Diff["name"].After)
+	assert.Equal(t, "jwt:bob", history.Entries[1].Actor)
+	assert.Equal(t, shrek.ID, history.Entries[1].EntityId)
+	assert.Equal(t, audit.Anonymous, history.Entries[2].Actor)
+
+	rec = call(http.MethodGet, "/movies/"+shrek.ID.String()+"/history?limit=1", "", token("carol", auth.Editor))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+	require.Len(t, history.Entries, 1)
+	assert.Equal(t, "appearance.linked", history.Entries[0].Operation)
+	assert.Equal(t, http.StatusNotFound, call(http.MethodGet, "/movies/"+uuid.NewString()+"/history", "", token("carol", auth.Editor)).Code)
+
+	assert.Equal(t, http.StatusForbidden, call(http.MethodGet, "/audit", "", token("carol", auth.Editor)).Code)
+	rec = call(http.MethodGet, "/audit?actor=jwt:alice&entity_type=character", "", token("root", auth.Admin))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+	require.Len(t, history.Entries, 1)
+	assert.Equal(t, donkey.ID, history.Entries[0].EntityId)
+}
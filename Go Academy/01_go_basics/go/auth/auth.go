@@ -0,0 +1,238 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/logging"
+	"example.com/go_basics/go/problem"
+	"example.com/go_basics/go/validation"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// Names of the security schemes in the API spec.
+const (
+	SchemeAPIKey = "apiKey"
+	SchemeBearer = "bearerAuth"
+)
+
+// RoleExtension names the minimum role of a secured operation in the spec.
+// Operations that declare none require the viewer role.
+const RoleExtension = "x-role"
+
+const principalKey = "auth_principal"
+
+// ErrNoCredentials is returned by an Authenticator when the request carries
+// no credentials for its scheme, so the next scheme may be tried.
+var ErrNoCredentials = errors.New("no credentials")
+
+// ErrForbidden is returned by Check when the caller's role is too low.
+var ErrForbidden = errors.New("forbidden")
+
+var errMissingCredentials = errors.New("credentials required: send an X-API-Key header or a bearer token")
+
+// Role grants access to operations. Each role includes the ones below it:
+// viewer < editor < admin.
+type Role string
+
+const (
+	Viewer Role = "viewer"
+	Editor Role = "editor"
+	Admin  Role = "admin"
+)
+
+var ranks = map[Role]int{Viewer: 1, Editor: 2, Admin: 3}
+
+func ParseRole(s string) (Role, error) {
+	r := Role(s)
+	if _, ok := ranks[r]; !ok {
+		return "", fmt.Errorf("unknown role %q", s)
+	}
+	return r, nil
+}
+
+// Includes reports whether r may do everything other may.
+func (r Role) Includes(other Role) bool {
+	return ranks[r] > 0 && ranks[r] >= ranks[other]
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject string
+	Role    Role
+	Scheme  string
+}
+
+// Authenticator verifies the credentials of one security scheme.
+type Authenticator interface {
+	Authenticate(r *http.Request) (Principal, error)
+}
+
+// Auth enforces the security requirements and roles the API spec declares
+// for each operation.
+type Auth struct {
+	schemes map[string]Authenticator
+}
+
+// New returns nil when authentication is disabled, which leaves every
+// operation open. API keys are always accepted, bearer tokens only once a
+// JWT secret is configured.
+func New(cfg *config.Config, keys *KeyStore) *Auth {
+	if !cfg.Auth.Enabled {
+		return nil
+	}
+	a := &Auth{schemes: map[string]Authenticator{SchemeAPIKey: keys}}
+	if cfg.Auth.JWTSecret != "" {
+		a.schemes[SchemeBearer] = NewJWT([]byte(cfg.Auth.JWTSecret), cfg.Auth.JWTIssuer)
+	}
+	return a
+}
+
+// Register adds or replaces the authenticator of a security scheme.
+func (a *Auth) Register(scheme string, authenticator Authenticator) {
+	a.schemes[scheme] = authenticator
+}
+
+// Middleware authenticates requests to operations with a security
+// requirement and checks the caller's role. Missing or invalid credentials
+// get a 401, a role that is too low a 403. Public operations identify the
+// callers that send credentials anyway, so their limits and logs follow
+// them. Routes missing from spec pass through.
+func (a *Auth) Middleware(spec *openapi3.T) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			op := validation.Operation(spec, c)
+			if op == nil {
+				return next(c)
+			}
+			requirements := spec.Security
+			if op.Security != nil {
+				requirements = *op.Security
+			}
+			public := len(requirements) == 0
+			if public {
+				requirements = a.anyScheme()
+			}
+
+			p, err := a.authenticate(c.Request(), requirements)
+			switch {
+			case public && errors.Is(err, errMissingCredentials):
+				return next(c)
+			case err != nil:
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, a.challenge())
+				return problem.New(http.StatusUnauthorized, err.Error())
+			}
+			if !public {
+				required, err := requiredRole(op)
+				if err != nil {
+					return err
+				}
+				if !p.Role.Includes(required) {
+					return problem.Newf(http.StatusForbidden, "The %s role is required, %s has %s", required, p.Subject, p.Role)
+				}
+			}
+
+			c.Set(principalKey, p)
+			req := c.Request()
+			logger := logging.FromContext(req.Context()).With(zap.String("subject", p.Subject))
+			ctx := WithPrincipal(logging.WithContext(req.Context(), logger), p)
+			c.SetRequest(req.WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// Check authenticates headers with any registered scheme and requires the
+// role, for callers outside the echo router such as the gRPC server.
+func (a *Auth) Check(header http.Header, role Role) (Principal, error) {
+	p, err := a.authenticate(&http.Request{Header: header}, a.anyScheme())
+	if err != nil {
+		return Principal{}, err
+	}
+	if !p.Role.Includes(role) {
+		return Principal{}, fmt.Errorf("%w: the %s role is required, %s has %s", ErrForbidden, role, p.Subject, p.Role)
+	}
+	return p, nil
+}
+
+// anyScheme accepts credentials of every registered scheme.
+func (a *Auth) anyScheme() openapi3.SecurityRequirements {
+	requirements := make(openapi3.SecurityRequirements, 0, len(a.schemes))
+	for scheme := range a.schemes {
+		requirements = append(requirements, openapi3.SecurityRequirement{scheme: nil})
+	}
+	return requirements
+}
+
+// authenticate accepts the first requirement whose schemes all succeed.
+func (a *Auth) authenticate(r *http.Request, requirements openapi3.SecurityRequirements) (Principal, error) {
+	for _, requirement := range requirements {
+		var p Principal
+		var err error
+		for scheme := range requirement {
+			authenticator, ok := a.schemes[scheme]
+			if !ok {
+				err = ErrNoCredentials
+				break
+			}
+			if p, err = authenticator.Authenticate(r); err != nil {
+				break
+			}
+			p.Scheme = scheme
+		}
+		switch {
+		case err == nil:
+			return p, nil
+		case !errors.Is(err, ErrNoCredentials):
+			return Principal{}, err
+		}
+	}
+	return Principal{}, errMissingCredentials
+}
+
+func (a *Auth) challenge() string {
+	challenges := []string{`ApiKey header="X-API-Key"`}
+	if _, ok := a.schemes[SchemeBearer]; ok {
+		challenges = append(challenges, "Bearer")
+	}
+	return strings.Join(challenges, ", ")
+}
+
+func requiredRole(op *openapi3.Operation) (Role, error) {
+	v, ok := op.Extensions[RoleExtension]
+	if !ok {
+		return Viewer, nil
+	}
+	s, _ := v.(string)
+	r, err := ParseRole(s)
+	if err != nil {
+		return "", fmt.Errorf("%s of operation %s: %w", RoleExtension, op.OperationID, err)
+	}
+	return r, nil
+}
+
+// FromContext returns the authenticated caller of the request.
+func FromContext(c echo.Context) (Principal, bool) {
+	p, ok := c.Get(principalKey).(Principal)
+	return p, ok
+}
+
+type principalContextKey struct{}
+
+// WithPrincipal carries the caller to code that only sees the context,
+// such as the repository.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, p)
+}
+
+// PrincipalFrom returns the caller WithPrincipal put into ctx.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalContextKey{}).(Principal)
+	return p, ok
+}
@@ -0,0 +1,142 @@
+package auth_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/handlers"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/problem"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/routes"
+	"example.com/go_basics/go/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	adminKey  = "mca_test-admin-key"
+	jwtSecret = "0123456789abcdef0123456789abcdef"
+)
+
+func newRouter(t *testing.T) http.Handler {
+	cfg := config.Default()
+	cfg.Auth.AdminKey = adminKey
+	cfg.Auth.JWTSecret = jwtSecret
+	cfg.Auth.JWTIssuer = "movies-test"
+	keys := auth.NewKeyStore(cfg)
+	h := handlers.New(repository.New(db.New(), nil, nil, nil), &pki.Authority{Dir: t.TempDir()}, nil, nil, keys, nil, nil)
+	e := routes.NewEchoRouter(h, nil, zap.NewNop(), nil, nil, auth.New(cfg, keys), nil)
+	spec, err := api.GetSwagger()
+	require.NoError(t, err)
+	e.Use(validation.ResponseMiddleware(spec))
+	return e
+}
+
+func call(t *testing.T, e http.Handler, method, target, body string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func detail(t *testing.T, rec *httptest.ResponseRecorder) string {
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	return p.Detail
+}
+
+func TestAPIKeys(t *testing.T) {
+	e := newRouter(t)
+	movie := `{"title":"Shrek","release_year":2001}`
+
+	rec := call(t, e, http.MethodPost, "/movies", movie)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "X-API-Key")
+	rec = call(t, e, http.MethodPost, "/movies", movie, auth.HeaderAPIKey, "mca_guess")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, detail(t, rec), "unknown or revoked")
+
+	rec = call(t, e, http.MethodPost, "/admin/api-keys", `{"name":"ci","role":"editor"}`, auth.HeaderAPIKey, adminKey)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created api.CreatedApiKey
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, api.Editor, created.Key.Role)
+	hash := sha256.Sum256([]byte(created.Secret))
+	assert.Equal(t, hex.EncodeToString(hash[:4]), created.Key.Prefix)
+	assert.NotContains(t, created.Secret, created.Key.Prefix)
+	editorKey := created.Secret
+
+	assert.Equal(t, http.StatusCreated, call(t, e, http.MethodPost, "/movies", movie, auth.HeaderAPIKey, editorKey).Code)
+	assert.Equal(t, http.StatusOK, call(t, e, http.MethodGet, "/movies", "").Code)
+	assert.Equal(t, http.StatusOK, call(t, e, http.MethodGet, "/movies", "", auth.HeaderAPIKey, editorKey).Code)
+	assert.Equal(t, http.StatusUnauthorized, call(t, e, http.MethodGet, "/movies", "", auth.HeaderAPIKey, "mca_guess").Code)
+	rec = call(t, e, http.MethodDelete, "/movies?id="+created.Key.Id.String(), "", auth.HeaderAPIKey, editorKey)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, detail(t, rec), "admin role is required")
+	assert.Equal(t, http.StatusForbidden, call(t, e, http.MethodGet, "/admin/api-keys", "", auth.HeaderAPIKey, editorKey).Code)
+
+	rec = call(t, e, http.MethodGet, "/admin/api-keys", "", auth.HeaderAPIKey, adminKey)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), editorKey)
+	var keys []api.ApiKey
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &keys))
+	require.Len(t, keys, 2)
+	assert.Equal(t, api.Admin, keys[0].Role)
+	assert.NotContains(t, adminKey, keys[0].Prefix)
+	assert.Equal(t, "ci", keys[1].Name)
+
+	assert.Equal(t, http.StatusNoContent, call(t, e, http.MethodDelete, "/admin/api-keys/"+created.Key.Id.String(), "", auth.HeaderAPIKey, adminKey).Code)
+	assert.Equal(t, http.StatusNotFound, call(t, e, http.MethodDelete, "/admin/api-keys/"+created.Key.Id.String(), "", auth.HeaderAPIKey, adminKey).Code)
+	assert.Equal(t, http.StatusUnauthorized, call(t, e, http.MethodPost, "/movies", movie, auth.HeaderAPIKey, editorKey).Code)
+}
+
+func TestBearerTokens(t *testing.T) {
+	e := newRouter(t)
+	movie := `{"title":"Shrek","release_year":2001}`
+	bearer := func(j *auth.JWT, role auth.Role, ttl time.Duration) string {
+		token, err := j.Sign("alice", role, ttl)
+		require.NoError(t, err)
+		return "Bearer " + token
+	}
+	j := auth.NewJWT([]byte(jwtSecret), "movies-test")
+
+	assert.Equal(t, http.StatusCreated, call(t, e, http.MethodPost, "/movies", movie, "Authorization", bearer(j, auth.Editor, time.Minute)).Code)
+	assert.Equal(t, http.StatusForbidden, call(t, e, http.MethodPost, "/movies", movie, "Authorization", bearer(j, auth.Viewer, time.Minute)).Code)
+
+	for name, token := range map[string]string{
+		"expired":      bearer(j, auth.Admin, -time.Minute),
+		"wrong secret": bearer(auth.NewJWT([]byte("another secret of at least 32 bytes"), "movies-test"), auth.Admin, time.Minute),
+		"wrong issuer": bearer(auth.NewJWT([]byte(jwtSecret), "someone-else"), auth.Admin, time.Minute),
+		"unknown role": bearer(j, "root", time.Minute),
+		"alg none":     "Bearer eyJhbGciOiJub25lIn0.eyJzdWIiOiJhbGljZSIsInJvbGUiOiJhZG1pbiJ9.",
+	} {
+		rec := call(t, e, http.MethodPost, "/movies", movie, "Authorization", token)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, name)
+		assert.Contains(t, detail(t, rec), "invalid bearer token", name)
+	}
+}
+
+func TestRoles(t *testing.T) {
+	assert.True(t, auth.Admin.Includes(auth.Editor))
+	assert.True(t, auth.Editor.Includes(auth.Editor))
+	assert.False(t, auth.Viewer.Includes(auth.Editor))
+	assert.False(t, auth.Role("").Includes(auth.Viewer))
+	_, err := auth.ParseRole("root")
+	assert.Error(t, err)
+}
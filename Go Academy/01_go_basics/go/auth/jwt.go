@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// Claims of a bearer token. The role claim holds viewer, editor or admin.
+type Claims struct {
+	Role Role `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// JWT verifies HS256 bearer tokens. Tokens must expire and, when an issuer
+// is set, carry it in their iss claim.
+type JWT struct {
+	secret []byte
+	issuer string
+}
+
+func NewJWT(secret []byte, issuer string) *JWT {
+	return &JWT{secret: secret, issuer: issuer}
+}
+
+// Sign issues a token for subject, valid for ttl.
+func (j *JWT) Sign(subject string, role Role, ttl time.Duration) (string, error) {
+	now := time.Now()
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    j.issuer,
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}).SignedString(j.secret)
+}
+
+func (j *JWT) Authenticate(r *http.Request) (Principal, error) {
+	token, ok := strings.CutPrefix(r.Header.Get(echo.HeaderAuthorization), "Bearer ")
+	if !ok {
+		return Principal{}, ErrNoCredentials
+	}
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if j.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(j.issuer))
+	}
+	var claims Claims
+	if _, err := jwt.ParseWithClaims(strings.TrimSpace(token), &claims, func(*jwt.Token) (any, error) {
+		return j.secret, nil
+	}, opts...); err != nil {
+		return Principal{}, fmt.Errorf("invalid bearer token: %w", err)
+	}
+	role, err := ParseRole(string(claims.Role))
+	if err != nil {
+		return Principal{}, fmt.Errorf("invalid bearer token: %w", err)
+	}
+	return Principal{Subject: "jwt:" + claims.Subject, Role: role}, nil
+}
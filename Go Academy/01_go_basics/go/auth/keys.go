@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/go_basics/go/config"
+	"github.com/google/uuid"
+)
+
+// HeaderAPIKey carries the API key of a request.
+const HeaderAPIKey = "X-API-Key"
+
+// keyPrefix marks the API keys of this server, which helps secret scanners.
+const keyPrefix = "mca_"
+
+var (
+	ErrKeyNotFound   = errors.New("API key not found")
+	errUnknownAPIKey = errors.New("unknown or revoked API key")
+)
+
+// APIKey describes an issued key. The secret itself is never stored.
+type APIKey struct {
+	ID   uuid.UUID
+	Name string
+	Role Role
+	// Prefix tells keys apart by the leading hex digits of their hash, so
+	// listing keys reveals nothing of the secrets.
+	Prefix    string
+	CreatedAt time.Time
+}
+
+// KeyStore keeps the SHA-256 hashes of the API keys it issued. Keys are 256
+// bit random values, so a fast hash cannot be brute forced the way a
+// password hash could.
+type KeyStore struct {
+	mu     sync.RWMutex
+	keys   map[uuid.UUID]APIKey
+	byHash map[[sha256.Size]byte]uuid.UUID
+}
+
+// NewKeyStore adds the admin key of the config, if any.
+func NewKeyStore(cfg *config.Config) *KeyStore {
+	s := &KeyStore{
+		keys:   make(map[uuid.UUID]APIKey),
+		byHash: make(map[[sha256.Size]byte]uuid.UUID),
+	}
+	if cfg.Auth.AdminKey != "" {
+		s.add("admin (config)", Admin, string(cfg.Auth.AdminKey))
+	}
+	return s
+}
+
+// Create issues a key. The returned secret is the only copy.
+func (s *KeyStore) Create(name string, role Role) (APIKey, string, error) {
+	if _, err := ParseRole(string(role)); err != nil {
+		return APIKey{}, "", err
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return APIKey{}, "", err
+	}
+	secret := keyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return s.add(name, role, secret), secret, nil
+}
+
+func (s *KeyStore) add(name string, role Role, secret string) APIKey {
+	hash := sha256.Sum256([]byte(secret))
+	key := APIKey{
+		ID:        uuid.New(),
+		Name:      name,
+		Role:      role,
+		Prefix:    hex.EncodeToString(hash[:4]),
+		CreatedAt: time.Now().UTC(),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key.ID] = key
+	s.byHash[hash] = key.ID
+	return key
+}
+
+// List returns every key, oldest first.
+func (s *KeyStore) List() []APIKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]APIKey, 0, len(s.keys))
+	for _, k := range s.keys {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b APIKey) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return keys
+}
+
+// Revoke deletes a key, requests using it fail from now on.
+func (s *KeyStore) Revoke(id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.keys[id]; !ok {
+		return fmt.Errorf("%w [ID: %s]", ErrKeyNotFound, id)
+	}
+	delete(s.keys, id)
+	for hash, keyID := range s.byHash {
+		if keyID == id {
+			delete(s.byHash, hash)
+		}
+	}
+	return nil
+}
+
+// Authenticate looks up the X-API-Key header of r.
+func (s *KeyStore) Authenticate(r *http.Request) (Principal, error) {
+	secret := strings.TrimSpace(r.Header.Get(HeaderAPIKey))
+	if secret == "" {
+		return Principal{}, ErrNoCredentials
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	id, ok := s.byHash[sha256.Sum256([]byte(secret))]
+	if !ok {
+		return Principal{}, errUnknownAPIKey
+	}
+	key := s.keys[id]
+	return Principal{Subject: "key:" + key.Name, Role: key.Role}, nil
+}
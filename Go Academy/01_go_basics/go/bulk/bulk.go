@@ -0,0 +1,79 @@
+// Package bulk imports and exports the whole catalog as records in JSON,
+// NDJSON or CSV. Every record is a movie, a character or an appearance;
+// appearances name their movie and character by the key of another record,
+// so a file can be written by hand or produced by an export.
+package bulk
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Format of a file of records.
+type Format string
+
+const (
+	// JSON is an array of records.
+	JSON Format = "json"
+	// NDJSON is one record per line.
+	NDJSON Format = "ndjson"
+	// CSV has a header row naming the record fields in any order.
+	CSV Format = "csv"
+)
+
+// ContentTypes maps the formats to their media type.
+var ContentTypes = map[Format]string{
+	JSON:   "application/json",
+	NDJSON: "application/x-ndjson",
+	CSV:    "text/csv",
+}
+
+// FormatOf returns the format of a media type.
+func FormatOf(contentType string) (Format, error) {
+	for f, ct := range ContentTypes {
+		if ct == contentType {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("%w: unsupported content type %q", ErrInvalid, contentType)
+}
+
+// Record types.
+const (
+	TypeMovie      = "movie"
+	TypeCharacter  = "character"
+	TypeAppearance = "appearance"
+)
+
+// Record is one movie, character or appearance. Movies and characters may
+// set a Key, unique within the file, for appearances to refer to; exports
+// use their ID. An appearance names a Movie and a Character by key, or by
+// the ID of one already stored.
+type Record struct {
+	Type        string `json:"type"`
+	Key         string `json:"key,omitempty"`
+	Title       string `json:"title,omitempty"`
+	ReleaseYear int    `json:"release_year,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Movie       string `json:"movie,omitempty"`
+	Character   string `json:"character,omitempty"`
+
+	// Line is where the record starts in the file, from 1.
+	Line int `json:"-"`
+}
+
+// columns of CSV files, in the order exports write them.
+var columns = []string{"type", "key", "title", "release_year", "name", "movie", "character"}
+
+// ErrInvalid is returned for a file that cannot be read at all.
+var ErrInvalid = errors.New("invalid import")
+
+// LineError reports a record that cannot be imported.
+type LineError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
@@ -0,0 +1,180 @@
+package bulk_test
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/bulk"
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/handlers"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/routes"
+	"example.com/go_basics/go/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const adminKey = "mca_test-admin-key"
+
+func newRouter(t *testing.T) (http.Handler, *repository.Repository) {
+	cfg := config.Default()
+	cfg.Auth.AdminKey = adminKey
+	repo := repository.New(db.New(), nil, nil, nil)
+	keys := auth.NewKeyStore(cfg)
+	h := handlers.New(repo, &pki.Authority{Dir: t.TempDir()}, nil, nil, keys, nil, nil)
+	e := routes.NewEchoRouter(h, nil, zap.NewNop(), nil, nil, auth.New(cfg, keys), nil)
+	spec, err := api.GetSwagger()
+	require.NoError(t, err)
+	e.Use(validation.ResponseMiddleware(spec))
+	return e, repo
+}
+
+func importFile(t *testing.T, e http.Handler, target, contentType, body string) (int, bulk.Report) {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(auth.HeaderAPIKey, adminKey)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	var report bulk.Report
+	if rec.Code == http.StatusOK || rec.Code == http.StatusUnprocessableEntity {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report), rec.Body.String())
+	}
+	return rec.Code, report
+}
+
+func export(t *testing.T, e http.Handler, format string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/export?format="+format, nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	return rec
+}
+
+const shrekNDJSON = `{"type":"movie","key":"shrek","title":"Shrek","release_year":2001}
+{"type":"appearance","movie":"shrek","character":"donkey"}
+
+{"type":"character","key":"donkey","name":"Donkey"}
+{"type":"character","name":"Lord Farquaad"}
+`
+
+func TestImportNDJSON(t *testing.T) {
+	e, repo := newRouter(t)
+
+	code, report := importFile(t, e, "/import?dry_run=true", "application/x-ndjson", shrekNDJSON)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, bulk.Report{DryRun: true, Movies: 1, Characters: 2, Appearances: 1, Errors: []bulk.LineError{}}, report)
+	assert.Empty(t, repo.Snapshot(t.Context()).Movies)
+
+	code, report = importFile(t, e, "/import", "application/x-ndjson", shrekNDJSON)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, report.Movies)
+	assert.Equal(t, 2, report.Characters)
+	assert.Equal(t, 1, report.Appearances)
+	characters, err := repo.GetCharactersByMovie(t.Context(), report.IDs["shrek"])
+	require.NoError(t, err)
+	require.Len(t, characters, 1)
+	assert.Equal(t, report.IDs["donkey"], characters[0].ID)
+
+	// Appearances may name stored movies and characters by ID.
+	link := `{"type":"character","key":"puss","name":"Puss in Boots"}
+{"type":"appearance","movie":"` + report.IDs["shrek"].String() + `","character":"puss"}`
+	code, report = importFile(t, e, "/import", "application/x-ndjson", link)
+	require.Equal(t, http.StatusOK, code, report.Errors)
+	assert.Equal(t, 1, report.Appearances)
+}
+
+func TestImportReportsErrorsByLine(t *testing.T) {
+	e, repo := newRouter(t)
+	csvFile := `type,key,title,release_year,name,movie,character
+movie,shrek,Shrek,2001,,,
+movie,shrek,Shrek 2,1800,,,
+character,donkey,,,,,
+appearance,,,,,shrek,fiona
+appearance,,,,,donkey,
+villain,,,,Farquaad,,
+movie,babe,Babe,soon,,,
+`
+	code, report := importFile(t, e, "/import", "text/csv", csvFile)
+	require.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []bulk.LineError{
+		{Line: 3, Message: `key "shrek" is already used on line 2`},
+		{Line: 3, Message: "release_year must be 1900 or later"},
+		{Line: 4, Message: "a character needs a name"},
+		{Line: 5, Message: `character "fiona" is neither a key in the file nor a stored character`},
+		{Line: 6, Message: `movie "donkey" is the key of a character on line 4`},
+		{Line: 6, Message: "an appearance needs a character"},
+		{Line: 7, Message: `unknown type "villain", expected movie, character or appearance`},
+		{Line: 8, Message: `release_year "soon" is not a number`},
+	}, report.Errors)
+	assert.Zero(t, report.Movies)
+	assert.Empty(t, repo.Snapshot(t.Context()).Movies)
+
+	code, report = importFile(t, e, "/import", "application/x-ndjson", "{\"type\":\"movie\",\"title\":\"Shrek\",\"release_year\":2001}\n{\"type\":\"movie\",\"titel\":\"Babe\"}\nnot json\n")
+	require.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []int{2, 3}, []int{report.Errors[0].Line, report.Errors[1].Line})
+	assert.Contains(t, report.Errors[0].Message, `unknown field "titel"`)
+
+	jsonFile := `[
+		{"type": "movie", "title": "Shrek", "release_year": 2001},
+		{"type": "movie", "title": "Babe",
+		 "release_year": "1995"}
+	]`
+	code, report = importFile(t, e, "/import", "application/json", jsonFile)
+	require.Equal(t, http.StatusUnprocessableEntity, code)
+	require.Len(t, report.Errors, 1)
+	assert.Equal(t, 3, report.Errors[0].Line)
+
+	req := httptest.NewRequest(http.MethodPost, "/import", strings.NewReader(shrekNDJSON))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	e, _ := newRouter(t)
+	code, _ := importFile(t, e, "/import", "application/x-ndjson", shrekNDJSON)
+	require.Equal(t, http.StatusOK, code)
+
+	rec := export(t, e, "csv")
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="catalog.csv"`, rec.Header().Get("Content-Disposition"))
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 5)
+	assert.Equal(t, []string{"type", "key", "title", "release_year", "name", "movie", "character"}, rows[0])
+	assert.Equal(t, []string{"movie", "Shrek", "2001"}, []string{rows[1][0], rows[1][2], rows[1][3]})
+	assert.Equal(t, []string{"character", "Donkey"}, []string{rows[2][0], rows[2][4]})
+	assert.Equal(t, "Lord Farquaad", rows[3][4])
+	assert.Equal(t, []string{"appearance", rows[1][1], rows[2][1]}, []string{rows[4][0], rows[4][5], rows[4][6]})
+
+	ndjson := export(t, e, "ndjson").Body.String()
+	assert.Len(t, strings.Split(strings.TrimSpace(ndjson), "\n"), 4)
+
+	// Every format imports into an empty server as it is.
+	for format, contentType := range bulk.ContentTypes {
+		t.Run(string(format), func(t *testing.T) {
+			body := export(t, e, string(format)).Body.String()
+			other, repo := newRouter(t)
+			code, report := importFile(t, other, "/import", contentType, body)
+			require.Equal(t, http.StatusOK, code, report.Errors)
+			s := repo.Snapshot(t.Context())
+			require.Len(t, s.Movies, 1)
+			require.Len(t, s.Characters, 2)
+			assert.Equal(t, []string{"Shrek", "Donkey", "Lord Farquaad"}, []string{s.Movies[0].Title, s.Characters[0].Name, s.Characters[1].Name})
+			require.Len(t, s.Appearances, 1)
+			assert.Equal(t, s.Characters[0].ID, s.Appearances[0].CharacterID)
+		})
+	}
+
+	empty, _ := newRouter(t)
+	assert.Equal(t, "[]\n", export(t, empty, "json").Body.String())
+}
@@ -0,0 +1,182 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxLine bounds an NDJSON line.
+const maxLine = 1 << 20
+
+// decode reads every record of r. Records that cannot be read are reported
+// and skipped, unless the file cannot be read past them.
+func decode(format Format, r io.Reader) ([]Record, []LineError) {
+	switch format {
+	case JSON:
+		return decodeJSON(r)
+	case NDJSON:
+		return decodeNDJSON(r)
+	case CSV:
+		return decodeCSV(r)
+	}
+	return nil, []LineError{{Line: 1, Message: fmt.Sprintf("unsupported format %q", format)}}
+}
+
+func decodeJSON(r io.Reader) ([]Record, []LineError) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, []LineError{{Line: 1, Message: err.Error()}}
+	}
+	// lineAt returns the line of the first value at or after offset.
+	lineAt := func(offset int64) int {
+		rest := data[offset:]
+		start := offset + int64(len(rest)-len(bytes.TrimLeft(rest, " \t\r\n,")))
+		return bytes.Count(data[:start], []byte("\n")) + 1
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if t, err := dec.Token(); err != nil || t != json.Delim('[') {
+		return nil, []LineError{{Line: lineAt(0), Message: "expected an array of records"}}
+	}
+	var records []Record
+	var errs []LineError
+	for dec.More() {
+		line := lineAt(dec.InputOffset())
+		var rec Record
+		err := dec.Decode(&rec)
+		var syntax *json.SyntaxError
+		switch {
+		case errors.As(err, &syntax):
+			return records, append(errs, LineError{Line: lineAt(syntax.Offset - 1), Message: jsonMessage(err)})
+		case err != nil:
+			errs = append(errs, LineError{Line: line, Message: jsonMessage(err)})
+		default:
+			rec.Line = line
+			records = append(records, rec)
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		errs = append(errs, LineError{Line: lineAt(dec.InputOffset()), Message: jsonMessage(err)})
+	}
+	return records, errs
+}
+
+func decodeNDJSON(r io.Reader) ([]Record, []LineError) {
+	var records []Record
+	var errs []LineError
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLine)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(text))
+		dec.DisallowUnknownFields()
+		var rec Record
+		if err := dec.Decode(&rec); err != nil {
+			errs = append(errs, LineError{Line: line, Message: jsonMessage(err)})
+			continue
+		}
+		if dec.More() {
+			errs = append(errs, LineError{Line: line, Message: "one record per line expected"})
+			continue
+		}
+		rec.Line = line
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, LineError{Line: line + 1, Message: err.Error()})
+	}
+	return records, errs
+}
+
+func decodeCSV(r io.Reader) ([]Record, []LineError) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, []LineError{{Line: 1, Message: "expected a header row: " + csvMessage(err)}}
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if !slices.Contains(columns, name) {
+			return nil, []LineError{{Line: 1, Message: fmt.Sprintf("unknown column %q, expected %s", name, strings.Join(columns, ", "))}}
+		}
+		index[name] = i
+	}
+	if _, ok := index["type"]; !ok {
+		return nil, []LineError{{Line: 1, Message: `the header lacks the "type" column`}}
+	}
+
+	var records []Record
+	var errs []LineError
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return records, errs
+		}
+		line, _ := cr.FieldPos(0)
+		if err != nil {
+			var parse *csv.ParseError
+			if errors.As(err, &parse) {
+				line = parse.StartLine
+			}
+			errs = append(errs, LineError{Line: line, Message: csvMessage(err)})
+			continue
+		}
+		if len(row) != len(header) {
+			errs = append(errs, LineError{Line: line, Message: fmt.Sprintf("%d fields, the header has %d", len(row), len(header))})
+			continue
+		}
+		field := func(name string) string {
+			if i, ok := index[name]; ok {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		rec := Record{
+			Type:      field("type"),
+			Key:       field("key"),
+			Title:     field("title"),
+			Name:      field("name"),
+			Movie:     field("movie"),
+			Character: field("character"),
+			Line:      line,
+		}
+		if year := field("release_year"); year != "" {
+			if rec.ReleaseYear, err = strconv.Atoi(year); err != nil {
+				errs = append(errs, LineError{Line: line, Message: fmt.Sprintf("release_year %q is not a number", year)})
+				continue
+			}
+		}
+		records = append(records, rec)
+	}
+}
+
+func jsonMessage(err error) string {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return "unexpected end of JSON input"
+	}
+	return strings.TrimPrefix(err.Error(), "json: ")
+}
+
+func csvMessage(err error) string {
+	var parse *csv.ParseError
+	if errors.As(err, &parse) {
+		return parse.Err.Error()
+	}
+	return err.Error()
+}
@@ -0,0 +1,97 @@
+package bulk
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"example.com/go_basics/go/repository"
+)
+
+// Export writes the snapshot as records: movies, then characters, then
+// appearances. Movies and characters are keyed by their ID, so the file
+// imports into another store as it is.
+func Export(w io.Writer, format Format, s repository.Snapshot) error {
+	bw := bufio.NewWriter(w)
+	var write func(Record) error
+	end := func() error { return nil }
+	switch format {
+	case JSON:
+		first := true
+		write = func(rec Record) error {
+			sep := ",\n"
+			if first {
+				sep, first = "[\n", false
+			}
+			if _, err := bw.WriteString(sep); err != nil {
+				return err
+			}
+			return writeJSON(bw, rec)
+		}
+		end = func() error {
+			if first {
+				_, err := bw.WriteString("[]\n")
+				return err
+			}
+			_, err := bw.WriteString("\n]\n")
+			return err
+		}
+	case NDJSON:
+		write = func(rec Record) error {
+			if err := writeJSON(bw, rec); err != nil {
+				return err
+			}
+			return bw.WriteByte('\n')
+		}
+	case CSV:
+		cw := csv.NewWriter(bw)
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+		write = func(rec Record) error {
+			year := ""
+			if rec.ReleaseYear != 0 {
+				year = strconv.Itoa(rec.ReleaseYear)
+			}
+			return cw.Write([]string{rec.Type, rec.Key, rec.Title, year, rec.Name, rec.Movie, rec.Character})
+		}
+		end = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	default:
+		return fmt.Errorf("%w: unsupported format %q", ErrInvalid, format)
+	}
+
+	for _, m := range s.Movies {
+		if err := write(Record{Type: TypeMovie, Key: m.ID.String(), Title: m.Title, ReleaseYear: m.Year}); err != nil {
+			return err
+		}
+	}
+	for _, c := range s.Characters {
+		if err := write(Record{Type: TypeCharacter, Key: c.ID.String(), Name: c.Name}); err != nil {
+			return err
+		}
+	}
+	for _, a := range s.Appearances {
+		if err := write(Record{Type: TypeAppearance, Movie: a.MovieID.String(), Character: a.CharacterID.String()}); err != nil {
+			return err
+		}
+	}
+	if err := end(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func writeJSON(w io.Writer, rec Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
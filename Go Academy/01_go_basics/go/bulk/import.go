@@ -0,0 +1,195 @@
+package bulk
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"example.com/go_basics/go/logging"
+	"example.com/go_basics/go/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxErrors bounds the errors of a report.
+const maxErrors = 100
+
+// Report tells what an import created, or would have created on a dry run.
+type Report struct {
+	DryRun      bool `json:"dry_run"`
+	Movies      int  `json:"movies"`
+	Characters  int  `json:"characters"`
+	Appearances int  `json:"appearances"`
+	// IDs maps the keys of the created movies and characters to their ID.
+	IDs    map[string]uuid.UUID `json:"ids,omitempty"`
+	Errors []LineError          `json:"errors"`
+}
+
+// Import reads the records of r and creates them through repo. The whole
+// file is checked before anything is created, and the records are created
+// in one batch, so a file with errors creates nothing. A dry run only
+// checks.
+func Import(ctx context.Context, repo *repository.Repository, format Format, r io.Reader, dryRun bool) Report {
+	records, errs := decode(format, r)
+	p := plan(ctx, repo, records)
+	report := Report{DryRun: dryRun, Errors: append(append([]LineError{}, errs...), p.errs...)}
+	slices.SortStableFunc(report.Errors, func(a, b LineError) int { return cmp.Compare(a.Line, b.Line) })
+	if len(report.Errors) > maxErrors {
+		report.Errors = append(report.Errors[:maxErrors], LineError{Line: report.Errors[maxErrors].Line, Message: "too many errors, the rest are not reported"})
+	}
+	switch {
+	case len(report.Errors) > 0:
+		return report
+	case dryRun:
+		report.Movies, report.Characters, report.Appearances = len(p.movies), len(p.characters), len(p.appearances)
+		return report
+	}
+
+	ids := make(map[string]uuid.UUID)
+	var created Report
+	err := repo.Batch(ctx, func(tx *repository.Tx) error {
+		for _, rec := range p.movies {
+			m, err := tx.CreateMovie(rec.Title, rec.ReleaseYear)
+			if err != nil {
+				return LineError{Line: rec.Line, Message: err.Error()}
+			}
+			created.Movies++
+			if rec.Key != "" {
+				ids[rec.Key] = m.ID
+			}
+		}
+		for _, rec := range p.characters {
+			c, err := tx.CreateCharacter(rec.Name)
+			if err != nil {
+				return LineError{Line: rec.Line, Message: err.Error()}
+			}
+			created.Characters++
+			if rec.Key != "" {
+				ids[rec.Key] = c.ID
+			}
+		}
+		resolve := func(ref string) uuid.UUID {
+			if id, ok := ids[ref]; ok {
+				return id
+			}
+			return uuid.MustParse(ref)
+		}
+		for _, rec := range p.appearances {
+			if err := tx.AddAppearance(resolve(rec.Movie), resolve(rec.Character)); err != nil {
+				return LineError{Line: rec.Line, Message: err.Error()}
+			}
+			created.Appearances++
+		}
+		return nil
+	})
+	var lineErr LineError
+	if errors.As(err, &lineErr) {
+		report.Errors = append(report.Errors, lineErr)
+		logging.FromContext(ctx).Warn("import rolled back", zap.Int("line", lineErr.Line), zap.Error(err))
+		return report
+	}
+	report.Movies, report.Characters, report.Appearances, report.IDs = created.Movies, created.Characters, created.Appearances, ids
+	logging.FromContext(ctx).Info("catalog imported",
+		zap.Int("movies", report.Movies), zap.Int("characters", report.Characters), zap.Int("appearances", report.Appearances))
+	return report
+}
+
+// importPlan holds the checked records by type, in file order.
+type importPlan struct {
+	movies, characters, appearances []Record
+	errs                            []LineError
+}
+
+func plan(ctx context.Context, repo *repository.Repository, records []Record) importPlan {
+	var p importPlan
+	report := func(rec Record, format string, args ...any) {
+		p.errs = append(p.errs, LineError{Line: rec.Line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Keys may be used before the line that defines them.
+	keys := make(map[string]Record)
+	for _, rec := range records {
+		if rec.Key == "" || (rec.Type != TypeMovie && rec.Type != TypeCharacter) {
+			continue
+		}
+		if first, ok := keys[rec.Key]; ok {
+			report(rec, "key %q is already used on line %d", rec.Key, first.Line)
+			continue
+		}
+		keys[rec.Key] = rec
+	}
+
+	linked := make(map[[2]string]int)
+	for _, rec := range records {
+		switch rec.Type {
+		case TypeMovie:
+			switch {
+			case rec.Title == "":
+				report(rec, "a movie needs a title")
+			case rec.ReleaseYear < 1900:
+				report(rec, "release_year must be 1900 or later")
+			default:
+				p.movies = append(p.movies, rec)
+			}
+		case TypeCharacter:
+			if rec.Name == "" {
+				report(rec, "a character needs a name")
+				continue
+			}
+			p.characters = append(p.characters, rec)
+		case TypeAppearance:
+			movieErr := resolveRef(ctx, repo, keys, TypeMovie, rec.Movie)
+			if movieErr != nil {
+				report(rec, "%v", movieErr)
+			}
+			characterErr := resolveRef(ctx, repo, keys, TypeCharacter, rec.Character)
+			if characterErr != nil {
+				report(rec, "%v", characterErr)
+			}
+			if movieErr != nil || characterErr != nil {
+				continue
+			}
+			pair := [2]string{rec.Movie, rec.Character}
+			if first, ok := linked[pair]; ok {
+				report(rec, "the appearance is already on line %d", first)
+				continue
+			}
+			linked[pair] = rec.Line
+			p.appearances = append(p.appearances, rec)
+		case "":
+			report(rec, "a record needs a type")
+		default:
+			report(rec, "unknown type %q, expected %s, %s or %s", rec.Type, TypeMovie, TypeCharacter, TypeAppearance)
+		}
+	}
+	return p
+}
+
+// resolveRef checks that ref is the key of a record of the type in the
+// file, or the ID of a stored one.
+func resolveRef(ctx context.Context, repo *repository.Repository, keys map[string]Record, typ, ref string) error {
+	if ref == "" {
+		return fmt.Errorf("an appearance needs a %s", typ)
+	}
+	if rec, ok := keys[ref]; ok {
+		if rec.Type != typ {
+			return fmt.Errorf("%s %q is the key of a %s on line %d", typ, ref, rec.Type, rec.Line)
+		}
+		return nil
+	}
+	id, err := uuid.Parse(ref)
+	if err == nil {
+		if typ == TypeMovie {
+			_, err = repo.GetMovie(ctx, id)
+		} else {
+			_, err = repo.GetCharacter(ctx, id)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s %q is neither a key in the file nor a stored %s", typ, ref, typ)
+	}
+	return nil
+}
@@ -1,26 +1,28 @@
 package main
 
 import (
-	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
-	"crypto/x509/pkix"
-	"encoding/pem"
 	"errors"
 	"flag"
 	"fmt"
-	"math/big"
 	"os"
 	"path/filepath"
-	"time"
+
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/translog"
 )
 
 func main() {
-	mode := flag.String("mode", "", "Mode: ca | movie | character")
+	mode := flag.String("mode", "", "Mode: ca | movie | character | verify")
 	name := flag.String("name", "", "Name of movie or character")
-	caPath := flag.String("ca", "", "Path to CA cert (for movie)")
+	caPath := flag.String("ca", "", "Path to CA cert (for movie and verify)")
 	moviePath := flag.String("movie", "", "Path to movie cert (for character)")
 	outPath := flag.String("out", "", "Output path for cert")
+	certPath := flag.String("cert", "", "Path to cert checked against the transparency log (for verify)")
+	logURL := flag.String("log", "http://localhost:8080", "Base URL of the API serving the log (for verify)")
+	oldSize := flag.Int("old-size", 0, "Size of a previously trusted tree head (for verify)")
+	oldRoot := flag.String("old-root", "", "Base64 root hash of a previously trusted tree head (for verify)")
 	flag.Parse()
 
 	switch *mode {
@@ -33,121 +35,100 @@ func main() {
 	case "character":
 		err := generateCharacterCert(*name, *moviePath, *outPath)
 		exitOnError(err)
+	case "verify":
+		err := verifyLogInclusion(*certPath, *caPath, *logURL, *oldSize, *oldRoot)
+		exitOnError(err)
 	default:
-		fmt.Println("Invalid mode. Use -mode=ca|movie|character")
+		fmt.Println("Invalid mode. Use -mode=ca|movie|character|verify")
 		os.Exit(1)
 	}
 }
 
 func generateCACert(out string) error {
-	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
-	template := x509.Certificate{
-		SerialNumber:          big.NewInt(1),
-		Subject:               pkix.Name{CommonName: "My CA"},
-		NotBefore:             time.Now(),
-		NotAfter:              time.Now().AddDate(10, 0, 0),
-		IsCA:                  true,
-		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
-		BasicConstraintsValid: true,
-	}
-
-	certBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	cert, priv, err := pki.Issue(pki.CATemplate(), nil, nil)
 	if err != nil {
 		return err
 	}
 
-	return writeCertAndKey(out, certBytes, priv)
+	return writeCertAndKey(out, cert, priv)
 }
 
 func generateMovieCert(name, caPath, out string) error {
-	caCert, caKey, err := loadCertAndKey(caPath)
+	caCert, caKey, err := pki.LoadCertAndKey(caPath)
 	if err != nil {
 		return err
 	}
 
-	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
-	template := x509.Certificate{
-		SerialNumber: big.NewInt(time.Now().UnixNano()),
-		Subject:      pkix.Name{CommonName: name},
-		NotBefore:    time.Now(),
-		NotAfter:     time.Now().AddDate(5, 0, 0),
-		KeyUsage:     x509.KeyUsageDigitalSignature,
-	}
-
-	certBytes, err := x509.CreateCertificate(rand.Reader, &template, caCert, &priv.PublicKey, caKey)
+	cert, priv, err := pki.Issue(pki.MovieTemplate(name), caCert, caKey)
 	if err != nil {
 		return err
 	}
 
-	return writeCertAndKey(out, certBytes, priv)
+	return writeCertAndKey(out, cert, priv)
 }
 
 func generateCharacterCert(name, moviePath, out string) error {
-	movieCert, movieKey, err := loadCertAndKey(moviePath)
+	movieCert, movieKey, err := pki.LoadCertAndKey(moviePath)
 	if err != nil {
 		return err
 	}
 
-	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
-	template := x509.Certificate{
-		SerialNumber: big.NewInt(time.Now().UnixNano()),
-		Subject:      pkix.Name{CommonName: name},
-		NotBefore:    time.Now(),
-		NotAfter:     time.Now().AddDate(2, 0, 0),
-		KeyUsage:     x509.KeyUsageDigitalSignature,
-	}
-
-	certBytes, err := x509.CreateCertificate(rand.Reader, &template, movieCert, &priv.PublicKey, movieKey)
+	cert, priv, err := pki.Issue(pki.CharacterTemplate(name), movieCert, movieKey)
 	if err != nil {
 		return err
 	}
 
-	return writeCertAndKey(out, certBytes, priv)
+	return writeCertAndKey(out, cert, priv)
 }
 
-func writeCertAndKey(out string, cert []byte, key *rsa.PrivateKey) error {
-	certOut := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})
-	keyOut := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
-
-	dir := filepath.Dir(out)
-	os.MkdirAll(dir, 0755)
-
-	if err := os.WriteFile(out, certOut, 0644); err != nil {
+// verifyLogInclusion checks the signed tree head of the log with the CA key,
+// proves that the certificate is included and, given an older tree head,
+// that the log only grew since then.
+func verifyLogInclusion(certPath, caPath, logURL string, oldSize int, oldRoot string) error {
+	cert, err := pki.LoadCert(certPath)
+	if err != nil {
 		return err
 	}
-	keyPath := out[:len(out)-len(filepath.Ext(out))] + ".key"
-	return os.WriteFile(keyPath, keyOut, 0600)
-}
-
-func loadCertAndKey(path string) (*x509.Certificate, *rsa.PrivateKey, error) {
-	data, err := os.ReadFile(path)
+	caCert, err := pki.LoadCert(caPath)
 	if err != nil {
-		return nil, nil, err
-	}
-	block, _ := pem.Decode(data)
-	if block == nil || block.Type != "CERTIFICATE" {
-		return nil, nil, errors.New("invalid cert")
+		return err
 	}
-	cert, err := x509.ParseCertificate(block.Bytes)
-	if err != nil {
-		return nil, nil, err
+	pub, ok := caCert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("CA key is not an RSA key")
 	}
 
-	keyPath := path[:len(path)-len(filepath.Ext(path))] + ".key"
-	keyData, err := os.ReadFile(keyPath)
+	client := translog.NewClient(logURL)
+	sth, err := translog.VerifyCertificate(client, cert.Raw, pub)
 	if err != nil {
-		return nil, nil, err
+		return err
 	}
-	keyBlock, _ := pem.Decode(keyData)
-	if keyBlock == nil || keyBlock.Type != "RSA PRIVATE KEY" {
-		return nil, nil, errors.New("invalid key")
+	fmt.Printf("Certificate %s is included in the log (tree size %d, root %s)\n",
+		cert.SerialNumber, sth.TreeSize, translog.EncodeHash(sth.RootHash))
+
+	if oldSize == 0 {
+		return nil
 	}
-	key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	root, err := translog.DecodeHash(oldRoot)
 	if err != nil {
-		return nil, nil, err
+		return err
+	}
+	old := translog.SignedTreeHead{TreeSize: oldSize, RootHash: root}
+	if err := translog.VerifyExtends(client, old, sth); err != nil {
+		return err
 	}
+	fmt.Printf("Tree of size %d is consistent with tree of size %d\n", sth.TreeSize, oldSize)
+	return nil
+}
 
-	return cert, key, nil
+func writeCertAndKey(out string, cert *x509.Certificate, key *rsa.PrivateKey) error {
+	dir := filepath.Dir(out)
+	os.MkdirAll(dir, 0755)
+
+	if err := os.WriteFile(out, pki.EncodeCert(cert), 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(pki.KeyPath(out), pki.EncodeKey(key), 0600)
 }
 
 func exitOnError(err error) {
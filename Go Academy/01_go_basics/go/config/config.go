@@ -0,0 +1,532 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting of the API server. Load fills it from defaults,
+// a YAML file, environment variables and flags, each overriding the previous.
+type Config struct {
+	Server    Server    `yaml:"server"`
+	Certs     Certs     `yaml:"certs"`
+	SWAPI     SWAPI     `yaml:"swapi"`
+	TestData  TestData  `yaml:"testdata"`
+	Log       Log       `yaml:"log"`
+	Tracing   Tracing   `yaml:"tracing"`
+	Health    Health    `yaml:"health"`
+	Auth      Auth      `yaml:"auth"`
+	RateLimit RateLimit `yaml:"rate_limit"`
+	Events    Events    `yaml:"events"`
+	Webhooks  Webhooks  `yaml:"webhooks"`
+	GraphQL   GraphQL   `yaml:"graphql"`
+	Trash     Trash     `yaml:"trash"`
+
+	// PrintConfig asks main to dump the loaded config and exit.
+	PrintConfig bool `yaml:"-"`
+}
+
+type Server struct {
+	Addr string `yaml:"addr"`
+	// TLSAddr enables an additional listener that requires client
+	// certificates issued by our CA, e.g. :8443.
+	TLSAddr  string   `yaml:"tls_addr"`
+	TLSHosts []string `yaml:"tls_hosts"`
+	// GRPCAddr is the listen address of the gRPC server, which is off when
+	// it is empty.
+	GRPCAddr string `yaml:"grpc_addr"`
+	// DrainDelay keeps serving after /readyz starts failing on shutdown, so
+	// load balancers notice before connections are refused.
+	DrainDelay time.Duration `yaml:"drain_delay"`
+	// ShutdownTimeout bounds how long in-flight requests may take to finish.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+type Certs struct {
+	Dir string `yaml:"dir"`
+}
+
+type SWAPI struct {
+	BaseURL string        `yaml:"base_url"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type TestData struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+type Log struct {
+	// Level is a zap level name: debug, info, warn or error.
+	Level string `yaml:"level"`
+	// Format is json or console.
+	Format string `yaml:"format"`
+}
+
+type Tracing struct {
+	// Exporter is none, stdout or otlp.
+	Exporter string `yaml:"exporter"`
+	// Endpoint is the OTLP/HTTP collector URL, e.g. http://localhost:4318.
+	// When empty the OTEL_EXPORTER_OTLP_* variables apply.
+	Endpoint string `yaml:"endpoint"`
+	// SampleRatio is the fraction of new traces that are recorded. Requests
+	// that arrive with a sampled trace context are always recorded.
+	SampleRatio float64 `yaml:"sample_ratio"`
+	ServiceName string  `yaml:"service_name"`
+}
+
+type Health struct {
+	// SWAPI makes /readyz fail while the Star Wars API is unreachable.
+	SWAPI bool `yaml:"swapi"`
+}
+
+type Auth struct {
+	// Enabled requires credentials for the operations the API spec secures.
+	Enabled bool `yaml:"enabled"`
+	// AdminKey is an API key with the admin role that exists from startup,
+	// so the first keys can be created through /admin/api-keys.
+	AdminKey Secret `yaml:"admin_key"`
+	// JWTSecret is the HMAC key of bearer tokens, which are rejected when
+	// it is empty.
+	JWTSecret Secret `yaml:"jwt_secret"`
+	// JWTIssuer is checked against the iss claim when set.
+	JWTIssuer string `yaml:"jwt_issuer"`
+}
+
+type RateLimit struct {
+	Enabled bool `yaml:"enabled"`
+	// TrustProxy takes the client IP from X-Forwarded-For or X-Real-IP.
+	// Only enable it behind a proxy that sets them.
+	TrustProxy bool `yaml:"trust_proxy"`
+	// Read applies to GET requests, Write to the others and SWAPI to the
+	// operations that call the Star Wars API.
+	Read  Limit `yaml:"read"`
+	Write Limit `yaml:"write"`
+	SWAPI Limit `yaml:"swapi"`
+	// Routes overrides the limit of single echo routes, e.g. "POST /movies"
+	// or "DELETE /characters/:id".
+	Routes map[string]Limit `yaml:"routes"`
+	// Tiers scales every limit by the client tier: anonymous or the role of
+	// the caller. A factor of 0 lifts the limits.
+	Tiers map[string]float64 `yaml:"tiers"`
+}
+
+type Events struct {
+	// Buffer is how many recent changes are kept for clients resuming the
+	// change feed with Last-Event-ID.
+	Buffer int `yaml:"buffer"`
+	// Heartbeat is the interval of keep-alive messages on idle streams.
+	Heartbeat time.Duration `yaml:"heartbeat"`
+}
+
+type Webhooks struct {
+	// Workers is the number of deliveries sent at the same time.
+	Workers int `yaml:"workers"`
+	// MaxAttempts is how often a delivery is tried before it goes to the
+	// dead-letter list.
+	MaxAttempts int `yaml:"max_attempts"`
+	// Backoff is the delay before the first retry. It doubles with every
+	// attempt up to MaxBackoff.
+	Backoff    time.Duration `yaml:"backoff"`
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+	// Timeout bounds a single delivery request.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type GraphQL struct {
+	// MaxComplexity rejects queries costing more before they run. Every
+	// field costs 1 and list fields multiply the cost of their selection
+	// by 10.
+	MaxComplexity int `yaml:"max_complexity"`
+}
+
+type Trash struct {
+	// Retention is how long deleted movies and characters can be restored
+	// before they are purged.
+	Retention time.Duration `yaml:"retention"`
+	// PurgeInterval is how often the trash is checked for expired entries.
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+}
+
+// Limit is a token bucket refilled with Rate tokens per second, holding at
+// most Burst. Env vars and flags write it as rate:burst, e.g. 5:10.
+type Limit struct {
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+}
+
+func (l Limit) String() string {
+	return strconv.FormatFloat(l.Rate, 'g', -1, 64) + ":" + strconv.Itoa(l.Burst)
+}
+
+// Secret is a config value that is never printed.
+type Secret string
+
+const redacted = "[REDACTED]"
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func (s Secret) MarshalYAML() (any, error) {
+	return s.String(), nil
+}
+
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Addr:            ":8080",
+			GRPCAddr:        ":9090",
+			TLSHosts:        []string{"localhost", "127.0.0.1", "::1"},
+			ShutdownTimeout: 10 * time.Second,
+		},
+		Certs: Certs{Dir: "certs"},
+		SWAPI: SWAPI{
+			BaseURL: "https://swapi.dev/api",
+			Timeout: 10 * time.Second,
+		},
+		TestData: TestData{Enabled: true},
+		Log: Log{
+			Level:  "info",
+			Format: "json",
+		},
+		Auth: Auth{Enabled: true},
+		RateLimit: RateLimit{
+			Enabled: true,
+			Read:    Limit{Rate: 20, Burst: 40},
+			Write:   Limit{Rate: 5, Burst: 10},
+			SWAPI:   Limit{Rate: 1, Burst: 5},
+			Tiers:   map[string]float64{"anonymous": 1, "viewer": 1, "editor": 2, "admin": 5},
+		},
+		Events: Events{
+			Buffer:    1000,
+			Heartbeat: 15 * time.Second,
+		},
+		Webhooks: Webhooks{
+			Workers:     4,
+			MaxAttempts: 6,
+			Backoff:     time.Second,
+			MaxBackoff:  5 * time.Minute,
+			Timeout:     10 * time.Second,
+		},
+		GraphQL: GraphQL{MaxComplexity: 2000},
+		Trash: Trash{
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+		Tracing: Tracing{
+			Exporter:    "none",
+			SampleRatio: 1,
+			ServiceName: "movie-character-api",
+		},
+	}
+}
+
+// setting binds one config field to its environment variable and flag.
+type setting struct {
+	env, flag, usage string
+	target           any
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{"SERVER_ADDR", "addr", "HTTP listen address", &c.Server.Addr},
+		{"TLS_ADDR", "tls-addr", "mutual TLS listen address, disabled when empty", &c.Server.TLSAddr},
+		{"GRPC_ADDR", "grpc-addr", "gRPC listen address, disabled when empty", &c.Server.GRPCAddr},
+		{"TLS_HOSTS", "tls-hosts", "comma separated host names of the TLS server certificate", &c.Server.TLSHosts},
+		{"DRAIN_DELAY", "drain-delay", "how long to keep serving after readiness fails on shutdown", &c.Server.DrainDelay},
+		{"SHUTDOWN_TIMEOUT", "shutdown-timeout", "deadline for in-flight requests on shutdown", &c.Server.ShutdownTimeout},
+		{"CERTS_DIR", "certs-dir", "directory holding the CA, movie and character certificates", &c.Certs.Dir},
+		{"SWAPI_BASE_URL", "swapi-url", "base URL of the Star Wars API", &c.SWAPI.BaseURL},
+		{"SWAPI_TIMEOUT", "swapi-timeout", "timeout of Star Wars API requests", &c.SWAPI.Timeout},
+		{"TESTDATA_ENABLED", "testdata", "load the seed movies and characters on startup", &c.TestData.Enabled},
+		{"HEALTH_CHECK_SWAPI", "health-swapi", "fail readiness while the Star Wars API is unreachable", &c.Health.SWAPI},
+		{"AUTH_ENABLED", "auth", "require credentials for mutating operations", &c.Auth.Enabled},
+		{"AUTH_ADMIN_KEY", "auth-admin-key", "API key with the admin role", &c.Auth.AdminKey},
+		{"AUTH_JWT_SECRET", "auth-jwt-secret", "HMAC secret of JWT bearer tokens, at least 32 bytes", &c.Auth.JWTSecret},
+		{"AUTH_JWT_ISSUER", "auth-jwt-issuer", "required iss claim of JWT bearer tokens", &c.Auth.JWTIssuer},
+		{"RATE_LIMIT_ENABLED", "rate-limit", "limit requests per client", &c.RateLimit.Enabled},
+		{"RATE_LIMIT_TRUST_PROXY", "rate-limit-trust-proxy", "identify clients by X-Forwarded-For", &c.RateLimit.TrustProxy},
+		{"RATE_LIMIT_READ", "rate-limit-read", "rate:burst of read requests per client", &c.RateLimit.Read},
+		{"RATE_LIMIT_WRITE", "rate-limit-write", "rate:burst of write requests per client", &c.RateLimit.Write},
+		{"RATE_LIMIT_SWAPI", "rate-limit-swapi", "rate:burst of SWAPI backed requests per client", &c.RateLimit.SWAPI},
+		{"EVENTS_BUFFER", "events-buffer", "number of changes kept for resuming the change feed", &c.Events.Buffer},
+		{"EVENTS_HEARTBEAT", "events-heartbeat", "keep-alive interval of change feed streams", &c.Events.Heartbeat},
+		{"WEBHOOK_WORKERS", "webhook-workers", "number of concurrent webhook deliveries", &c.Webhooks.Workers},
+		{"WEBHOOK_MAX_ATTEMPTS", "webhook-max-attempts", "attempts before a webhook delivery is dead-lettered", &c.Webhooks.MaxAttempts},
+		{"WEBHOOK_BACKOFF", "webhook-backoff", "delay before the first webhook retry, doubled per attempt", &c.Webhooks.Backoff},
+		{"WEBHOOK_MAX_BACKOFF", "webhook-max-backoff", "longest delay between webhook retries", &c.Webhooks.MaxBackoff},
+		{"WEBHOOK_TIMEOUT", "webhook-timeout", "timeout of a webhook delivery request", &c.Webhooks.Timeout},
+		{"GRAPHQL_MAX_COMPLEXITY", "graphql-max-complexity", "highest cost of a GraphQL query", &c.GraphQL.MaxComplexity},
+		{"TRASH_RETENTION", "trash-retention", "how long deleted movies and characters can be restored", &c.Trash.Retention},
+		{"TRASH_PURGE_INTERVAL", "trash-purge-interval", "interval of purging expired trash entries", &c.Trash.PurgeInterval},
+		{"LOG_LEVEL", "log-level", "minimum log level: debug, info, warn or error", &c.Log.Level},
+		{"LOG_FORMAT", "log-format", "log output format: json or console", &c.Log.Format},
+		{"TRACING_EXPORTER", "trace-exporter", "trace exporter: none, stdout or otlp", &c.Tracing.Exporter},
+		{"TRACING_ENDPOINT", "trace-endpoint", "OTLP/HTTP collector URL", &c.Tracing.Endpoint},
+		{"TRACING_SAMPLE_RATIO", "trace-sample-ratio", "fraction of new traces to record, 0 to 1", &c.Tracing.SampleRatio},
+		{"TRACING_SERVICE_NAME", "trace-service-name", "service name reported with every span", &c.Tracing.ServiceName},
+	}
+}
+
+// Load builds the config for the command line args. The YAML file is taken
+// from -config or CONFIG_FILE.
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	c := Default()
+	settings := c.settings()
+
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	configFile, _ := lookupEnv("CONFIG_FILE")
+	fs.StringVar(&configFile, "config", configFile, "YAML config file")
+	fs.BoolVar(&c.PrintConfig, "print-config", false, "print the config with secrets redacted and exit")
+	flags := map[string]string{}
+	for _, s := range settings {
+		record := func(v string) error {
+			flags[s.flag] = v
+			return nil
+		}
+		if _, ok := s.target.(*bool); ok {
+			fs.BoolFunc(s.flag, s.usage, record)
+		} else {
+			fs.Func(s.flag, s.usage, record)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if configFile != "" {
+		if err := c.loadFile(configFile); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range settings {
+		if v, ok := lookupEnv(s.env); ok {
+			if err := set(s.target, v); err != nil {
+				return nil, fmt.Errorf("%s: %w", s.env, err)
+			}
+		}
+	}
+	for _, s := range settings {
+		if v, ok := flags[s.flag]; ok {
+			if err := set(s.target, v); err != nil {
+				return nil, fmt.Errorf("-%s: %w", s.flag, err)
+			}
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	defer f.Close()
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func set(target any, v string) error {
+	switch t := target.(type) {
+	case *string:
+		*t = v
+	case *[]string:
+		*t = nil
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				*t = append(*t, s)
+			}
+		}
+	case *bool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		*t = b
+	case *int:
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		*t = n
+	case *float64:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		*t = f
+	case *time.Duration:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*t = d
+	case *Secret:
+		*t = Secret(v)
+	case *Limit:
+		rate, burst, ok := strings.Cut(v, ":")
+		if !ok {
+			return fmt.Errorf("%q is not rate:burst", v)
+		}
+		r, err := strconv.ParseFloat(rate, 64)
+		if err != nil {
+			return err
+		}
+		b, err := strconv.Atoi(burst)
+		if err != nil {
+			return err
+		}
+		*t = Limit{Rate: r, Burst: b}
+	default:
+		return fmt.Errorf("unsupported setting type %T", target)
+	}
+	return nil
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	if _, _, err := net.SplitHostPort(c.Server.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("server.addr: %w", err))
+	}
+	if c.Server.TLSAddr != "" {
+		if _, _, err := net.SplitHostPort(c.Server.TLSAddr); err != nil {
+			errs = append(errs, fmt.Errorf("server.tls_addr: %w", err))
+		}
+		if len(c.Server.TLSHosts) == 0 {
+			errs = append(errs, errors.New("server.tls_hosts: required when tls_addr is set"))
+		}
+	}
+	if c.Server.GRPCAddr != "" {
+		if _, _, err := net.SplitHostPort(c.Server.GRPCAddr); err != nil {
+			errs = append(errs, fmt.Errorf("server.grpc_addr: %w", err))
+		}
+	}
+	if c.Server.DrainDelay < 0 {
+		errs = append(errs, errors.New("server.drain_delay: must not be negative"))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server.shutdown_timeout: must be positive"))
+	}
+	if c.Certs.Dir == "" {
+		errs = append(errs, errors.New("certs.dir: required"))
+	}
+	if u, err := url.Parse(c.SWAPI.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("swapi.base_url: %q is not an absolute http(s) URL", c.SWAPI.BaseURL))
+	}
+	if c.SWAPI.Timeout <= 0 {
+		errs = append(errs, errors.New("swapi.timeout: must be positive"))
+	}
+	if n := len(c.Auth.JWTSecret); n > 0 && n < 32 {
+		errs = append(errs, fmt.Errorf("auth.jwt_secret: %d bytes is too short for HS256, use at least 32", n))
+	}
+	limits := map[string]Limit{
+		"read":  c.RateLimit.Read,
+		"write": c.RateLimit.Write,
+		"swapi": c.RateLimit.SWAPI,
+	}
+	for route, l := range c.RateLimit.Routes {
+		limits["routes."+route] = l
+	}
+	for name, l := range limits {
+		if l.Rate <= 0 || l.Burst < 1 {
+			errs = append(errs, fmt.Errorf("rate_limit.%s: %s needs a positive rate and a burst of at least 1", name, l))
+		}
+	}
+	for tier, factor := range c.RateLimit.Tiers {
+		switch tier {
+		case "anonymous", "viewer", "editor", "admin":
+		default:
+			errs = append(errs, fmt.Errorf("rate_limit.tiers: unknown tier %q", tier))
+		}
+		if factor < 0 {
+			errs = append(errs, fmt.Errorf("rate_limit.tiers.%s: must not be negative", tier))
+		}
+	}
+	if c.Events.Buffer < 1 {
+		errs = append(errs, errors.New("events.buffer: must be at least 1"))
+	}
+	if c.Events.Heartbeat <= 0 {
+		errs = append(errs, errors.New("events.heartbeat: must be positive"))
+	}
+	if c.Webhooks.Workers < 1 {
+		errs = append(errs, errors.New("webhooks.workers: must be at least 1"))
+	}
+	if c.Webhooks.MaxAttempts < 1 {
+		errs = append(errs, errors.New("webhooks.max_attempts: must be at least 1"))
+	}
+	if c.Webhooks.Backoff <= 0 || c.Webhooks.MaxBackoff < c.Webhooks.Backoff {
+		errs = append(errs, errors.New("webhooks.backoff: must be positive and not above max_backoff"))
+	}
+	if c.Webhooks.Timeout <= 0 {
+		errs = append(errs, errors.New("webhooks.timeout: must be positive"))
+	}
+	if c.GraphQL.MaxComplexity < 1 {
+		errs = append(errs, errors.New("graphql.max_complexity: must be at least 1"))
+	}
+	if c.Trash.Retention <= 0 {
+		errs = append(errs, errors.New("trash.retention: must be positive"))
+	}
+	if c.Trash.PurgeInterval <= 0 {
+		errs = append(errs, errors.New("trash.purge_interval: must be positive"))
+	}
+	if _, err := zapcore.ParseLevel(c.Log.Level); err != nil {
+		errs = append(errs, fmt.Errorf("log.level: %w", err))
+	}
+	if c.Log.Format != "json" && c.Log.Format != "console" {
+		errs = append(errs, fmt.Errorf("log.format: %q is neither json nor console", c.Log.Format))
+	}
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		if c.Tracing.Endpoint != "" {
+			if u, err := url.Parse(c.Tracing.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				errs = append(errs, fmt.Errorf("tracing.endpoint: %q is not an absolute http(s) URL", c.Tracing.Endpoint))
+			}
+		}
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter: %q is not one of none, stdout or otlp", c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sample_ratio: %g is not between 0 and 1", c.Tracing.SampleRatio))
+	}
+	if c.Tracing.ServiceName == "" {
+		errs = append(errs, errors.New("tracing.service_name: required"))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Print writes the config as YAML. Secret values are redacted.
+func (c *Config) Print(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	return enc.Close()
+}
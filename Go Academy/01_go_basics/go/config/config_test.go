@@ -0,0 +1,92 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+server:
+  addr: ":9000"
+certs:
+  dir: /etc/movies/certs
+swapi:
+  timeout: 3s
+`), 0644))
+
+	cfg, err := Load([]string{"-addr", ":9100", "-testdata=false"}, env(map[string]string{
+		"CONFIG_FILE":      file,
+		"SERVER_ADDR":      ":9050",
+		"SWAPI_TIMEOUT":    "5s",
+		"TLS_HOSTS":        "movies.local, 10.0.0.1",
+		"RATE_LIMIT_WRITE": "0.5:4",
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, ":9100", cfg.Server.Addr)
+	assert.Equal(t, "/etc/movies/certs", cfg.Certs.Dir)
+	assert.Equal(t, 5*time.Second, cfg.SWAPI.Timeout)
+	assert.Equal(t, "https://swapi.dev/api", cfg.SWAPI.BaseURL)
+	assert.Equal(t, []string{"movies.local", "10.0.0.1"}, cfg.Server.TLSHosts)
+	assert.False(t, cfg.TestData.Enabled)
+	assert.Equal(t, Limit{Rate: 0.5, Burst: 4}, cfg.RateLimit.Write)
+}
+
+func TestLoadErrors(t *testing.T) {
+	_, err := Load(nil, env(map[string]string{"SWAPI_TIMEOUT": "soon"}))
+	assert.ErrorContains(t, err, "SWAPI_TIMEOUT")
+
+	_, err = Load([]string{"-rate-limit-read", "20"}, env(nil))
+	assert.ErrorContains(t, err, "-rate-limit-read")
+
+	_, err = Load([]string{"-config", filepath.Join(t.TempDir(), "missing.yaml")}, env(nil))
+	assert.ErrorContains(t, err, "config file")
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("server:\n  port: 8080\n"), 0644))
+	_, err = Load([]string{"-config", file}, env(nil))
+	assert.ErrorContains(t, err, "field port not found")
+
+	_, err = Load([]string{"-addr", "8080", "-swapi-url", "swapi.dev", "-log-level", "loud", "-trace-sample-ratio", "2", "-rate-limit-swapi", "0:5", "-graphql-max-complexity", "0", "-grpc-addr", "9090", "-trash-retention", "0s"}, env(map[string]string{"LOG_FORMAT": "xml", "TRACING_EXPORTER": "zipkin"}))
+	assert.ErrorContains(t, err, "server.addr")
+	assert.ErrorContains(t, err, "swapi.base_url")
+	assert.ErrorContains(t, err, "log.level")
+	assert.ErrorContains(t, err, "log.format")
+	assert.ErrorContains(t, err, "tracing.exporter")
+	assert.ErrorContains(t, err, "tracing.sample_ratio")
+	assert.ErrorContains(t, err, "rate_limit.swapi")
+	assert.ErrorContains(t, err, "graphql.max_complexity")
+	assert.ErrorContains(t, err, "server.grpc_addr")
+	assert.ErrorContains(t, err, "trash.retention")
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Default().Print(&out))
+	assert.Contains(t, out.String(), "addr: :8080")
+
+	secrets, err := yaml.Marshal(struct {
+		Token Secret `yaml:"token"`
+		Empty Secret `yaml:"empty"`
+	}{Token: "s3cr3t"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(secrets), "s3cr3t")
+	assert.Contains(t, string(secrets), "token: '[REDACTED]'")
+	assert.Contains(t, string(secrets), `empty: ""`)
+}
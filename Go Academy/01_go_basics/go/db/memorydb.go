@@ -2,19 +2,59 @@ package db
 
 import (
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"example.com/go_basics/go/entity"
+	"github.com/google/uuid"
 )
 
 type MemoryDB struct {
 	Movies      sync.Map
 	Characters  sync.Map
 	Appearances []entity.Appearance
-	Mutex       sync.Mutex
+	// Trash holds the deleted movies and characters by ID. Mutex guards it
+	// along with Appearances, so a deletion moves its appearances at once.
+	Trash map[uuid.UUID]Deleted
+	// Franchises holds the franchises by ID. Mutex guards it too.
+	Franchises map[uuid.UUID]entity.Franchise
+	Mutex      sync.Mutex
+	// Writes is read-locked by every change and locked by snapshots and
+	// batches, so they see no change half done. Plain reads do not take it.
+	Writes sync.RWMutex
+	// Changes counts the changes made, so caches can tell they are stale.
+	// It grows while Writes is held.
+	Changes atomic.Uint64
+}
+
+// Deleted is a movie or character in the trash with the appearances its
+// deletion dropped.
+type Deleted struct {
+	Movie       *entity.Movie
+	Character   *entity.Character
+	Appearances []entity.Appearance
+}
+
+// ID returns the ID of the deleted movie or character.
+func (d Deleted) ID() uuid.UUID {
+	if d.Movie != nil {
+		return d.Movie.ID
+	}
+	return d.Character.ID
+}
+
+// DeletedAt returns when the movie or character was deleted.
+func (d Deleted) DeletedAt() time.Time {
+	if d.Movie != nil {
+		return *d.Movie.DeletedAt
+	}
+	return *d.Character.DeletedAt
 }
 
 func New() *MemoryDB {
 	return &MemoryDB{
 		Appearances: make([]entity.Appearance, 0),
+		Trash:       make(map[uuid.UUID]Deleted),
+		Franchises:  make(map[uuid.UUID]entity.Franchise),
 	}
 }
@@ -1,16 +1,25 @@
 package entity
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Character struct {
 	ID    uuid.UUID
 	Name  string `json:"name" validate:"required"`
 	Movie string `json:"movie"` // test - only for http resty request
+	// Version starts at 1 and grows with every update. It is sent as ETag.
+	Version int64 `json:"version"`
+	// DeletedAt is set while the character is in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 func NewCharacter(options ...func(*Character)) Character {
 	char := Character{
-		ID: uuid.New(),
+		ID:      uuid.New(),
+		Version: 1,
 	}
 	for _, o := range options {
 		o(&char)
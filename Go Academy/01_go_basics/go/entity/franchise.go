@@ -0,0 +1,59 @@
+package entity
+
+import "github.com/google/uuid"
+
+// Installment kinds.
+const (
+	Original = "original"
+	Sequel   = "sequel"
+	Prequel  = "prequel"
+	SpinOff  = "spin-off"
+)
+
+// InstallmentKinds lists the kinds an installment may have.
+var InstallmentKinds = []string{Original, Sequel, Prequel, SpinOff}
+
+// Franchise groups movies into a series. Its slices are replaced, never
+// changed in place, so copies can share them.
+type Franchise struct {
+	ID   uuid.UUID
+	Name string `json:"name" validate:"required"`
+	// Installments are the movies of the franchise in order.
+	Installments []Installment `json:"installments"`
+	// Characters are linked to the franchise as a whole, whatever movies
+	// they appear in.
+	Characters []uuid.UUID `json:"characters"`
+	// Version starts at 1 and grows with every update. It is sent as ETag.
+	Version int64 `json:"version"`
+}
+
+// Installment is a movie of a franchise.
+type Installment struct {
+	MovieID uuid.UUID `json:"movie_id"`
+	Kind    string    `json:"kind"`
+}
+
+func NewFranchise(options ...func(*Franchise)) Franchise {
+	f := Franchise{
+		ID:           uuid.New(),
+		Installments: []Installment{},
+		Characters:   []uuid.UUID{},
+		Version:      1,
+	}
+	for _, o := range options {
+		o(&f)
+	}
+	return f
+}
+
+func WithFranchiseName(name string) func(*Franchise) {
+	return func(f *Franchise) {
+		f.Name = name
+	}
+}
+
+func WithInstallments(installments ...Installment) func(*Franchise) {
+	return func(f *Franchise) {
+		f.Installments = installments
+	}
+}
@@ -1,16 +1,25 @@
 package entity
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Movie struct {
 	ID    uuid.UUID
 	Title string `json:"title" validate:"required"`
-	Year  int `json:"year" validate:"required,min=1900"`
+	Year  int    `json:"year" validate:"required,min=1900"`
+	// Version starts at 1 and grows with every update. It is sent as ETag.
+	Version int64 `json:"version"`
+	// DeletedAt is set while the movie is in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 func NewMovie(options ...func(*Movie)) Movie {
 	mov := Movie{
-		ID: uuid.New(),
+		ID:      uuid.New(),
+		Version: 1,
 	}
 	for _, o := range options {
 		o(&mov)
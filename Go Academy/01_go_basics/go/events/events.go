@@ -0,0 +1,209 @@
+package events
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"example.com/go_basics/go/config"
+)
+
+type Type string
+
+const (
+	MovieCreated       Type = "movie.created"
+	MovieUpdated       Type = "movie.updated"
+	MovieDeleted       Type = "movie.deleted"
+	MovieRestored      Type = "movie.restored"
+	CharacterCreated   Type = "character.created"
+	CharacterUpdated   Type = "character.updated"
+	CharacterDeleted   Type = "character.deleted"
+	CharacterRestored  Type = "character.restored"
+	AppearanceLinked   Type = "appearance.linked"
+	AppearanceUnlinked Type = "appearance.unlinked"
+	FranchiseCreated   Type = "franchise.created"
+	FranchiseUpdated   Type = "franchise.updated"
+	FranchiseDeleted   Type = "franchise.deleted"
+	// Reset tells a resuming client that the changes it missed are no longer
+	// buffered, so it has to reload its data.
+	Reset Type = "reset"
+)
+
+// Types lists the change types, without Reset.
+var Types = []Type{
+	MovieCreated, MovieUpdated, MovieDeleted, MovieRestored,
+	CharacterCreated, CharacterUpdated, CharacterDeleted, CharacterRestored,
+	AppearanceLinked, AppearanceUnlinked,
+	FranchiseCreated, FranchiseUpdated, FranchiseDeleted,
+}
+
+// subscriberBuffer is how far a subscriber may fall behind before it is
+// dropped. It can resume from the replay buffer once it reconnects.
+const subscriberBuffer = 64
+
+// seqBits is how many low bits of an event ID count the events of a bus.
+// The bits above hold the epoch of the bus, so an ID handed out before a
+// restart is never taken for one of the current process. IDs stay below
+// 2^53, which JavaScript numbers hold exactly.
+const (
+	seqBits   = 33
+	epochBits = 53 - seqBits
+)
+
+// lastEpoch keeps buses created in the same millisecond apart.
+var lastEpoch atomic.Uint64
+
+// newEpoch derives a non-zero epoch from the boot time in milliseconds.
+func newEpoch() uint64 {
+	for {
+		prev := lastEpoch.Load()
+		epoch := uint64(time.Now().UnixMilli())%(1<<epochBits-1) + 1
+		if epoch == prev {
+			epoch = epoch%(1<<epochBits-1) + 1
+		}
+		if lastEpoch.CompareAndSwap(prev, epoch) {
+			return epoch
+		}
+	}
+}
+
+// Event is one change. IDs grow by one per event within a process; their
+// upper bits change when the server restarts.
+type Event struct {
+	ID   uint64    `json:"id"`
+	Type Type      `json:"type"`
+	Time time.Time `json:"time"`
+	Data any       `json:"data,omitempty"`
+}
+
+// Bus fans out repository changes to subscribers and keeps the most recent
+// ones for clients that reconnect.
+type Bus struct {
+	mu          sync.Mutex
+	epoch       uint64
+	lastID      uint64
+	replay      []Event // ring buffer, next is the slot of the oldest event once full
+	next        int
+	subscribers map[*Subscription]struct{}
+	closed      bool
+	heartbeat   time.Duration
+}
+
+func New(cfg *config.Config) *Bus {
+	epoch := newEpoch()
+	return &Bus{
+		epoch:       epoch,
+		lastID:      epoch << seqBits,
+		replay:      make([]Event, 0, cfg.Events.Buffer),
+		subscribers: make(map[*Subscription]struct{}),
+		heartbeat:   cfg.Events.Heartbeat,
+	}
+}
+
+// Publish records a change. It never blocks: subscribers that are too far
+// behind are dropped.
+func (b *Bus) Publish(typ Type, data any) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastID++
+	e := Event{ID: b.lastID, Type: typ, Time: time.Now().UTC(), Data: data}
+	if len(b.replay) < cap(b.replay) {
+		b.replay = append(b.replay, e)
+	} else {
+		b.replay[b.next] = e
+		b.next = (b.next + 1) % len(b.replay)
+	}
+	for s := range b.subscribers {
+		select {
+		case s.ch <- e:
+		default:
+			b.remove(s)
+		}
+	}
+}
+
+// Subscription delivers the events published after it was created. C is
+// closed when the subscriber fell behind or the bus was closed.
+type Subscription struct {
+	C   <-chan Event
+	ch  chan Event
+	bus *Bus
+}
+
+// LastID returns the ID of the latest event. Resuming after it replays
+// nothing.
+func (b *Bus) LastID() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastID
+}
+
+// Subscribe starts a subscription. A non-zero lastID resumes after that
+// event: the buffered events following it are returned for replay, or a
+// single Reset event when some of them are gone or lastID is from another
+// process.
+func (b *Bus) Subscribe(lastID uint64) (*Subscription, []Event) {
+	ch := make(chan Event, subscriberBuffer)
+	s := &Subscription{C: ch, ch: ch, bus: b}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return s, nil
+	}
+	b.subscribers[s] = struct{}{}
+	if lastID == 0 {
+		return s, nil
+	}
+	buffered := b.buffered()
+	oldest := b.lastID + 1
+	if len(buffered) > 0 {
+		oldest = buffered[0].ID
+	}
+	// A lastID of another epoch was handed out before a restart.
+	if lastID>>seqBits != b.epoch || lastID > b.lastID || lastID+1 < oldest {
+		return s, []Event{{ID: b.lastID, Type: Reset, Time: time.Now().UTC()}}
+	}
+	return s, buffered[lastID+1-oldest:]
+}
+
+// buffered copies the replay buffer, oldest first.
+func (b *Bus) buffered() []Event {
+	return slices.Concat(b.replay[b.next:], b.replay[:b.next])
+}
+
+// Close ends the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.remove(s)
+}
+
+func (b *Bus) remove(s *Subscription) {
+	if _, ok := b.subscribers[s]; ok {
+		delete(b.subscribers, s)
+		close(s.ch)
+	}
+}
+
+// Closed reports whether Close was called.
+func (b *Bus) Closed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+// Close ends every subscription, so open streams finish and the server can
+// shut down. Later subscriptions are closed right away.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for s := range b.subscribers {
+		b.remove(s)
+	}
+}
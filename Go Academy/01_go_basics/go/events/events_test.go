@@ -0,0 +1,200 @@
+package events_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/events"
+	"example.com/go_basics/go/handlers"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/routes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+func newBus(buffer int) *events.Bus {
+	cfg := config.Default()
+	cfg.Events.Buffer = buffer
+	return events.New(cfg)
+}
+
+func ids(es []events.Event) []uint64 {
+	var out []uint64
+	for _, e := range es {
+		out = append(out, e.ID)
+	}
+	return out
+}
+
+func TestReplay(t *testing.T) {
+	bus := newBus(3)
+	// id(n) is the ID of the nth event.
+	base := bus.LastID()
+	id := func(n uint64) uint64 { return base + n }
+	for range 5 {
+		bus.Publish(events.MovieCreated, nil)
+	}
+
+	_, replay := bus.Subscribe(0)
+	assert.Empty(t, replay)
+	_, replay = bus.Subscribe(id(3))
+	assert.Equal(t, []uint64{id(4), id(5)}, ids(replay))
+	_, replay = bus.Subscribe(id(2))
+	assert.Equal(t, []uint64{id(3), id(4), id(5)}, ids(replay))
+	_, replay = bus.Subscribe(id(5))
+	assert.Empty(t, replay)
+	_, replay = bus.Subscribe(id(0))
+	assert.Equal(t, events.Reset, replay[0].Type)
+
+	// Event 2 is gone, 9 was not handed out yet, and the events of another
+	// bus, as before a restart, are never replayed.
+	restarted := newBus(3)
+	restarted.Publish(events.MovieCreated, nil)
+	for _, lastID := range []uint64{id(1), id(9), 9, restarted.LastID() - 1} {
+		_, replay = bus.Subscribe(lastID)
+		require.Len(t, replay, 1)
+		assert.Equal(t, events.Reset, replay[0].Type)
+		assert.Equal(t, id(5), replay[0].ID)
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	bus := newBus(1000)
+	base := bus.LastID()
+	slow, _ := bus.Subscribe(0)
+	fast, _ := bus.Subscribe(0)
+	for range 100 {
+		bus.Publish(events.CharacterUpdated, nil)
+		<-fast.C
+	}
+
+	received := 0
+	for range slow.C {
+		received++
+	}
+	assert.Less(t, received, 100)
+
+	// Where it left off is still buffered.
+	_, replay := bus.Subscribe(base + uint64(received))
+	assert.Len(t, replay, 100-received)
+
+	bus.Close()
+	_, ok := <-fast.C
+	assert.False(t, ok)
+	closed, _ := bus.Subscribe(0)
+	_, ok = <-closed.C
+	assert.False(t, ok)
+}
+
+func newServer(t *testing.T) (*httptest.Server, *repository.Repository) {
+	cfg := config.Default()
+	cfg.Events.Heartbeat = 50 * time.Millisecond
+	bus := events.New(cfg)
+	repo := repository.New(db.New(), nil, bus, nil)
+	h := handlers.New(repo, &pki.Authority{Dir: t.TempDir()}, nil, nil, nil, nil, nil)
+	server := httptest.NewServer(routes.NewEchoRouter(h, nil, zap.NewNop(), nil, nil, nil, nil))
+	t.Cleanup(func() {
+		bus.Close()
+		server.Close()
+	})
+	return server, repo
+}
+
+// readSSE returns the next event of the stream, skipping pings.
+func readSSE(t *testing.T, r *bufio.Reader) (id, typ string, e events.Event) {
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		switch line = strings.TrimSuffix(line, "\n"); {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			typ = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e))
+		case line == "" && id != "":
+			return id, typ, e
+		}
+	}
+}
+
+func TestServerSentEvents(t *testing.T) {
+	server, repo := newServer(t)
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	first := repo.Events.LastID()
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL+"/events", nil)
+	req.Header.Set("Last-Event-ID", strconv.FormatUint(first, 10))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	r := bufio.NewReader(resp.Body)
+
+	id, typ, _ := readSSE(t, r)
+	assert.Equal(t, strconv.FormatUint(first+1, 10), id)
+	assert.Equal(t, "character.created", typ)
+
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek.ID, donkey.ID))
+	require.NoError(t, repo.DeleteMovie(t.Context(), shrek.ID, repository.AnyVersion))
+	id, typ, e := readSSE(t, r)
+	assert.Equal(t, strconv.FormatUint(first+2, 10), id)
+	assert.Equal(t, "appearance.linked", typ)
+	assert.Equal(t, map[string]any{"movie_id": shrek.ID.String(), "character_id": donkey.ID.String()}, e.Data)
+	_, typ, _ = readSSE(t, r)
+	assert.Equal(t, "appearance.unlinked", typ)
+	_, typ, e = readSSE(t, r)
+	assert.Equal(t, "movie.deleted", typ)
+	assert.Equal(t, "Shrek", e.Data.(map[string]any)["title"])
+
+	line, err := r.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, ": ping\n", line)
+
+	resp, err = http.Get(server.URL + "/events?signed=true")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotAcceptable, resp.StatusCode)
+}
+
+func TestWebSocket(t *testing.T) {
+	server, repo := newServer(t)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	first := repo.Events.LastID()
+
+	// Resuming after the first event cannot miss the update, even if it
+	// happens before the server subscribed.
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/events/ws?last_event_id="
+	ws, err := websocket.Dial(url+strconv.FormatUint(first, 10), "", server.URL)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	_, err = repo.UpdateCharacter(t.Context(), donkey.ID, "Donkey the Brave", repository.AnyVersion)
+	require.NoError(t, err)
+	var e events.Event
+	require.NoError(t, websocket.JSON.Receive(ws, &e))
+	assert.Equal(t, first+1, e.ID)
+	assert.Equal(t, events.CharacterUpdated, e.Type)
+	assert.Equal(t, "Donkey the Brave", e.Data.(map[string]any)["name"])
+
+	// An ID from before a restart.
+	stale, err := websocket.Dial(url+"9", "", server.URL)
+	require.NoError(t, err)
+	defer stale.Close()
+	require.NoError(t, websocket.JSON.Receive(stale, &e))
+	assert.Equal(t, events.Reset, e.Type)
+	assert.Equal(t, first+1, e.ID)
+}
@@ -0,0 +1,58 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Stream sends the events after lastID to a client until ctx is done, the
+// client falls behind or the bus closes. Idle streams get a ping every
+// heartbeat, so proxies keep the connection open.
+func (b *Bus) Stream(ctx context.Context, lastID uint64, send func(Event) error, ping func() error) error {
+	sub, replay := b.Subscribe(lastID)
+	defer sub.Close()
+	for _, e := range replay {
+		if err := send(e); err != nil {
+			return err
+		}
+	}
+	ticker := time.NewTicker(b.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			if err := send(e); err != nil {
+				return err
+			}
+			ticker.Reset(b.heartbeat)
+		case <-ticker.C:
+			if err := ping(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// WriteSSE writes e as a server-sent event whose data is the JSON event.
+func WriteSSE(w io.Writer, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
+
+// WriteSSEPing writes a comment line, which clients ignore.
+func WriteSSEPing(w io.Writer) error {
+	_, err := io.WriteString(w, ": ping\n\n")
+	return err
+}
@@ -0,0 +1,104 @@
+package graphql
+
+import (
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// listFactor is the assumed length of a list, by which the cost of the
+// selection below a list field is multiplied.
+const listFactor = 10
+
+// complexity returns the cost of the operation to run: 1 per field, with
+// the selections of list fields multiplied by listFactor. Introspection
+// fields cost 1 each. The document must be valid.
+func (s *Server) complexity(doc *ast.Document, operationName string) int {
+	c := costs{
+		schema:    &s.schema,
+		fragments: make(map[string]*ast.FragmentDefinition),
+		limit:     s.maxComplexity,
+	}
+	var op *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.FragmentDefinition:
+			c.fragments[def.Name.Value] = def
+		case *ast.OperationDefinition:
+			if operationName == "" || (def.Name != nil && def.Name.Value == operationName) {
+				op = def
+			}
+		}
+	}
+	if op == nil {
+		return 0
+	}
+	root := s.schema.QueryType()
+	switch op.Operation {
+	case ast.OperationTypeMutation:
+		root = s.schema.MutationType()
+	case ast.OperationTypeSubscription:
+		root = s.schema.SubscriptionType()
+	}
+	return c.selection(op.SelectionSet, root)
+}
+
+type costs struct {
+	schema    *gql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	// limit stops the count once it is exceeded, which keeps deeply
+	// nested lists from overflowing.
+	limit int
+}
+
+func (c *costs) selection(set *ast.SelectionSet, parent *gql.Object) int {
+	if set == nil {
+		return 0
+	}
+	total := 0
+	for _, sel := range set.Selections {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			total += c.field(sel, parent)
+		case *ast.InlineFragment:
+			total += c.selection(sel.SelectionSet, c.condition(sel.TypeCondition, parent))
+		case *ast.FragmentSpread:
+			if def, ok := c.fragments[sel.Name.Value]; ok {
+				total += c.selection(def.SelectionSet, c.condition(def.TypeCondition, parent))
+			}
+		}
+		if total > c.limit {
+			break
+		}
+	}
+	return total
+}
+
+func (c *costs) field(f *ast.Field, parent *gql.Object) int {
+	var child *gql.Object
+	factor := 1
+	if parent != nil {
+		if def, ok := parent.Fields()[f.Name.Value]; ok {
+			t := def.Type
+			if nn, ok := t.(*gql.NonNull); ok {
+				t = nn.OfType
+			}
+			if list, ok := t.(*gql.List); ok {
+				factor = listFactor
+				t = list.OfType
+				if nn, ok := t.(*gql.NonNull); ok {
+					t = nn.OfType
+				}
+			}
+			child, _ = t.(*gql.Object)
+		}
+	}
+	return 1 + factor*c.selection(f.SelectionSet, child)
+}
+
+func (c *costs) condition(named *ast.Named, parent *gql.Object) *gql.Object {
+	if named == nil {
+		return parent
+	}
+	t, _ := c.schema.Type(named.Name.Value).(*gql.Object)
+	return t
+}
@@ -0,0 +1,64 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"example.com/go_basics/go/logging"
+	"example.com/go_basics/go/repository"
+	"go.uber.org/zap"
+)
+
+// Codes put in the extensions of GraphQL errors. They match the HTTP status
+// the REST API answers with.
+const (
+	CodeBadUserInput       = "BAD_USER_INPUT"
+	CodeUnauthenticated    = "UNAUTHENTICATED"
+	CodeForbidden          = "FORBIDDEN"
+	CodeNotFound           = "NOT_FOUND"
+	CodePreconditionFailed = "PRECONDITION_FAILED"
+	CodeBadGateway         = "BAD_GATEWAY"
+	CodeRateLimited        = "RATE_LIMITED"
+	CodeTooComplex         = "COMPLEXITY_LIMIT"
+	CodeInternal           = "INTERNAL"
+)
+
+// codedError adds its code to the extensions of the GraphQL error.
+type codedError struct {
+	code string
+	err  error
+}
+
+func coded(code string, err error) error {
+	return codedError{code: code, err: err}
+}
+
+func (e codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e codedError) Unwrap() error {
+	return e.err
+}
+
+func (e codedError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+// present gives a resolver error its code. Unexpected errors are logged and
+// hidden from the client, as HTTPErrorHandler does for REST.
+func present(ctx context.Context, err error) error {
+	var c codedError
+	switch {
+	case errors.As(err, &c):
+		return err
+	case errors.Is(err, repository.ErrNotFound):
+		return coded(CodeNotFound, err)
+	case errors.Is(err, repository.ErrInvalidInput):
+		return coded(CodeBadUserInput, err)
+	case errors.Is(err, repository.ErrVersionMismatch):
+		return coded(CodePreconditionFailed, err)
+	}
+	logging.FromContext(ctx).Error("GraphQL resolver failed", zap.Error(err))
+	return coded(CodeInternal, errors.New("An unexpected error occurred"))
+}
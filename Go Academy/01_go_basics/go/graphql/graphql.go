@@ -0,0 +1,86 @@
+// Package graphql serves the movie/character graph as GraphQL. Nested
+// relations are batched per query level, so a page of movies with their
+// characters and each character's movies costs one repository call per
+// level instead of one per parent.
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/swapi"
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// GraphiQL is the in-browser IDE served on GET /graphql.
+//
+//go:embed graphiql.html
+var GraphiQL []byte
+
+// Request is a GraphQL request as sent over HTTP.
+type Request struct {
+	Query         string
+	OperationName string
+	Variables     map[string]any
+}
+
+// Server runs GraphQL requests against the repository.
+type Server struct {
+	schema        gql.Schema
+	repo          *repository.Repository
+	swapi         *swapi.Client
+	maxComplexity int
+	authEnabled   bool
+}
+
+func New(cfg *config.Config, repo *repository.Repository, sw *swapi.Client) (*Server, error) {
+	s := &Server{
+		repo:          repo,
+		swapi:         sw,
+		maxComplexity: cfg.GraphQL.MaxComplexity,
+		authEnabled:   cfg.Auth.Enabled,
+	}
+	schema, err := s.newSchema()
+	if err != nil {
+		return nil, fmt.Errorf("building GraphQL schema: %w", err)
+	}
+	s.schema = schema
+	return s, nil
+}
+
+// Execute runs a query or mutation. As GraphQL over HTTP expects, every
+// failure is reported in the errors of the result. Queries above the
+// complexity limit are rejected before any resolver runs.
+func (s *Server) Execute(ctx context.Context, req Request) *gql.Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return &gql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	if v := gql.ValidateDocument(&s.schema, doc, gql.SpecifiedRules); !v.IsValid {
+		return &gql.Result{Errors: v.Errors}
+	}
+	if cost := s.complexity(doc, req.OperationName); cost > s.maxComplexity {
+		return &gql.Result{Errors: []gqlerrors.FormattedError{{
+			Message:    fmt.Sprintf("Query complexity %d exceeds the limit of %d", cost, s.maxComplexity),
+			Locations:  []location.SourceLocation{},
+			Extensions: map[string]any{"code": CodeTooComplex},
+		}}}
+	}
+	return gql.Execute(gql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoaders(ctx, s.newLoaders(ctx)),
+	})
+}
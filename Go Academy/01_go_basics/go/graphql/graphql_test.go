@@ -0,0 +1,181 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/graphql"
+	"example.com/go_basics/go/handlers"
+	"example.com/go_basics/go/metrics"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/routes"
+	"example.com/go_basics/go/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const adminKey = "mca_test-admin-key"
+
+func newRouter(t *testing.T) (http.Handler, *repository.Repository) {
+	cfg := config.Default()
+	cfg.Auth.AdminKey = adminKey
+	store := db.New()
+	ca := &pki.Authority{Dir: t.TempDir()}
+	m := metrics.New(store, ca)
+	repo := repository.New(store, m, nil, nil)
+	gq, err := graphql.New(cfg, repo, nil)
+	require.NoError(t, err)
+	keys := auth.NewKeyStore(cfg)
+	h := handlers.New(repo, ca, nil, nil, keys, nil, gq)
+	e := routes.NewEchoRouter(h, nil, zap.NewNop(), m, nil, auth.New(cfg, keys), nil)
+	spec, err := api.GetSwagger()
+	require.NoError(t, err)
+	e.Use(validation.ResponseMiddleware(spec))
+	return e, repo
+}
+
+type result struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func (r result) codes() []any {
+	var codes []any
+	for _, e := range r.Errors {
+		codes = append(codes, e.Extensions["code"])
+	}
+	return codes
+}
+
+func query(t *testing.T, e http.Handler, q string, variables map[string]any, header ...string) result {
+	body, err := json.Marshal(map[string]any{"query": q, "variables": variables})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var r result
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &r))
+	return r
+}
+
+func TestNestedQueryIsBatched(t *testing.T) {
+	e, repo := newRouter(t)
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	shrek2, _ := repo.CreateMovie(t.Context(), "Shrek 2", 2004)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	puss, _ := repo.CreateCharacter(t.Context(), "Puss in Boots")
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek.ID, donkey.ID))
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek2.ID, donkey.ID))
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek2.ID, puss.ID))
+
+	r := query(t, e, `{ movies { title characters { name movies { title } } } }`, nil)
+	require.Empty(t, r.Errors)
+	movies := r.Data["movies"].([]any)
+	require.Len(t, movies, 2)
+	assert.Equal(t, map[string]any{
+		"title": "Shrek 2",
+		"characters": []any{
+			map[string]any{"name": "Donkey", "movies": []any{
+				map[string]any{"title": "Shrek"},
+				map[string]any{"title": "Shrek 2"},
+			}},
+			map[string]any{"name": "Puss in Boots", "movies": []any{
+				map[string]any{"title": "Shrek 2"},
+			}},
+		},
+	}, movies[1])
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metrics.Path, nil))
+	for _, line := range []string{
+		`repository_operation_duration_seconds_count{operation="GetCharactersByMovies"} 1`,
+		`repository_operation_duration_seconds_count{operation="GetMoviesByCharacters"} 1`,
+	} {
+		assert.Contains(t, rec.Body.String(), line)
+	}
+
+	r = query(t, e, `query($id: ID!) { movie(id: $id) { title } }`, map[string]any{"id": shrek.ID.String()})
+	assert.Equal(t, map[string]any{"movie": map[string]any{"title": "Shrek"}}, r.Data)
+	r = query(t, e, `{ character(id: "not-a-uuid") { name } }`, nil)
+	assert.Equal(t, []any{graphql.CodeBadUserInput}, r.codes())
+}
+
+func TestMutations(t *testing.T) {
+	e, repo := newRouter(t)
+	create := `mutation { createMovie(title: "Shrek", releaseYear: 2001) { id version } }`
+
+	r := query(t, e, create, nil)
+	assert.Equal(t, []any{graphql.CodeUnauthenticated}, r.codes())
+	r = query(t, e, create, nil, auth.HeaderAPIKey, adminKey)
+	require.Empty(t, r.Errors)
+	movieID := r.Data["createMovie"].(map[string]any)["id"].(string)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+
+	update := `mutation($id: ID!, $version: Int!) {
+		updateMovie(id: $id, releaseYear: 2002, version: $version) { releaseYear version }
+	}`
+	r = query(t, e, update, map[string]any{"id": movieID, "version": 1}, auth.HeaderAPIKey, adminKey)
+	require.Empty(t, r.Errors)
+	assert.Equal(t, map[string]any{"releaseYear": float64(2002), "version": float64(2)}, r.Data["updateMovie"])
+	r = query(t, e, update, map[string]any{"id": movieID, "version": 1}, auth.HeaderAPIKey, adminKey)
+	assert.Equal(t, []any{graphql.CodePreconditionFailed}, r.codes())
+
+	link := `mutation($movie: ID!, $character: ID!) {
+		linkAppearance(movieId: $movie, characterId: $character) { movie { title } character { name movies { title } } }
+	}`
+	ids := map[string]any{"movie": movieID, "character": donkey.ID.String()}
+	r = query(t, e, link, ids, auth.HeaderAPIKey, adminKey)
+	require.Empty(t, r.Errors)
+	assert.Equal(t, map[string]any{
+		"movie":     map[string]any{"title": "Shrek"},
+		"character": map[string]any{"name": "Donkey", "movies": []any{map[string]any{"title": "Shrek"}}},
+	}, r.Data["linkAppearance"])
+
+	unlink := `mutation($movie: ID!, $character: ID!) { unlinkAppearance(movieId: $movie, characterId: $character) { movie { title } } }`
+	r = query(t, e, unlink, ids, auth.HeaderAPIKey, adminKey)
+	require.Empty(t, r.Errors)
+	r = query(t, e, unlink, ids, auth.HeaderAPIKey, adminKey)
+	assert.Equal(t, []any{graphql.CodeNotFound}, r.codes())
+
+	r = query(t, e, `mutation($id: ID!) { deleteMovie(id: $id, version: 0) }`, map[string]any{"id": movieID}, auth.HeaderAPIKey, adminKey)
+	require.Empty(t, r.Errors)
+	assert.Equal(t, movieID, r.Data["deleteMovie"])
+	r = query(t, e, `query($id: ID!) { movie(id: $id) { title } }`, map[string]any{"id": movieID})
+	assert.Equal(t, map[string]any{"movie": nil}, r.Data)
+}
+
+func TestComplexityLimit(t *testing.T) {
+	e, _ := newRouter(t)
+
+	r := query(t, e, `{ movies { characters { movies { title } } } }`, nil)
+	assert.Empty(t, r.Errors)
+	r = query(t, e, `{ movies { characters { movies { characters { name } } } } }`, nil)
+	assert.Equal(t, []any{graphql.CodeTooComplex}, r.codes())
+	assert.Nil(t, r.Data)
+
+	// Fragments count where they are spread.
+	r = query(t, e, `{ movies { ...deep } } fragment deep on Movie { characters { movies { characters { name } } } }`, nil)
+	assert.Equal(t, []any{graphql.CodeTooComplex}, r.codes())
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "graphiql")
+}
@@ -0,0 +1,100 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/ratelimit"
+	"github.com/google/uuid"
+	gql "github.com/graphql-go/graphql"
+)
+
+// Guard tells the resolvers who sent a request. Mutations check the same
+// roles and client certificates as the REST operations they mirror.
+type Guard interface {
+	// Principal returns the authenticated caller, if any.
+	Principal() (auth.Principal, bool)
+	CanManageMovie(id uuid.UUID) bool
+	CanManageCharacter(id uuid.UUID) bool
+	// Charge takes a token of a rate limit class from the caller.
+	Charge(class string) error
+}
+
+type guardKey struct{}
+
+// WithGuard returns a context whose requests are checked by g. Without one
+// the caller is anonymous and has no client certificate.
+func WithGuard(ctx context.Context, g Guard) context.Context {
+	return context.WithValue(ctx, guardKey{}, g)
+}
+
+func guardFrom(ctx context.Context) Guard {
+	if g, ok := ctx.Value(guardKey{}).(Guard); ok {
+		return g
+	}
+	return anonymous{}
+}
+
+type anonymous struct{}
+
+func (anonymous) Principal() (auth.Principal, bool) { return auth.Principal{}, false }
+func (anonymous) CanManageMovie(uuid.UUID) bool     { return true }
+func (anonymous) CanManageCharacter(uuid.UUID) bool { return true }
+func (anonymous) Charge(string) error               { return nil }
+
+// charge draws from the caller's class limit. /graphql is a read route, so
+// mutations pay for the write and SWAPI work they do here.
+func (s *Server) charge(ctx context.Context, class string) error {
+	if err := guardFrom(ctx).Charge(class); err != nil {
+		return coded(CodeRateLimited, err)
+	}
+	return nil
+}
+
+// mutation charges resolve to the write limit before it runs.
+func (s *Server) mutation(resolve gql.FieldResolveFn) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (any, error) {
+		if err := s.charge(p.Context, ratelimit.Write); err != nil {
+			return nil, err
+		}
+		return resolve(p)
+	}
+}
+
+// require fails unless the caller has at least role. With authentication
+// disabled everyone may do everything, as with REST.
+func (s *Server) require(ctx context.Context, role auth.Role) error {
+	if !s.authEnabled {
+		return nil
+	}
+	p, ok := guardFrom(ctx).Principal()
+	if !ok {
+		return coded(CodeUnauthenticated, errors.New("credentials required: send an X-API-Key header or a bearer token"))
+	}
+	if !p.Role.Includes(role) {
+		return coded(CodeForbidden, fmt.Errorf("the %s role is required, %s has %s", role, p.Subject, p.Role))
+	}
+	return nil
+}
+
+func (s *Server) manageMovie(ctx context.Context, role auth.Role, id uuid.UUID) error {
+	if err := s.require(ctx, role); err != nil {
+		return err
+	}
+	if !guardFrom(ctx).CanManageMovie(id) {
+		return coded(CodeForbidden, errors.New("client certificate may not manage this movie"))
+	}
+	return nil
+}
+
+func (s *Server) manageCharacter(ctx context.Context, role auth.Role, id uuid.UUID) error {
+	if err := s.require(ctx, role); err != nil {
+		return err
+	}
+	if !guardFrom(ctx).CanManageCharacter(id) {
+		return coded(CodeForbidden, errors.New("client certificate may not manage this character"))
+	}
+	return nil
+}
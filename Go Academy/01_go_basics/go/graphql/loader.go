@@ -0,0 +1,75 @@
+package graphql
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"example.com/go_basics/go/entity"
+	"github.com/google/uuid"
+)
+
+// loader batches the lookups of one query level. The executor runs every
+// resolver of a level before it calls the returned thunks, so the first
+// thunk fetches the keys of all its siblings at once.
+type loader[V any] struct {
+	ctx   context.Context
+	fetch func(context.Context, []uuid.UUID) map[uuid.UUID]V
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]struct{}
+	loaded  map[uuid.UUID]V
+}
+
+func newLoader[V any](ctx context.Context, fetch func(context.Context, []uuid.UUID) map[uuid.UUID]V) *loader[V] {
+	return &loader[V]{
+		ctx:     ctx,
+		fetch:   fetch,
+		pending: make(map[uuid.UUID]struct{}),
+		loaded:  make(map[uuid.UUID]V),
+	}
+}
+
+// load queues id and returns the thunk that resolves it.
+func (l *loader[V]) load(id uuid.UUID) func() (any, error) {
+	l.mu.Lock()
+	if _, ok := l.loaded[id]; !ok {
+		l.pending[id] = struct{}{}
+	}
+	l.mu.Unlock()
+	return func() (any, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if len(l.pending) > 0 {
+			ids := slices.Collect(maps.Keys(l.pending))
+			clear(l.pending)
+			maps.Copy(l.loaded, l.fetch(l.ctx, ids))
+		}
+		return l.loaded[id], nil
+	}
+}
+
+// loaders live for one request, so no change is ever served from them to
+// a later query.
+type loaders struct {
+	characters *loader[[]entity.Character]
+	movies     *loader[[]entity.Movie]
+}
+
+type loadersKey struct{}
+
+func (s *Server) newLoaders(ctx context.Context) *loaders {
+	return &loaders{
+		characters: newLoader(ctx, s.repo.GetCharactersByMovies),
+		movies:     newLoader(ctx, s.repo.GetMoviesByCharacters),
+	}
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
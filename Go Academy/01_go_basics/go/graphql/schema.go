@@ -0,0 +1,412 @@
+package graphql
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/entity"
+	"example.com/go_basics/go/ratelimit"
+	"example.com/go_basics/go/repository"
+	"github.com/google/uuid"
+	gql "github.com/graphql-go/graphql"
+)
+
+func (s *Server) newSchema() (gql.Schema, error) {
+	movieType := gql.NewObject(gql.ObjectConfig{
+		Name: "Movie",
+		Fields: gql.Fields{
+			"id":          field(gql.ID, func(m entity.Movie) any { return m.ID.String() }),
+			"title":       field(gql.String, func(m entity.Movie) any { return m.Title }),
+			"releaseYear": field(gql.Int, func(m entity.Movie) any { return m.Year }),
+			"version":     field(gql.Int, func(m entity.Movie) any { return m.Version }),
+		},
+	})
+	characterType := gql.NewObject(gql.ObjectConfig{
+		Name: "Character",
+		Fields: gql.Fields{
+			"id":      field(gql.ID, func(c entity.Character) any { return c.ID.String() }),
+			"name":    field(gql.String, func(c entity.Character) any { return c.Name }),
+			"version": field(gql.Int, func(c entity.Character) any { return c.Version }),
+		},
+	})
+	movieType.AddFieldConfig("characters", &gql.Field{
+		Type:        listOf(characterType),
+		Description: "Characters appearing in the movie",
+		Resolve: func(p gql.ResolveParams) (any, error) {
+			return loadersFrom(p.Context).characters.load(p.Source.(entity.Movie).ID), nil
+		},
+	})
+	characterType.AddFieldConfig("movies", &gql.Field{
+		Type:        listOf(movieType),
+		Description: "Movies the character appears in",
+		Resolve: func(p gql.ResolveParams) (any, error) {
+			return loadersFrom(p.Context).movies.load(p.Source.(entity.Character).ID), nil
+		},
+	})
+	appearanceType := gql.NewObject(gql.ObjectConfig{
+		Name:        "Appearance",
+		Description: "A character appearing in a movie",
+		Fields: gql.Fields{
+			"movie": &gql.Field{
+				Type: gql.NewNonNull(movieType),
+				Resolve: func(p gql.ResolveParams) (any, error) {
+					m, err := s.repo.GetMovie(p.Context, p.Source.(entity.Appearance).MovieID)
+					if err != nil {
+						return nil, present(p.Context, err)
+					}
+					return m, nil
+				},
+			},
+			"character": &gql.Field{
+				Type: gql.NewNonNull(characterType),
+				Resolve: func(p gql.ResolveParams) (any, error) {
+					c, err := s.repo.GetCharacter(p.Context, p.Source.(entity.Appearance).CharacterID)
+					if err != nil {
+						return nil, present(p.Context, err)
+					}
+					return c, nil
+				},
+			},
+		},
+	})
+
+	query := gql.NewObject(gql.ObjectConfig{
+		Name: "Query",
+		Fields: gql.Fields{
+			"movies": &gql.Field{
+				Type:        listOf(movieType),
+				Description: "Every movie, by title",
+				Resolve:     s.movies,
+			},
+			"movie": &gql.Field{
+				Type:    movieType,
+				Args:    gql.FieldConfigArgument{"id": {Type: gql.NewNonNull(gql.ID)}},
+				Resolve: s.movie,
+			},
+			"characters": &gql.Field{
+				Type:        listOf(characterType),
+				Description: "Every character, by name",
+				Resolve:     s.characters,
+			},
+			"character": &gql.Field{
+				Type:    characterType,
+				Args:    gql.FieldConfigArgument{"id": {Type: gql.NewNonNull(gql.ID)}},
+				Resolve: s.character,
+			},
+		},
+	})
+
+	version := &gql.ArgumentConfig{
+		Type:        gql.NewNonNull(gql.Int),
+		Description: "Version the change is based on, 0 to skip the check like If-Match: *",
+	}
+	appearanceArgs := gql.FieldConfigArgument{
+		"movieId":     {Type: gql.NewNonNull(gql.ID)},
+		"characterId": {Type: gql.NewNonNull(gql.ID)},
+	}
+	mutation := gql.NewObject(gql.ObjectConfig{
+		Name: "Mutation",
+		Fields: gql.Fields{
+			"createMovie": &gql.Field{
+				Type: gql.NewNonNull(movieType),
+				Args: gql.FieldConfigArgument{
+					"title":       {Type: gql.NewNonNull(gql.String)},
+					"releaseYear": {Type: gql.NewNonNull(gql.Int)},
+				},
+				Resolve: s.mutation(s.createMovie),
+			},
+			"updateMovie": &gql.Field{
+				Type:        gql.NewNonNull(movieType),
+				Description: "Changes the title, the release year or both",
+				Args: gql.FieldConfigArgument{
+					"id":          {Type: gql.NewNonNull(gql.ID)},
+					"title":       {Type: gql.String},
+					"releaseYear": {Type: gql.Int},
+					"version":     version,
+				},
+				Resolve: s.mutation(s.updateMovie),
+			},
+			"deleteMovie": &gql.Field{
+				Type: gql.NewNonNull(gql.ID),
+				Args: gql.FieldConfigArgument{
+					"id":      {Type: gql.NewNonNull(gql.ID)},
+					"version": version,
+				},
+				Resolve: s.mutation(s.deleteMovie),
+			},
+			"createCharacter": &gql.Field{
+				Type: gql.NewNonNull(characterType),
+				Args: gql.FieldConfigArgument{
+					"name": {Type: gql.NewNonNull(gql.String)},
+					"movie": {
+						Type:        gql.String,
+						Description: "Star Wars checks that SWAPI knows the character",
+					},
+				},
+				Resolve: s.mutation(s.createCharacter),
+			},
+			"updateCharacter": &gql.Field{
+				Type: gql.NewNonNull(characterType),
+				Args: gql.FieldConfigArgument{
+					"id":      {Type: gql.NewNonNull(gql.ID)},
+					"name":    {Type: gql.NewNonNull(gql.String)},
+					"version": version,
+				},
+				Resolve: s.mutation(s.updateCharacter),
+			},
+			"deleteCharacter": &gql.Field{
+				Type: gql.NewNonNull(gql.ID),
+				Args: gql.FieldConfigArgument{
+					"id":      {Type: gql.NewNonNull(gql.ID)},
+					"version": version,
+				},
+				Resolve: s.mutation(s.deleteCharacter),
+			},
+			"linkAppearance": &gql.Field{
+				Type:    gql.NewNonNull(appearanceType),
+				Args:    appearanceArgs,
+				Resolve: s.mutation(s.linkAppearance),
+			},
+			"unlinkAppearance": &gql.Field{
+				Type:    gql.NewNonNull(appearanceType),
+				Args:    appearanceArgs,
+				Resolve: s.mutation(s.unlinkAppearance),
+			},
+		},
+	})
+
+	return gql.NewSchema(gql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// field is a non-null field read from the source entity.
+func field[T any](t gql.Output, get func(T) any) *gql.Field {
+	return &gql.Field{
+		Type: gql.NewNonNull(t),
+		Resolve: func(p gql.ResolveParams) (any, error) {
+			return get(p.Source.(T)), nil
+		},
+	}
+}
+
+func listOf(t gql.Type) gql.Output {
+	return gql.NewNonNull(gql.NewList(gql.NewNonNull(t)))
+}
+
+func idArg(p gql.ResolveParams, name string) (uuid.UUID, error) {
+	s, _ := p.Args[name].(string)
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, coded(CodeBadUserInput, fmt.Errorf("%s: %q is not a UUID", name, s))
+	}
+	return id, nil
+}
+
+func versionArg(p gql.ResolveParams) int64 {
+	v, _ := p.Args["version"].(int)
+	return int64(v)
+}
+
+func (s *Server) movies(p gql.ResolveParams) (any, error) {
+	all, err := s.repo.ListAllMovies(p.Context)
+	if err != nil && !errors.Is(err, repository.ErrNoMovies) {
+		return nil, present(p.Context, err)
+	}
+	// Never nil, the list is non-null.
+	out := slices.AppendSeq(make([]entity.Movie, 0, len(all)), maps.Values(all))
+	slices.SortFunc(out, func(a, b entity.Movie) int {
+		return cmp.Or(cmp.Compare(a.Title, b.Title), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	return out, nil
+}
+
+func (s *Server) characters(p gql.ResolveParams) (any, error) {
+	all, err := s.repo.ListAllCharacters(p.Context)
+	if err != nil && !errors.Is(err, repository.ErrNoCharacters) {
+		return nil, present(p.Context, err)
+	}
+	// Never nil, the list is non-null.
+	out := slices.AppendSeq(make([]entity.Character, 0, len(all)), maps.Values(all))
+	slices.SortFunc(out, func(a, b entity.Character) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	return out, nil
+}
+
+// movie is null for unknown IDs.
+func (s *Server) movie(p gql.ResolveParams) (any, error) {
+	id, err := idArg(p, "id")
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.repo.GetMovie(p.Context, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, present(p.Context, err)
+	}
+	return m, nil
+}
+
+// character is null for unknown IDs.
+func (s *Server) character(p gql.ResolveParams) (any, error) {
+	id, err := idArg(p, "id")
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.repo.GetCharacter(p.Context, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, present(p.Context, err)
+	}
+	return c, nil
+}
+
+func (s *Server) createMovie(p gql.ResolveParams) (any, error) {
+	if err := s.require(p.Context, auth.Editor); err != nil {
+		return nil, err
+	}
+	m, err := s.repo.CreateMovie(p.Context, p.Args["title"].(string), p.Args["releaseYear"].(int))
+	if err != nil {
+		return nil, present(p.Context, err)
+	}
+	return m, nil
+}
+
+func (s *Server) updateMovie(p gql.ResolveParams) (any, error) {
+	id, err := idArg(p, "id")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.manageMovie(p.Context, auth.Editor, id); err != nil {
+		return nil, err
+	}
+	title, _ := p.Args["title"].(string)
+	year, _ := p.Args["releaseYear"].(int)
+	if title == "" && year == 0 {
+		return nil, coded(CodeBadUserInput, errors.New("updateMovie needs a title or a releaseYear"))
+	}
+	m, err := s.repo.UpdateMovie(p.Context, id, title, year, versionArg(p))
+	if err != nil {
+		return nil, present(p.Context, err)
+	}
+	return m, nil
+}
+
+func (s *Server) deleteMovie(p gql.ResolveParams) (any, error) {
+	id, err := idArg(p, "id")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.manageMovie(p.Context, auth.Admin, id); err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeleteMovie(p.Context, id, versionArg(p)); err != nil {
+		return nil, present(p.Context, err)
+	}
+	return id.String(), nil
+}
+
+func (s *Server) createCharacter(p gql.ResolveParams) (any, error) {
+	if err := s.require(p.Context, auth.Editor); err != nil {
+		return nil, err
+	}
+	name := p.Args["name"].(string)
+	if movie, _ := p.Args["movie"].(string); movie == "Star Wars" {
+		if err := s.charge(p.Context, ratelimit.SWAPI); err != nil {
+			return nil, err
+		}
+		if err := s.checkSWAPI(p.Context, name); err != nil {
+			return nil, err
+		}
+	}
+	c, err := s.repo.CreateCharacter(p.Context, name)
+	if err != nil {
+		return nil, present(p.Context, err)
+	}
+	return c, nil
+}
+
+func (s *Server) checkSWAPI(ctx context.Context, name string) error {
+	exists, err := s.swapi.CharacterExists(ctx, name)
+	if err != nil {
+		return coded(CodeBadGateway, fmt.Errorf("SWAPI lookup failed: %w", err))
+	}
+	if !exists {
+		return coded(CodeBadUserInput, errors.New("character not found in Star Wars universe"))
+	}
+	return nil
+}
+
+func (s *Server) updateCharacter(p gql.ResolveParams) (any, error) {
+	id, err := idArg(p, "id")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.manageCharacter(p.Context, auth.Editor, id); err != nil {
+		return nil, err
+	}
+	c, err := s.repo.UpdateCharacter(p.Context, id, p.Args["name"].(string), versionArg(p))
+	if err != nil {
+		return nil, present(p.Context, err)
+	}
+	return c, nil
+}
+
+func (s *Server) deleteCharacter(p gql.ResolveParams) (any, error) {
+	id, err := idArg(p, "id")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.manageCharacter(p.Context, auth.Admin, id); err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeleteCharacter(p.Context, id, versionArg(p)); err != nil {
+		return nil, present(p.Context, err)
+	}
+	return id.String(), nil
+}
+
+func (s *Server) appearanceArgs(p gql.ResolveParams) (entity.Appearance, error) {
+	movieID, err := idArg(p, "movieId")
+	if err != nil {
+		return entity.Appearance{}, err
+	}
+	characterID, err := idArg(p, "characterId")
+	if err != nil {
+		return entity.Appearance{}, err
+	}
+	if err := s.manageMovie(p.Context, auth.Editor, movieID); err != nil {
+		return entity.Appearance{}, err
+	}
+	return entity.New(entity.WithMovieId(movieID), entity.WithCharacterId(characterID)), nil
+}
+
+func (s *Server) linkAppearance(p gql.ResolveParams) (any, error) {
+	a, err := s.appearanceArgs(p)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddAppearance(p.Context, a.MovieID, a.CharacterID); err != nil {
+		return nil, present(p.Context, err)
+	}
+	return a, nil
+}
+
+func (s *Server) unlinkAppearance(p gql.ResolveParams) (any, error) {
+	a, err := s.appearanceArgs(p)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RemoveAppearance(p.Context, a.MovieID, a.CharacterID); err != nil {
+		return nil, present(p.Context, err)
+	}
+	return a, nil
+}
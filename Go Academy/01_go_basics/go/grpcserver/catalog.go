@@ -0,0 +1,369 @@
+package grpcserver
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"example.com/go_basics/go/entity"
+	"example.com/go_basics/go/events"
+	"example.com/go_basics/go/logging"
+	moviesv1 "example.com/go_basics/go/proto/movies/v1"
+	"example.com/go_basics/go/ratelimit"
+	"example.com/go_basics/go/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var eventTypes = map[events.Type]moviesv1.EventType{
+	events.MovieCreated:       moviesv1.EventType_EVENT_TYPE_MOVIE_CREATED,
+	events.MovieUpdated:       moviesv1.EventType_EVENT_TYPE_MOVIE_UPDATED,
+	events.MovieDeleted:       moviesv1.EventType_EVENT_TYPE_MOVIE_DELETED,
+	events.MovieRestored:      moviesv1.EventType_EVENT_TYPE_MOVIE_RESTORED,
+	events.CharacterCreated:   moviesv1.EventType_EVENT_TYPE_CHARACTER_CREATED,
+	events.CharacterUpdated:   moviesv1.EventType_EVENT_TYPE_CHARACTER_UPDATED,
+	events.CharacterDeleted:   moviesv1.EventType_EVENT_TYPE_CHARACTER_DELETED,
+	events.CharacterRestored:  moviesv1.EventType_EVENT_TYPE_CHARACTER_RESTORED,
+	events.AppearanceLinked:   moviesv1.EventType_EVENT_TYPE_APPEARANCE_LINKED,
+	events.AppearanceUnlinked: moviesv1.EventType_EVENT_TYPE_APPEARANCE_UNLINKED,
+	events.FranchiseCreated:   moviesv1.EventType_EVENT_TYPE_FRANCHISE_CREATED,
+	events.FranchiseUpdated:   moviesv1.EventType_EVENT_TYPE_FRANCHISE_UPDATED,
+	events.FranchiseDeleted:   moviesv1.EventType_EVENT_TYPE_FRANCHISE_DELETED,
+	events.Reset:              moviesv1.EventType_EVENT_TYPE_RESET,
+}
+
+func (s *Server) CreateMovie(ctx context.Context, req *moviesv1.CreateMovieRequest) (*moviesv1.Movie, error) {
+	m, err := s.repo.CreateMovie(ctx, req.GetTitle(), int(req.GetReleaseYear()))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toMovie(m), nil
+}
+
+func (s *Server) GetMovie(ctx context.Context, req *moviesv1.GetMovieRequest) (*moviesv1.Movie, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.repo.GetMovie(ctx, id)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toMovie(m), nil
+}
+
+func (s *Server) UpdateMovie(ctx context.Context, req *moviesv1.UpdateMovieRequest) (*moviesv1.Movie, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if req.Title == nil && req.ReleaseYear == nil {
+		return nil, status.Error(codes.InvalidArgument, "UpdateMovie needs a title or a release_year")
+	}
+	version, err := requireVersion(req.Version)
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.repo.UpdateMovie(ctx, id, req.GetTitle(), int(req.GetReleaseYear()), version)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toMovie(m), nil
+}
+
+func (s *Server) DeleteMovie(ctx context.Context, req *moviesv1.DeleteMovieRequest) (*moviesv1.DeleteMovieResponse, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	version, err := requireVersion(req.Version)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeleteMovie(ctx, id, version); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &moviesv1.DeleteMovieResponse{}, nil
+}
+
+func (s *Server) ListMovies(_ *moviesv1.ListMoviesRequest, stream grpc.ServerStreamingServer[moviesv1.Movie]) error {
+	ctx := stream.Context()
+	movies, err := s.repo.ListAllMovies(ctx)
+	if err != nil && !errors.Is(err, repository.ErrNoMovies) {
+		return toStatus(ctx, err)
+	}
+	return sendMovies(stream, slices.Collect(maps.Values(movies)))
+}
+
+func (s *Server) CreateCharacter(ctx context.Context, req *moviesv1.CreateCharacterRequest) (*moviesv1.Character, error) {
+	if req.GetMovie() == "Star Wars" {
+		if err := s.limit(ctx, ratelimit.SWAPI); err != nil {
+			return nil, err
+		}
+		exists, err := s.swapi.CharacterExists(ctx, req.GetName())
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "SWAPI lookup failed: %v", err)
+		}
+		if !exists {
+			return nil, status.Error(codes.InvalidArgument, "character not found in Star Wars universe")
+		}
+	}
+	c, err := s.repo.CreateCharacter(ctx, req.GetName())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toCharacter(c), nil
+}
+
+func (s *Server) GetCharacter(ctx context.Context, req *moviesv1.GetCharacterRequest) (*moviesv1.Character, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.repo.GetCharacter(ctx, id)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toCharacter(c), nil
+}
+
+func (s *Server) UpdateCharacter(ctx context.Context, req *moviesv1.UpdateCharacterRequest) (*moviesv1.Character, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	version, err := requireVersion(req.Version)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.repo.UpdateCharacter(ctx, id, req.GetName(), version)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toCharacter(c), nil
+}
+
+func (s *Server) DeleteCharacter(ctx context.Context, req *moviesv1.DeleteCharacterRequest) (*moviesv1.DeleteCharacterResponse, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	version, err := requireVersion(req.Version)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeleteCharacter(ctx, id, version); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &moviesv1.DeleteCharacterResponse{}, nil
+}
+
+func (s *Server) ListCharacters(_ *moviesv1.ListCharactersRequest, stream grpc.ServerStreamingServer[moviesv1.Character]) error {
+	ctx := stream.Context()
+	characters, err := s.repo.ListAllCharacters(ctx)
+	if err != nil && !errors.Is(err, repository.ErrNoCharacters) {
+		return toStatus(ctx, err)
+	}
+	return sendCharacters(stream, slices.Collect(maps.Values(characters)))
+}
+
+func (s *Server) LinkAppearance(ctx context.Context, req *moviesv1.LinkAppearanceRequest) (*moviesv1.Appearance, error) {
+	movieID, characterID, err := parseAppearance(req.GetMovieId(), req.GetCharacterId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddAppearance(ctx, movieID, characterID); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &moviesv1.Appearance{MovieId: movieID.String(), CharacterId: characterID.String()}, nil
+}
+
+func (s *Server) UnlinkAppearance(ctx context.Context, req *moviesv1.UnlinkAppearanceRequest) (*moviesv1.Appearance, error) {
+	movieID, characterID, err := parseAppearance(req.GetMovieId(), req.GetCharacterId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RemoveAppearance(ctx, movieID, characterID); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &moviesv1.Appearance{MovieId: movieID.String(), CharacterId: characterID.String()}, nil
+}
+
+func (s *Server) ListMovieCharacters(req *moviesv1.ListMovieCharactersRequest, stream grpc.ServerStreamingServer[moviesv1.Character]) error {
+	id, err := parseID("movie_id", req.GetMovieId())
+	if err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	characters, err := s.repo.GetCharactersByMovie(ctx, id)
+	if err != nil {
+		return toStatus(ctx, err)
+	}
+	return sendCharacters(stream, characters)
+}
+
+func (s *Server) ListCharacterMovies(req *moviesv1.ListCharacterMoviesRequest, stream grpc.ServerStreamingServer[moviesv1.Movie]) error {
+	id, err := parseID("character_id", req.GetCharacterId())
+	if err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	movies, err := s.repo.GetMoviesByCharacter(ctx, id)
+	if err != nil {
+		return toStatus(ctx, err)
+	}
+	return sendMovies(stream, movies)
+}
+
+// Watch streams the changes after after_id until the client cancels or the
+// server stops. gRPC keepalives take the place of the SSE heartbeat.
+func (s *Server) Watch(req *moviesv1.WatchRequest, stream grpc.ServerStreamingServer[moviesv1.Event]) error {
+	if s.bus == nil || s.bus.Closed() {
+		return status.Error(codes.Unavailable, "the change feed is closed")
+	}
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	send := func(e events.Event) error {
+		return stream.Send(toEvent(e))
+	}
+	return s.bus.Stream(ctx, req.GetAfterId(), send, func() error { return nil })
+}
+
+func sendMovies(stream grpc.ServerStreamingServer[moviesv1.Movie], movies []entity.Movie) error {
+	slices.SortFunc(movies, func(a, b entity.Movie) int {
+		return cmp.Or(cmp.Compare(a.Title, b.Title), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	for _, m := range movies {
+		if err := stream.Send(toMovie(m)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sendCharacters(stream grpc.ServerStreamingServer[moviesv1.Character], characters []entity.Character) error {
+	slices.SortFunc(characters, func(a, b entity.Character) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	for _, c := range characters {
+		if err := stream.Send(toCharacter(c)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseID(field, s string) (uuid.UUID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "%s is not a UUID: %q", field, s)
+	}
+	return id, nil
+}
+
+// requireVersion makes writes conditional, as If-Match does over REST. Only
+// an explicit 0 skips the check.
+func requireVersion(version *int64) (int64, error) {
+	if version == nil {
+		return 0, status.Error(codes.InvalidArgument, "version is required, 0 skips the check")
+	}
+	return *version, nil
+}
+
+func parseAppearance(movieID, characterID string) (uuid.UUID, uuid.UUID, error) {
+	m, err := parseID("movie_id", movieID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	c, err := parseID("character_id", characterID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return m, c, nil
+}
+
+// toStatus maps repository errors to the gRPC codes matching the HTTP
+// status REST answers with. Unexpected errors are logged and hidden.
+func toStatus(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, repository.ErrInvalidInput):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, repository.ErrVersionMismatch):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	logging.FromContext(ctx).Error("gRPC call failed", zap.Error(err))
+	return status.Error(codes.Internal, "An unexpected error occurred")
+}
+
+func toMovie(m entity.Movie) *moviesv1.Movie {
+	return &moviesv1.Movie{
+		Id:          m.ID.String(),
+		Title:       m.Title,
+		ReleaseYear: int32(m.Year),
+		Version:     m.Version,
+	}
+}
+
+func toCharacter(c entity.Character) *moviesv1.Character {
+	return &moviesv1.Character{
+		Id:      c.ID.String(),
+		Name:    c.Name,
+		Version: c.Version,
+	}
+}
+
+func toFranchise(f entity.Franchise) *moviesv1.Franchise {
+	out := &moviesv1.Franchise{
+		Id:      f.ID.String(),
+		Name:    f.Name,
+		Version: f.Version,
+	}
+	for _, in := range f.Installments {
+		out.Installments = append(out.Installments, &moviesv1.Installment{MovieId: in.MovieID.String(), Kind: in.Kind})
+	}
+	for _, id := range f.Characters {
+		out.CharacterIds = append(out.CharacterIds, id.String())
+	}
+	return out
+}
+
+func toEvent(e events.Event) *moviesv1.Event {
+	out := &moviesv1.Event{
+		Id:   e.ID,
+		Type: eventTypes[e.Type],
+		Time: timestamppb.New(e.Time),
+	}
+	switch data := e.Data.(type) {
+	case entity.Movie:
+		out.Data = &moviesv1.Event_Movie{Movie: toMovie(data)}
+	case entity.Character:
+		out.Data = &moviesv1.Event_Character{Character: toCharacter(data)}
+	case entity.Appearance:
+		out.Data = &moviesv1.Event_Appearance{Appearance: &moviesv1.Appearance{
+			MovieId:     data.MovieID.String(),
+			CharacterId: data.CharacterID.String(),
+		}}
+	case entity.Franchise:
+		out.Data = &moviesv1.Event_Franchise{Franchise: toFranchise(data)}
+	case nil:
+	default:
+		panic(fmt.Sprintf("grpcserver: unexpected event data %T", e.Data))
+	}
+	return out
+}
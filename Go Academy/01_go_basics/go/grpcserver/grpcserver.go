@@ -0,0 +1,224 @@
+// Package grpcserver serves the movie catalog over gRPC on its own port,
+// next to the REST API and sharing its repository, change feed, credentials
+// and rate limits.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/events"
+	"example.com/go_basics/go/logging"
+	moviesv1 "example.com/go_basics/go/proto/movies/v1"
+	"example.com/go_basics/go/ratelimit"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/swapi"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// roles lists the calls that need credentials, with the role of the REST
+// operation they mirror. Reads are public, as they are over REST.
+var roles = map[string]auth.Role{
+	"CreateMovie":      auth.Editor,
+	"UpdateMovie":      auth.Editor,
+	"DeleteMovie":      auth.Admin,
+	"CreateCharacter":  auth.Editor,
+	"UpdateCharacter":  auth.Editor,
+	"DeleteCharacter":  auth.Admin,
+	"LinkAppearance":   auth.Editor,
+	"UnlinkAppearance": auth.Editor,
+}
+
+// Server implements moviesv1.CatalogServiceServer.
+type Server struct {
+	moviesv1.UnimplementedCatalogServiceServer
+
+	grpc    *grpc.Server
+	health  *health.Server
+	repo    *repository.Repository
+	bus     *events.Bus
+	auth    *auth.Auth
+	limiter *ratelimit.Limiter
+	swapi   *swapi.Client
+	logger  *zap.Logger
+	// done ends the Watch streams, which GracefulStop would wait for.
+	done chan struct{}
+}
+
+// Start serves gRPC on cfg.Server.GRPCAddr, unless it is empty.
+func Start(lc fx.Lifecycle, cfg *config.Config, repo *repository.Repository, bus *events.Bus, a *auth.Auth, limiter *ratelimit.Limiter, sw *swapi.Client, logger *zap.Logger) {
+	addr := cfg.Server.GRPCAddr
+	if addr == "" {
+		return
+	}
+	s := NewServer(repo, bus, a, limiter, sw, logger)
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			lis, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+			s.logger.Info("starting gRPC server", zap.String("addr", addr))
+			go func() {
+				if err := s.Serve(lis); err != nil {
+					s.logger.Error("gRPC server stopped", zap.Error(err))
+				}
+			}()
+			return nil
+		},
+		OnStop: s.Stop,
+	})
+}
+
+// NewServer registers the catalog, health and reflection services. A nil
+// auth leaves every call open, a nil limiter unlimited and a nil bus makes
+// Watch unavailable.
+func NewServer(repo *repository.Repository, bus *events.Bus, a *auth.Auth, limiter *ratelimit.Limiter, sw *swapi.Client, logger *zap.Logger) *Server {
+	s := &Server{
+		health:  health.NewServer(),
+		repo:    repo,
+		bus:     bus,
+		auth:    a,
+		limiter: limiter,
+		swapi:   sw,
+		logger:  logger.Named("grpc"),
+		done:    make(chan struct{}),
+	}
+	s.grpc = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.streamInterceptor),
+	)
+	moviesv1.RegisterCatalogServiceServer(s.grpc, s)
+	healthpb.RegisterHealthServer(s.grpc, s.health)
+	s.health.SetServingStatus(moviesv1.CatalogService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	reflection.Register(s.grpc)
+	return s
+}
+
+// Serve accepts connections on lis until Stop.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// Stop reports NOT_SERVING, ends the Watch streams and waits for the other
+// calls to finish. Calls still running when ctx is done are cancelled.
+func (s *Server) Stop(ctx context.Context) error {
+	s.health.Shutdown()
+	close(s.done)
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpc.Stop()
+		return ctx.Err()
+	}
+}
+
+func (s *Server) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.limitCall(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	if err := s.limitCall(ctx, info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// authorize checks the credentials of calls that need a role and puts a
+// logger for the call into the context.
+func (s *Server) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	logger := s.logger.With(zap.String("method", method))
+	if role, ok := roles[method]; ok && s.auth != nil {
+		md, _ := metadata.FromIncomingContext(ctx)
+		header := make(http.Header)
+		for _, key := range []string{auth.HeaderAPIKey, "Authorization"} {
+			for _, v := range md.Get(key) {
+				header.Add(key, v)
+			}
+		}
+		p, err := s.auth.Check(header, role)
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case err != nil:
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		logger = logger.With(zap.String("subject", p.Subject))
+		ctx = auth.WithPrincipal(ctx, p)
+	}
+	return logging.WithContext(ctx, logger), nil
+}
+
+// limitCall takes a token for a catalog call, from the write bucket for the
+// calls that need a role and else from the read one, as REST does.
+func (s *Server) limitCall(ctx context.Context, fullMethod string) error {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if service != moviesv1.CatalogService_ServiceDesc.ServiceName {
+		return nil
+	}
+	class := ratelimit.Read
+	if _, ok := roles[method]; ok {
+		class = ratelimit.Write
+	}
+	return s.limit(ctx, class)
+}
+
+// limit takes a token of class from the buckets the caller has over REST:
+// the principal's, else those of its IP.
+func (s *Server) limit(ctx context.Context, class string) error {
+	var host string
+	if p, ok := peer.FromContext(ctx); ok {
+		host = p.Addr.String()
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+	}
+	if ok, retry := s.limiter.Take(ctx, host, class); !ok {
+		logging.FromContext(ctx).Info("rate limited", zap.String("class", class))
+		return status.Errorf(codes.ResourceExhausted, "Rate limit of %s requests exceeded, retry in %ds", class, int(math.Ceil(retry.Seconds())))
+	}
+	return nil
+}
+
+// serverStream replaces the context of a stream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
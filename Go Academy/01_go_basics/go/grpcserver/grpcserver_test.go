@@ -0,0 +1,233 @@
+package grpcserver_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/events"
+	"example.com/go_basics/go/grpcserver"
+	moviesv1 "example.com/go_basics/go/proto/movies/v1"
+	"example.com/go_basics/go/ratelimit"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/swapi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+const adminKey = "mca_test-admin-key"
+
+func newClient(t *testing.T) (*grpc.ClientConn, *repository.Repository) {
+	cfg := config.Default()
+	cfg.Auth.AdminKey = adminKey
+	bus := events.New(cfg)
+	repo := repository.New(db.New(), nil, bus, nil)
+	s := grpcserver.NewServer(repo, bus, auth.New(cfg, auth.NewKeyStore(cfg)), nil, nil, zap.NewNop())
+	t.Cleanup(bus.Close)
+	return dial(t, s), repo
+}
+
+// dial serves s on an in-memory listener and connects to it.
+func dial(t *testing.T, s *grpcserver.Server) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	go s.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+		require.NoError(t, s.Stop(context.Background()))
+	})
+	return conn
+}
+
+func admin(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "x-api-key", adminKey)
+}
+
+func collect[T any](t *testing.T, stream grpc.ServerStreamingClient[T]) []*T {
+	var items []*T
+	for {
+		item, err := stream.Recv()
+		if err == io.EOF {
+			return items
+		}
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	conn, _ := newClient(t)
+	client := moviesv1.NewCatalogServiceClient(conn)
+	ctx := t.Context()
+
+	_, err := client.CreateMovie(ctx, &moviesv1.CreateMovieRequest{Title: "Shrek", ReleaseYear: 2001})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	shrek, err := client.CreateMovie(admin(ctx), &moviesv1.CreateMovieRequest{Title: "Shrek", ReleaseYear: 2001})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), shrek.Version)
+	_, err = client.CreateMovie(admin(ctx), &moviesv1.CreateMovieRequest{Title: "Babe", ReleaseYear: 1995})
+	require.NoError(t, err)
+	donkey, err := client.CreateCharacter(admin(ctx), &moviesv1.CreateCharacterRequest{Name: "Donkey"})
+	require.NoError(t, err)
+
+	title := "Shrek the First"
+	updated, err := client.UpdateMovie(admin(ctx), &moviesv1.UpdateMovieRequest{Id: shrek.Id, Title: &title, Version: proto.Int64(1)})
+	require.NoError(t, err)
+	assert.Equal(t, "Shrek the First", updated.Title)
+	assert.Equal(t, int32(2001), updated.ReleaseYear)
+	_, err = client.UpdateMovie(admin(ctx), &moviesv1.UpdateMovieRequest{Id: shrek.Id, Title: &title, Version: proto.Int64(1)})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = client.LinkAppearance(admin(ctx), &moviesv1.LinkAppearanceRequest{MovieId: shrek.Id, CharacterId: donkey.Id})
+	require.NoError(t, err)
+
+	stream, err := client.ListMovies(ctx, &moviesv1.ListMoviesRequest{})
+	require.NoError(t, err)
+	movies := collect(t, stream)
+	require.Len(t, movies, 2)
+	assert.Equal(t, "Babe", movies[0].Title)
+	assert.Equal(t, "Shrek the First", movies[1].Title)
+
+	chars, err := client.ListMovieCharacters(ctx, &moviesv1.ListMovieCharactersRequest{MovieId: shrek.Id})
+	require.NoError(t, err)
+	characters := collect(t, chars)
+	require.Len(t, characters, 1)
+	assert.Equal(t, "Donkey", characters[0].Name)
+
+	_, err = client.GetMovie(ctx, &moviesv1.GetMovieRequest{Id: "not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.DeleteMovie(admin(ctx), &moviesv1.DeleteMovieRequest{Id: shrek.Id})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.UpdateCharacter(admin(ctx), &moviesv1.UpdateCharacterRequest{Id: donkey.Id, Name: "Donkey the Brave"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.DeleteMovie(admin(ctx), &moviesv1.DeleteMovieRequest{Id: shrek.Id, Version: proto.Int64(0)})
+	require.NoError(t, err)
+	_, err = client.GetMovie(ctx, &moviesv1.GetMovieRequest{Id: shrek.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	appearsIn, err := client.ListCharacterMovies(ctx, &moviesv1.ListCharacterMoviesRequest{CharacterId: donkey.Id})
+	require.NoError(t, err)
+	assert.Empty(t, collect(t, appearsIn))
+}
+
+func TestWatch(t *testing.T) {
+	conn, repo := newClient(t)
+	client := moviesv1.NewCatalogServiceClient(conn)
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	shrek, _ := repo.CreateMovie(ctx, "Shrek", 2001)
+	first := repo.Events.LastID()
+	donkey, _ := repo.CreateCharacter(ctx, "Donkey")
+	require.NoError(t, repo.AddAppearance(ctx, shrek.ID, donkey.ID))
+
+	stream, err := client.Watch(ctx, &moviesv1.WatchRequest{AfterId: first})
+	require.NoError(t, err)
+	e, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, first+1, e.Id)
+	assert.Equal(t, moviesv1.EventType_EVENT_TYPE_CHARACTER_CREATED, e.Type)
+	assert.Equal(t, "Donkey", e.GetCharacter().GetName())
+	e, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, moviesv1.EventType_EVENT_TYPE_APPEARANCE_LINKED, e.Type)
+	assert.Equal(t, shrek.ID.String(), e.GetAppearance().GetMovieId())
+
+	_, err = repo.UpdateMovie(ctx, shrek.ID, "Shrek 2", 0, repository.AnyVersion)
+	require.NoError(t, err)
+	e, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, first+3, e.Id)
+	assert.Equal(t, moviesv1.EventType_EVENT_TYPE_MOVIE_UPDATED, e.Type)
+	assert.Equal(t, "Shrek 2", e.GetMovie().GetTitle())
+}
+
+func TestHealthAndReflection(t *testing.T) {
+	conn, _ := newClient(t)
+	ctx := t.Context()
+
+	for _, service := range []string{"", moviesv1.CatalogService_ServiceDesc.ServiceName} {
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+	}
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}))
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	var services []string
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		services = append(services, s.Name)
+	}
+	assert.Contains(t, services, moviesv1.CatalogService_ServiceDesc.ServiceName)
+	assert.Contains(t, services, healthpb.Health_ServiceDesc.ServiceName)
+}
+
+func TestRateLimit(t *testing.T) {
+	fakeSWAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"count":1}`)
+	}))
+	t.Cleanup(fakeSWAPI.Close)
+	cfg := config.Default()
+	cfg.Auth.AdminKey = adminKey
+	cfg.SWAPI.BaseURL = fakeSWAPI.URL
+	// Refills so slowly that no token comes back during the test.
+	cfg.RateLimit.Read = config.Limit{Rate: 0.001, Burst: 2}
+	cfg.RateLimit.Write = config.Limit{Rate: 0.001, Burst: 3}
+	cfg.RateLimit.SWAPI = config.Limit{Rate: 0.001, Burst: 1}
+	cfg.RateLimit.Tiers["admin"] = 1
+	repo := repository.New(db.New(), nil, nil, nil)
+	s := grpcserver.NewServer(repo, nil, auth.New(cfg, auth.NewKeyStore(cfg)), ratelimit.New(cfg, nil), swapi.New(cfg, nil), zap.NewNop())
+	client := moviesv1.NewCatalogServiceClient(dial(t, s))
+	ctx := admin(t.Context())
+
+	// Star Wars characters take a SWAPI token on top of the write one.
+	_, err := client.CreateCharacter(ctx, &moviesv1.CreateCharacterRequest{Name: "Yoda", Movie: "Star Wars"})
+	require.NoError(t, err)
+	_, err = client.CreateCharacter(ctx, &moviesv1.CreateCharacterRequest{Name: "Luke", Movie: "Star Wars"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "swapi")
+	_, err = client.CreateCharacter(ctx, &moviesv1.CreateCharacterRequest{Name: "Donkey"})
+	require.NoError(t, err)
+	_, err = client.CreateMovie(ctx, &moviesv1.CreateMovieRequest{Title: "Shrek", ReleaseYear: 2001})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "write")
+
+	// Reads are limited per IP without credentials.
+	for range 2 {
+		_, err = client.GetMovie(t.Context(), &moviesv1.GetMovieRequest{Id: uuid.NewString()})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	}
+	_, err = client.GetMovie(t.Context(), &moviesv1.GetMovieRequest{Id: uuid.NewString()})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "read")
+}
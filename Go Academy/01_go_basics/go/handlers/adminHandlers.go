@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/problem"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handlers) GetAdminApiKeys(c echo.Context) error {
+	keys := h.Keys.List()
+	out := make([]api.ApiKey, len(keys))
+	for i, k := range keys {
+		out[i] = toAPIKey(k)
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+func (h *Handlers) PostAdminApiKeys(c echo.Context) error {
+	var input api.NewApiKey
+	if err := c.Bind(&input); err != nil {
+		return problem.New(http.StatusBadRequest, "Invalid request body")
+	}
+	key, secret, err := h.Keys.Create(input.Name, auth.Role(input.Role))
+	if err != nil {
+		return problem.New(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusCreated, api.CreatedApiKey{Key: toAPIKey(key), Secret: secret})
+}
+
+func (h *Handlers) DeleteAdminApiKeysId(c echo.Context, id uuid.UUID) error {
+	if err := h.Keys.Revoke(id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func toAPIKey(k auth.APIKey) api.ApiKey {
+	return api.ApiKey{
+		Id:        k.ID,
+		Name:      k.Name,
+		Role:      api.Role(k.Role),
+		Prefix:    k.Prefix,
+		CreatedAt: k.CreatedAt,
+	}
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/audit"
+	"example.com/go_basics/go/repository"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultAuditLimit applies when a query sets no limit.
+const defaultAuditLimit = 100
+
+func auditLimit(limit *api.AuditLimit) int {
+	if limit == nil {
+		return defaultAuditLimit
+	}
+	return *limit
+}
+
+func (h *Handlers) GetAudit(c echo.Context, params api.GetAuditParams) error {
+	f := audit.Filter{Limit: auditLimit(params.Limit)}
+	if params.Actor != nil {
+		f.Actor = *params.Actor
+	}
+	if params.Operation != nil {
+		f.Operation = *params.Operation
+	}
+	if params.EntityType != nil {
+		f.EntityType = string(*params.EntityType)
+	}
+	if params.EntityId != nil {
+		f.EntityID = *params.EntityId
+	}
+	if params.Since != nil {
+		f.Since = *params.Since
+	}
+	if params.Until != nil {
+		f.Until = *params.Until
+	}
+	if params.Before != nil {
+		f.Before = uint64(*params.Before)
+	}
+	return c.JSON(http.StatusOK, map[string]any{"entries": h.Repo.Audit.Find(f)})
+}
+
+func (h *Handlers) GetMoviesIdHistory(c echo.Context, id uuid.UUID, params api.GetMoviesIdHistoryParams) error {
+	entries := h.Repo.Audit.Find(audit.Filter{EntityID: id, Limit: auditLimit(params.Limit)})
+	if len(entries) == 0 {
+		return fmt.Errorf("%w [ID: %s]", repository.ErrMovieNotFound, id)
+	}
+	return c.JSON(http.StatusOK, map[string]any{"entries": entries})
+}
+
+func (h *Handlers) GetCharactersIdHistory(c echo.Context, id uuid.UUID, params api.GetCharactersIdHistoryParams) error {
+	entries := h.Repo.Audit.Find(audit.Filter{EntityID: id, Limit: auditLimit(params.Limit)})
+	if len(entries) == 0 {
+		return fmt.Errorf("%w [ID: %s]", repository.ErrCharacterNotFound, id)
+	}
+	return c.JSON(http.StatusOK, map[string]any{"entries": entries})
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/x509"
+
+	"example.com/go_basics/go/entity"
+	"example.com/go_basics/go/mtls"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// canManageMovie lets a movie certificate manage only its own movie, and only
+// when it is the certificate kept for that movie. Requests without a client
+// certificate are not restricted. A movie that cannot be looked up is not
+// managed by anyone holding a certificate.
+func (h *Handlers) canManageMovie(c echo.Context, movieID uuid.UUID) bool {
+	ctx := c.Request().Context()
+	id, ok := mtls.FromContext(c)
+	if !ok {
+		return true
+	}
+	movie, err := h.Repo.GetMovie(ctx, movieID)
+	if err != nil {
+		return false
+	}
+	return id.IsMovie() && movie.Title == id.Name && h.isMovieCert(id.Cert, movie.Title)
+}
+
+// canManageCharacter lets a movie certificate manage the characters appearing
+// in that movie and a character certificate manage only itself. A character
+// certificate counts only when it was issued by the stored certificate of a
+// movie the character appears in.
+func (h *Handlers) canManageCharacter(c echo.Context, characterID uuid.UUID) bool {
+	ctx := c.Request().Context()
+	id, ok := mtls.FromContext(c)
+	if !ok {
+		return true
+	}
+	character, err := h.Repo.GetCharacter(ctx, characterID)
+	if err != nil {
+		return false
+	}
+	movies, err := h.Repo.GetMoviesByCharacter(ctx, characterID)
+	if err != nil {
+		return false
+	}
+	if id.IsCharacter() {
+		return character.Name == id.Name && h.issuedBy(id, movies)
+	}
+	for _, m := range movies {
+		if id.IsMovie() && m.Title == id.Name {
+			return h.isMovieCert(id.Cert, m.Title)
+		}
+	}
+	return false
+}
+
+// isMovieCert reports whether cert is the certificate kept for the movie
+// title, rather than another intermediate the CA signed for the same title.
+func (h *Handlers) isMovieCert(cert *x509.Certificate, title string) bool {
+	if cert == nil {
+		return false
+	}
+	kept, err := h.CA.MovieCert(title)
+	return err == nil && bytes.Equal(kept.Raw, cert.Raw)
+}
+
+// issuedBy reports whether the character certificate of id chains to the
+// certificate kept for one of movies.
+func (h *Handlers) issuedBy(id mtls.Identity, movies []entity.Movie) bool {
+	if id.Issuer == nil {
+		return false
+	}
+	for _, m := range movies {
+		if m.Title != id.Movie {
+			continue
+		}
+		return h.isMovieCert(id.Issuer, m.Title)
+	}
+	return false
+}
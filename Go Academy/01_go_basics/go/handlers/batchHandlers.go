@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/entity"
+	"example.com/go_basics/go/problem"
+	"example.com/go_basics/go/ratelimit"
+	"example.com/go_basics/go/repository"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// batchResult is the outcome of one batch operation.
+type batchResult struct {
+	Op         api.BatchOperationOp `json:"op"`
+	Status     int                  `json:"status"`
+	Ref        string               `json:"ref,omitempty"`
+	Movie      *entity.Movie        `json:"movie,omitempty"`
+	Character  *entity.Character    `json:"character,omitempty"`
+	Appearance *api.Appearance      `json:"appearance,omitempty"`
+}
+
+// target names a movie or character by ID or by the ref of an earlier
+// create in the batch.
+type target struct {
+	id  uuid.UUID
+	ref string
+}
+
+// batchOp is a checked operation.
+type batchOp struct {
+	api.BatchOperation
+	id, movie, character target
+	version              int64
+}
+
+// batchError names the operation a batch failed on.
+type batchError struct {
+	index int
+	err   error
+}
+
+func (e batchError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.index, e.err)
+}
+
+func (e batchError) Unwrap() error {
+	return e.err
+}
+
+func (h *Handlers) PostBatch(c echo.Context) error {
+	var input api.BatchRequest
+	if err := c.Bind(&input); err != nil {
+		return problem.New(http.StatusBadRequest, "Invalid request body")
+	}
+	ops, err := h.checkBatch(c, input.Operations)
+	if err != nil {
+		return err
+	}
+
+	results := make([]batchResult, len(ops))
+	err = h.Repo.Batch(c.Request().Context(), func(tx *repository.Tx) error {
+		if err := h.authorizeBatch(c, ops); err != nil {
+			return err
+		}
+		refs := make(map[string]uuid.UUID)
+		for i, op := range ops {
+			result, err := runBatchOp(tx, op, refs)
+			if err != nil {
+				return batchError{index: i, err: err}
+			}
+			results[i] = result
+		}
+		return nil
+	})
+	var be batchError
+	if errors.As(err, &be) {
+		p := toProblem(be.err)
+		p.Detail = fmt.Sprintf("Operation %d failed, nothing was changed: %s", be.index, p.Detail)
+		p.Errors = []problem.FieldError{{Field: fmt.Sprintf("operations.%d", be.index), Message: be.err.Error()}}
+		return p
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]any{"results": results})
+}
+
+// checkBatch does what does not depend on the store: required fields, refs,
+// roles and SWAPI lookups. SWAPI is asked before the batch holds the store,
+// so other changes do not wait for it.
+func (h *Handlers) checkBatch(c echo.Context, input []api.BatchOperation) ([]batchOp, error) {
+	var fields []problem.FieldError
+	invalid := func(i int, field, message string) {
+		fields = append(fields, problem.FieldError{Field: fmt.Sprintf("operations.%d.%s", i, field), Message: message})
+	}
+	// refs holds the kind of movie or character each ref names.
+	refs := make(map[string]string)
+	resolve := func(i int, field string, value *string, kind string) target {
+		switch {
+		case value == nil:
+			invalid(i, field, "is required")
+		case strings.HasPrefix(*value, "$"):
+			ref := strings.TrimPrefix(*value, "$")
+			switch refs[ref] {
+			case kind:
+				return target{ref: ref}
+			case "":
+				invalid(i, field, fmt.Sprintf("no earlier operation has the ref %q", ref))
+			default:
+				invalid(i, field, fmt.Sprintf("ref %q names a %s", ref, refs[ref]))
+			}
+		default:
+			id, err := uuid.Parse(*value)
+			if err != nil {
+				invalid(i, field, "must be a UUID or a $ref")
+			}
+			return target{id: id}
+		}
+		return target{}
+	}
+	need := func(i int, field string, set bool) {
+		if !set {
+			invalid(i, field, "is required")
+		}
+	}
+
+	ops := make([]batchOp, len(input))
+	for i, in := range input {
+		op := batchOp{BatchOperation: in}
+		if in.Version != nil {
+			op.version = *in.Version
+		}
+		kind := "movie"
+		switch in.Op {
+		case api.CreateMovie:
+			need(i, "title", in.Title != nil)
+			need(i, "release_year", in.ReleaseYear != nil)
+		case api.UpdateMovie:
+			op.id = resolve(i, "id", in.Id, kind)
+			need(i, "title", in.Title != nil || in.ReleaseYear != nil)
+			need(i, "version", in.Version != nil)
+		case api.DeleteMovie:
+			op.id = resolve(i, "id", in.Id, kind)
+			need(i, "version", in.Version != nil)
+		case api.CreateCharacter:
+			kind = "character"
+			need(i, "name", in.Name != nil)
+		case api.UpdateCharacter:
+			kind = "character"
+			op.id = resolve(i, "id", in.Id, kind)
+			need(i, "name", in.Name != nil)
+			need(i, "version", in.Version != nil)
+		case api.DeleteCharacter:
+			kind = "character"
+			op.id = resolve(i, "id", in.Id, kind)
+			need(i, "version", in.Version != nil)
+		case api.LinkAppearance, api.UnlinkAppearance:
+			op.movie = resolve(i, "movie_id", in.MovieId, "movie")
+			op.character = resolve(i, "character_id", in.CharacterId, "character")
+		}
+		if in.Ref != nil {
+			switch {
+			case in.Op != api.CreateMovie && in.Op != api.CreateCharacter:
+				invalid(i, "ref", "only creates take a ref")
+			case refs[*in.Ref] != "":
+				invalid(i, "ref", fmt.Sprintf("ref %q is already used", *in.Ref))
+			default:
+				refs[*in.Ref] = kind
+			}
+		}
+		ops[i] = op
+	}
+	if len(fields) > 0 {
+		return nil, problem.Validation(fields)
+	}
+
+	for i, op := range ops {
+		if op.Op == api.DeleteMovie || op.Op == api.DeleteCharacter {
+			if p, ok := auth.FromContext(c); ok && !p.Role.Includes(auth.Admin) {
+				return nil, forbiddenOp(i, fmt.Sprintf("The admin role is required for %s, %s has %s", op.Op, p.Subject, p.Role))
+			}
+		}
+		if op.Op == api.CreateCharacter && op.Movie != nil && *op.Movie == swapiFranchise {
+			if err := ratelimit.Charge(c, ratelimit.SWAPI); err != nil {
+				return nil, err
+			}
+			exists, err := h.SWAPI.CharacterExists(c.Request().Context(), *op.Name)
+			if err != nil {
+				return nil, problem.Newf(http.StatusBadGateway, "SWAPI lookup for operation %d failed: %v", i, err)
+			}
+			if !exists {
+				p := problem.New(http.StatusBadRequest, "Character not found in Star Wars universe")
+				p.Errors = []problem.FieldError{{Field: fmt.Sprintf("operations.%d.name", i), Message: p.Detail}}
+				return nil, p
+			}
+		}
+	}
+	return ops, nil
+}
+
+// authorizeBatch checks the client certificate against every movie and
+// character the batch changes. It runs inside the batch, so no other change
+// can rename or relink them between the check and the commit. Versions and
+// references are checked by the transaction itself.
+func (h *Handlers) authorizeBatch(c echo.Context, ops []batchOp) error {
+	// Movies and characters created by the batch are the caller's own.
+	for i, op := range ops {
+		switch op.Op {
+		case api.UpdateMovie, api.DeleteMovie:
+			if op.id.ref == "" && !h.canManageMovie(c, op.id.id) {
+				return forbiddenOp(i, "Client certificate may not manage this movie")
+			}
+		case api.UpdateCharacter, api.DeleteCharacter:
+			if op.id.ref == "" && !h.canManageCharacter(c, op.id.id) {
+				return forbiddenOp(i, "Client certificate may not manage this character")
+			}
+		case api.LinkAppearance, api.UnlinkAppearance:
+			if op.movie.ref == "" && !h.canManageMovie(c, op.movie.id) {
+				return forbiddenOp(i, "Client certificate may not manage this movie")
+			}
+		}
+	}
+	return nil
+}
+
+func forbiddenOp(i int, detail string) *problem.Problem {
+	p := problem.New(http.StatusForbidden, detail)
+	p.Errors = []problem.FieldError{{Field: fmt.Sprintf("operations.%d", i), Message: detail}}
+	return p
+}
+
+func runBatchOp(tx *repository.Tx, op batchOp, refs map[string]uuid.UUID) (batchResult, error) {
+	id := func(t target) uuid.UUID {
+		if t.ref != "" {
+			return refs[t.ref]
+		}
+		return t.id
+	}
+	deref := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	result := batchResult{Op: op.Op, Ref: deref(op.Ref)}
+	switch op.Op {
+	case api.CreateMovie:
+		m, err := tx.CreateMovie(*op.Title, *op.ReleaseYear)
+		if err != nil {
+			return result, err
+		}
+		if op.Ref != nil {
+			refs[*op.Ref] = m.ID
+		}
+		result.Status, result.Movie = http.StatusCreated, &m
+	case api.UpdateMovie:
+		var year int
+		if op.ReleaseYear != nil {
+			year = *op.ReleaseYear
+		}
+		m, err := tx.UpdateMovie(id(op.id), deref(op.Title), year, op.version)
+		if err != nil {
+			return result, err
+		}
+		result.Status, result.Movie = http.StatusOK, &m
+	case api.DeleteMovie:
+		if err := tx.DeleteMovie(id(op.id), op.version); err != nil {
+			return result, err
+		}
+		result.Status = http.StatusNoContent
+	case api.CreateCharacter:
+		ch, err := tx.CreateCharacter(*op.Name)
+		if err != nil {
+			return result, err
+		}
+		if op.Ref != nil {
+			refs[*op.Ref] = ch.ID
+		}
+		result.Status, result.Character = http.StatusCreated, &ch
+	case api.UpdateCharacter:
+		ch, err := tx.UpdateCharacter(id(op.id), *op.Name, op.version)
+		if err != nil {
+			return result, err
+		}
+		result.Status, result.Character = http.StatusNoContent, &ch
+	case api.DeleteCharacter:
+		if err := tx.DeleteCharacter(id(op.id), op.version); err != nil {
+			return result, err
+		}
+		result.Status = http.StatusNoContent
+	case api.LinkAppearance, api.UnlinkAppearance:
+		a := api.Appearance{MovieId: id(op.movie), CharacterId: id(op.character)}
+		var err error
+		if op.Op == api.LinkAppearance {
+			err = tx.AddAppearance(a.MovieId, a.CharacterId)
+		} else {
+			err = tx.RemoveAppearance(a.MovieId, a.CharacterId)
+		}
+		if err != nil {
+			return result, err
+		}
+		result.Status, result.Appearance = http.StatusNoContent, &a
+	default:
+		return result, fmt.Errorf("%w: unknown operation %q", repository.ErrInvalidInput, op.Op)
+	}
+	return result, nil
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/bulk"
+	"example.com/go_basics/go/problem"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handlers) PostImport(c echo.Context, params api.PostImportParams) error {
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	format, err := bulk.FormatOf(mediaType)
+	if err != nil {
+		return problem.New(http.StatusUnsupportedMediaType, err.Error())
+	}
+	dryRun := params.DryRun != nil && *params.DryRun
+	report := bulk.Import(c.Request().Context(), h.Repo, format, c.Request().Body, dryRun)
+	if len(report.Errors) > 0 {
+		return c.JSON(http.StatusUnprocessableEntity, report)
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+func (h *Handlers) GetExport(c echo.Context, params api.GetExportParams) error {
+	format := bulk.JSON
+	if params.Format != nil {
+		format = bulk.Format(*params.Format)
+	}
+	snapshot := h.Repo.Snapshot(c.Request().Context())
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, bulk.ContentTypes[format])
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", "catalog."+string(format)))
+	res.WriteHeader(http.StatusOK)
+	return bulk.Export(res, format, snapshot)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
@@ -10,55 +11,141 @@ import (
 	"path/filepath"
 
 	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/entity"
+	"example.com/go_basics/go/logging"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/problem"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
 )
 
 func (h *Handlers) GetCertificates(c echo.Context) error {
+	logger := logging.FromContext(c.Request().Context())
 	var certs []api.Certificate
-	fmt.Println("GetCertificates")
 	// CA cert
-	caPath := "certs/ca.pem"
-	if cert, err := loadCertificate(caPath, "CA", "CA Authority"); err == nil {
+	if cert, err := loadCertificate(h.CA.CAPath(), "CA", "CA Authority"); err == nil {
 		certs = append(certs, cert)
 	} else {
-		fmt.Println("CA cert error:", err)
+		logger.Warn("CA certificate unavailable", zap.Error(err))
 	}
 
 	// Movie certs
-	movieDir := "certs/movies"
-	if err := loadCertsFromDir(movieDir, "Movie", "CA Authority", &certs); err != nil {
-		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
-	} else {
-		fmt.Println("CA movies cert error:", err)
+	movieDir := filepath.Join(h.CA.Dir, "movies")
+	if err := loadCertsFromDir(logger, movieDir, "Movie", "CA Authority", &certs); err != nil {
+		return err
 	}
 
 	// Character certs
-	charDir := "certs/characters"
-	if err := loadCertsFromDir(charDir, "Character", "Movie Authority", &certs); err != nil {
-		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
-	} else {
-		fmt.Println("CA characters cert error:", err)
+	charDir := filepath.Join(h.CA.Dir, "characters")
+	if err := loadCertsFromDir(logger, charDir, "Character", "Movie Authority", &certs); err != nil {
+		return err
 	}
 
 	return c.JSON(http.StatusOK, certs)
 }
 
-func loadCertsFromDir(dir, certType, issuer string, out *[]api.Certificate) error {
-	fmt.Println("Scanning dir:", dir)
+func (h *Handlers) PostCertificatesCsr(c echo.Context) error {
+	ctx := c.Request().Context()
+	var input api.CertificateSigningRequest
+	if err := c.Bind(&input); err != nil {
+		return problem.New(http.StatusBadRequest, "Invalid request body")
+	}
+	csr, err := pki.ParseCSR([]byte(input.Csr))
+	if err != nil {
+		return err
+	}
+
+	if movie, err := h.Repo.GetMovie(ctx, input.Id); err == nil {
+		// A movie certificate is an intermediate that can sign any
+		// character, so only admins obtain one, also when auth is off.
+		p, ok := auth.FromContext(c)
+		if !ok {
+			return problem.New(http.StatusForbidden, "The admin role is required to sign a movie certificate")
+		}
+		if !p.Role.Includes(auth.Admin) {
+			return problem.Newf(http.StatusForbidden, "The admin role is required to sign a movie certificate, %s has %s", p.Subject, p.Role)
+		}
+		if !h.canManageMovie(c, movie.ID) {
+			return problem.New(http.StatusForbidden, "Client certificate may not manage this movie")
+		}
+		cert, err := h.CA.SignMovieCSR(csr, movie.Title)
+		if err != nil {
+			return err
+		}
+		return signedCertificate(c, cert, api.CertificateTypeMovie)
+	}
+	character, err := h.Repo.GetCharacter(ctx, input.Id)
+	if err != nil {
+		return problem.Newf(http.StatusNotFound, "no movie or character with ID %s", input.Id)
+	}
+	if !h.canManageCharacter(c, character.ID) {
+		return problem.New(http.StatusForbidden, "Client certificate may not manage this character")
+	}
+	movie, err := h.characterIssuer(ctx, character, input.MovieId)
+	if err != nil {
+		return err
+	}
+	cert, err := h.CA.SignCharacterCSR(csr, character.Name, movie.Title)
+	if err != nil {
+		return err
+	}
+	return signedCertificate(c, cert, api.CertificateTypeCharacter)
+}
+
+// characterIssuer picks the movie whose certificate signs a character CSR.
+// Without an explicit movie ID the first appearance with a movie certificate wins.
+func (h *Handlers) characterIssuer(ctx context.Context, character entity.Character, movieID *uuid.UUID) (entity.Movie, error) {
+	movies, err := h.Repo.GetMoviesByCharacter(ctx, character.ID)
+	if err != nil {
+		return entity.Movie{}, err
+	}
+	if movieID != nil {
+		for _, m := range movies {
+			if m.ID == *movieID {
+				return m, nil
+			}
+		}
+		return entity.Movie{}, problem.Newf(http.StatusBadRequest, "character %s does not appear in movie %s", character.Name, *movieID)
+	}
+	for _, m := range movies {
+		if h.CA.HasMovieIssuer(m.Title) {
+			return m, nil
+		}
+	}
+	return entity.Movie{}, problem.Newf(http.StatusConflict, "no movie certificate available to sign character %s", character.Name)
+}
+
+func signedCertificate(c echo.Context, cert *x509.Certificate, certType api.CertificateType) error {
+	return c.JSON(http.StatusCreated, api.SignedCertificate{
+		Certificate: api.Certificate{
+			Id:       cert.SerialNumber.String(),
+			Type:     certType,
+			IssuedTo: cert.Subject.CommonName,
+			IssuedBy: cert.Issuer.CommonName,
+			IssuedAt: cert.NotBefore,
+		},
+		Pem: string(pki.EncodeCert(cert)),
+	})
+}
+
+func loadCertsFromDir(logger *zap.Logger, dir, certType, issuer string, out *[]api.Certificate) error {
 	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil || d.IsDir() || filepath.Ext(path) != ".pem" {
 			return nil
 		}
 		cert, err := loadCertificate(path, certType, issuer)
-		if err == nil {
-			*out = append(*out, cert)
+		if err != nil {
+			logger.Debug("skipping certificate", zap.String("path", path), zap.Error(err))
+			return nil
 		}
+		*out = append(*out, cert)
 		return nil
 	})
 }
 
 func loadCertificate(path, certType, issuer string) (api.Certificate, error) {
-	fmt.Println("Parsing cert:", path)
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return api.Certificate{}, fmt.Errorf("read error: %w", err)
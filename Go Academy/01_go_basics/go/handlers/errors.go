@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/logging"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/problem"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/translog"
+	"example.com/go_basics/go/webhooks"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// HTTPErrorHandler writes every error returned by a handler or middleware as
+// application/problem+json.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+	logger := logging.FromContext(c.Request().Context())
+	p := toProblem(err)
+	if p.Status >= http.StatusInternalServerError {
+		logger.Error("request failed", zap.Error(err))
+	}
+	if err := problem.Write(c, p); err != nil {
+		logger.Error("writing problem response", zap.Error(err))
+	}
+}
+
+func toProblem(err error) *problem.Problem {
+	if p, ok := problem.From(err); ok {
+		return p
+	}
+	switch {
+	case errors.Is(err, repository.ErrNotFound),
+		errors.Is(err, translog.ErrNotFound),
+		errors.Is(err, auth.ErrKeyNotFound),
+		errors.Is(err, webhooks.ErrNotFound),
+		errors.Is(err, webhooks.ErrDeadLetterNotFound):
+		return problem.New(http.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrInvalidInput),
+		errors.Is(err, pki.ErrInvalidCSR),
+		errors.Is(err, pki.ErrSubjectMismatch),
+		errors.Is(err, pki.ErrInvalidName),
+		errors.Is(err, translog.ErrTreeSize),
+		errors.Is(err, webhooks.ErrInvalid):
+		return problem.New(http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrVersionMismatch):
+		return problem.New(http.StatusPreconditionFailed, err.Error())
+	case errors.Is(err, pki.ErrIssuerMissing),
+		errors.Is(err, pki.ErrIssuerExists):
+		return problem.New(http.StatusConflict, err.Error())
+	}
+	return problem.New(http.StatusInternalServerError, "An unexpected error occurred")
+}
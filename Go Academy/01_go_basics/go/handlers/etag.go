@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"example.com/go_basics/go/problem"
+	"example.com/go_basics/go/repository"
+	"github.com/labstack/echo/v4"
+)
+
+const headerETag = "ETag"
+
+// etag quotes the version of a movie or character.
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// expectedVersion reads an If-Match header. It accepts * or a single strong
+// ETag, since the store can only compare against one version.
+func expectedVersion(ifMatch string) (int64, error) {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "*" {
+		return repository.AnyVersion, nil
+	}
+	unquoted, ok := strings.CutPrefix(ifMatch, `"`)
+	if ok {
+		unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if !ok || err != nil || version < 1 {
+		return 0, problem.Newf(http.StatusPreconditionFailed, "If-Match must be * or the ETag of one version, got %s", ifMatch)
+	}
+	return version, nil
+}
+
+// notModified reports whether an If-None-Match header lists the current
+// ETag. Weak ETags match as well, as RFC 9110 asks for GET.
+func notModified(ifNoneMatch *string, current string) bool {
+	if ifNoneMatch == nil {
+		return false
+	}
+	for tag := range strings.SplitSeq(*ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+	return false
+}
+
+// withETag answers a conditional GET with 304 or sends body with its ETag.
+func withETag(c echo.Context, ifNoneMatch *string, version int64, body any) error {
+	tag := etag(version)
+	c.Response().Header().Set(headerETag, tag)
+	if notModified(ifNoneMatch, tag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.JSON(http.StatusOK, body)
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/events"
+	"example.com/go_basics/go/logging"
+	"example.com/go_basics/go/problem"
+	"example.com/go_basics/go/signing"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+func (h *Handlers) GetEvents(c echo.Context, params api.GetEventsParams) error {
+	if err := h.checkFeed(c); err != nil {
+		return err
+	}
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	// Keeps nginx from buffering the stream.
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	return h.Repo.Events.Stream(c.Request().Context(), lastEventID(params.LastEventID),
+		func(e events.Event) error {
+			if err := events.WriteSSE(res, e); err != nil {
+				return err
+			}
+			res.Flush()
+			return nil
+		},
+		func() error {
+			if err := events.WriteSSEPing(res); err != nil {
+				return err
+			}
+			res.Flush()
+			return nil
+		})
+}
+
+// GetEventsWs sends every event as a JSON text message. Browsers cannot set
+// headers on WebSocket requests, so resuming takes a query parameter.
+func (h *Handlers) GetEventsWs(c echo.Context, params api.GetEventsWsParams) error {
+	if err := h.checkFeed(c); err != nil {
+		return err
+	}
+	logger := logging.FromContext(c.Request().Context())
+	// Without a handshake func any origin may connect, as with the other
+	// public reads.
+	websocket.Server{Handler: func(ws *websocket.Conn) {
+		defer ws.Close()
+		ctx, cancel := context.WithCancel(c.Request().Context())
+		defer cancel()
+		// Clients send nothing but the close frame, which ends this read.
+		go func() {
+			defer cancel()
+			var discard []byte
+			for websocket.Message.Receive(ws, &discard) == nil {
+			}
+		}()
+		err := h.Repo.Events.Stream(ctx, lastEventID(params.LastEventId),
+			func(e events.Event) error {
+				return websocket.JSON.Send(ws, e)
+			},
+			func() error {
+				ws.PayloadType = websocket.PingFrame
+				defer func() { ws.PayloadType = websocket.TextFrame }()
+				_, err := ws.Write(nil)
+				return err
+			})
+		if err != nil {
+			logger.Debug("change feed client gone", zap.Error(err))
+		}
+	}}.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+// checkFeed rejects requests the change feed cannot serve. Signing buffers
+// the whole response, which never ends for a stream.
+func (h *Handlers) checkFeed(c echo.Context) error {
+	if h.Repo.Events == nil {
+		return problem.New(http.StatusNotFound, "The change feed is disabled")
+	}
+	if signing.Requested(c.Request()) {
+		return problem.New(http.StatusNotAcceptable, "The change feed cannot be signed, fetch the changed resources signed instead")
+	}
+	return nil
+}
+
+func lastEventID(id *int64) uint64 {
+	if id == nil {
+		return 0
+	}
+	return uint64(*id)
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"net/http"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/entity"
+	"example.com/go_basics/go/problem"
+	"example.com/go_basics/go/repository"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func toFranchise(f entity.Franchise) api.Franchise {
+	out := api.Franchise{
+		ID:           f.ID,
+		Name:         f.Name,
+		Installments: make([]api.FranchiseInstallment, 0, len(f.Installments)),
+		Characters:   f.Characters,
+		Version:      f.Version,
+	}
+	for _, in := range f.Installments {
+		out.Installments = append(out.Installments, api.FranchiseInstallment{MovieId: in.MovieID, Kind: api.FranchiseInstallmentKind(in.Kind)})
+	}
+	return out
+}
+
+func installmentNodes(installments []repository.Installment) []api.InstallmentNode {
+	nodes := make([]api.InstallmentNode, 0, len(installments))
+	for _, in := range installments {
+		nodes = append(nodes, api.InstallmentNode{
+			Position: in.Position,
+			Kind:     in.Kind,
+			Movie:    api.MovieNode{ID: in.Movie.ID, Title: in.Movie.Title, Year: in.Movie.Year},
+		})
+	}
+	return nodes
+}
+
+// bindFranchise reads the name and installments of a franchise.
+func bindFranchise(c echo.Context) (string, []entity.Installment, error) {
+	var input api.NewFranchise
+	if err := c.Bind(&input); err != nil {
+		return "", nil, problem.New(http.StatusBadRequest, "Invalid request body")
+	}
+	var installments []entity.Installment
+	if input.Installments != nil {
+		for _, in := range *input.Installments {
+			installments = append(installments, entity.Installment{MovieID: in.MovieId, Kind: string(in.Kind)})
+		}
+	}
+	return input.Name, installments, nil
+}
+
+func (h *Handlers) GetFranchises(c echo.Context) error {
+	list := api.FranchiseList{Franchises: []api.Franchise{}}
+	for _, f := range h.Repo.ListFranchises(c.Request().Context()) {
+		list.Franchises = append(list.Franchises, toFranchise(f))
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+func (h *Handlers) PostFranchises(c echo.Context) error {
+	name, installments, err := bindFranchise(c)
+	if err != nil {
+		return err
+	}
+	franchise, err := h.Repo.CreateFranchise(c.Request().Context(), name, installments)
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set(headerETag, etag(franchise.Version))
+	return c.JSON(http.StatusCreated, toFranchise(franchise))
+}
+
+func (h *Handlers) GetFranchisesId(c echo.Context, id uuid.UUID, params api.GetFranchisesIdParams) error {
+	franchise, err := h.Repo.GetFranchise(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return withETag(c, params.IfNoneMatch, franchise.Version, toFranchise(franchise))
+}
+
+func (h *Handlers) PutFranchisesId(c echo.Context, id uuid.UUID, params api.PutFranchisesIdParams) error {
+	name, installments, err := bindFranchise(c)
+	if err != nil {
+		return err
+	}
+	version, err := expectedVersion(params.IfMatch)
+	if err != nil {
+		return err
+	}
+	franchise, err := h.Repo.UpdateFranchise(c.Request().Context(), id, name, installments, version)
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set(headerETag, etag(franchise.Version))
+	return c.JSON(http.StatusOK, toFranchise(franchise))
+}
+
+func (h *Handlers) DeleteFranchisesId(c echo.Context, id uuid.UUID, params api.DeleteFranchisesIdParams) error {
+	version, err := expectedVersion(params.IfMatch)
+	if err != nil {
+		return err
+	}
+	if err := h.Repo.DeleteFranchise(c.Request().Context(), id, version); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handlers) GetFranchisesIdCharacters(c echo.Context, id uuid.UUID) error {
+	characters, err := h.Repo.FranchiseCharacters(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	result := api.FranchiseCharacters{Characters: make([]api.FranchiseCharacter, 0, len(characters))}
+	for _, fc := range characters {
+		result.Characters = append(result.Characters, api.FranchiseCharacter{
+			Character:    api.CharacterNode{ID: fc.Character.ID, Name: fc.Character.Name},
+			Linked:       fc.Linked,
+			Installments: installmentNodes(fc.Installments),
+		})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *Handlers) PostFranchisesIdCharacters(c echo.Context, id uuid.UUID) error {
+	var input api.FranchiseCharacterLink
+	if err := c.Bind(&input); err != nil {
+		return problem.New(http.StatusBadRequest, "Invalid request body")
+	}
+	if _, err := h.Repo.LinkFranchiseCharacter(c.Request().Context(), id, input.CharacterId); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handlers) DeleteFranchisesIdCharacters(c echo.Context, id uuid.UUID, params api.DeleteFranchisesIdCharactersParams) error {
+	if _, err := h.Repo.UnlinkFranchiseCharacter(c.Request().Context(), id, params.CharacterId); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handlers) GetCharactersIdTimeline(c echo.Context, id uuid.UUID) error {
+	timeline, err := h.Repo.CharacterTimeline(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	result := api.CharacterTimeline{Franchises: make([]api.TimelineFranchise, 0, len(timeline))}
+	for _, t := range timeline {
+		result.Franchises = append(result.Franchises, api.TimelineFranchise{
+			ID:           t.Franchise.ID,
+			Name:         t.Franchise.Name,
+			Linked:       t.Linked,
+			Installments: installmentNodes(t.Installments),
+		})
+	}
+	return c.JSON(http.StatusOK, result)
+}
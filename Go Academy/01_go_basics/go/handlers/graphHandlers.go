@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/entity"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// defaultCoStarsLimit applies when a query sets no limit.
+	defaultCoStarsLimit = 100
+	// defaultPathDepth applies when a query sets no max_depth.
+	defaultPathDepth = 6
+)
+
+func characterNodes(characters []entity.Character) []api.CharacterNode {
+	nodes := make([]api.CharacterNode, 0, len(characters))
+	for _, c := range characters {
+		nodes = append(nodes, api.CharacterNode{ID: c.ID, Name: c.Name})
+	}
+	return nodes
+}
+
+func movieNodes(movies []entity.Movie) []api.MovieNode {
+	nodes := make([]api.MovieNode, 0, len(movies))
+	for _, m := range movies {
+		nodes = append(nodes, api.MovieNode{ID: m.ID, Title: m.Title, Year: m.Year})
+	}
+	return nodes
+}
+
+func (h *Handlers) GetCharactersIdCoStars(c echo.Context, id uuid.UUID, params api.GetCharactersIdCoStarsParams) error {
+	limit := defaultCoStarsLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	coStars, err := h.Repo.CoStars(c.Request().Context(), id, limit)
+	if err != nil {
+		return err
+	}
+	result := api.CoStars{CoStars: make([]api.CoStar, 0, len(coStars))}
+	for _, s := range coStars {
+		result.CoStars = append(result.CoStars, api.CoStar{
+			Character: api.CharacterNode{ID: s.Character.ID, Name: s.Character.Name},
+			Shared:    len(s.Movies),
+			Movies:    movieNodes(s.Movies),
+		})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *Handlers) GetCharactersIdPath(c echo.Context, id uuid.UUID, params api.GetCharactersIdPathParams) error {
+	depth := defaultPathDepth
+	if params.MaxDepth != nil {
+		depth = *params.MaxDepth
+	}
+	path, err := h.Repo.ShortestPath(c.Request().Context(), id, params.To, depth)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, api.CharacterPath{
+		Degrees:    len(path.Movies),
+		Characters: characterNodes(path.Characters),
+		Movies:     movieNodes(path.Movies),
+	})
+}
+
+func (h *Handlers) GetGraphComponents(c echo.Context) error {
+	components := h.Repo.Components(c.Request().Context())
+	result := api.GraphComponents{Components: make([]api.GraphComponent, 0, len(components))}
+	for _, cc := range components {
+		result.Components = append(result.Components, api.GraphComponent{
+			Characters: characterNodes(cc.Characters),
+			Movies:     movieNodes(cc.Movies),
+		})
+	}
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/graphql"
+	"example.com/go_basics/go/problem"
+	"example.com/go_basics/go/ratelimit"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handlers) GetGraphql(c echo.Context) error {
+	if err := h.checkGraphQL(); err != nil {
+		return err
+	}
+	return c.HTMLBlob(http.StatusOK, graphql.GraphiQL)
+}
+
+func (h *Handlers) PostGraphql(c echo.Context) error {
+	if err := h.checkGraphQL(); err != nil {
+		return err
+	}
+	var input api.GraphQLRequest
+	if err := c.Bind(&input); err != nil {
+		return problem.New(http.StatusBadRequest, "Invalid request body")
+	}
+	req := graphql.Request{Query: input.Query}
+	if input.OperationName != nil {
+		req.OperationName = *input.OperationName
+	}
+	if input.Variables != nil {
+		req.Variables = *input.Variables
+	}
+	ctx := graphql.WithGuard(c.Request().Context(), guard{h: h, c: c})
+	return c.JSON(http.StatusOK, h.GraphQL.Execute(ctx, req))
+}
+
+func (h *Handlers) checkGraphQL() error {
+	if h.GraphQL == nil {
+		return problem.New(http.StatusNotFound, "GraphQL is disabled")
+	}
+	return nil
+}
+
+// guard lets GraphQL mutations check the caller like the REST handlers do.
+type guard struct {
+	h *Handlers
+	c echo.Context
+}
+
+func (g guard) Principal() (auth.Principal, bool) {
+	return auth.FromContext(g.c)
+}
+
+func (g guard) CanManageMovie(id uuid.UUID) bool {
+	return g.h.canManageMovie(g.c, id)
+}
+
+func (g guard) CanManageCharacter(id uuid.UUID) bool {
+	return g.h.canManageCharacter(g.c, id)
+}
+
+func (g guard) Charge(class string) error {
+	return ratelimit.Charge(g.c, class)
+}
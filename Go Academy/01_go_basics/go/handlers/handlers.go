@@ -4,168 +4,260 @@ import (
 	"net/http"
 
 	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/entity"
+	"example.com/go_basics/go/graphql"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/problem"
+	"example.com/go_basics/go/ratelimit"
 	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/stats"
 	"example.com/go_basics/go/swapi"
-	"github.com/go-playground/validator/v10"
+	"example.com/go_basics/go/translog"
+	"example.com/go_basics/go/webhooks"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
+// swapiFranchise names the franchise whose new characters SWAPI has to
+// know. A character created with it as movie is checked as well.
+const swapiFranchise = "Star Wars"
+
 type Handlers struct {
-	Repo      *repository.Repository
-	Validator *validator.Validate
-	SWAPI     *swapi.Client
+	Repo     *repository.Repository
+	SWAPI    *swapi.Client
+	CA       *pki.Authority
+	Log      *translog.Log
+	Keys     *auth.KeyStore
+	Webhooks *webhooks.Dispatcher
+	GraphQL  *graphql.Server
+	Stats    *stats.Cache
 }
 
-func New(repo *repository.Repository) *Handlers {
+func New(repo *repository.Repository, ca *pki.Authority, log *translog.Log, sw *swapi.Client, keys *auth.KeyStore, hooks *webhooks.Dispatcher, gq *graphql.Server) *Handlers {
 	return &Handlers{
-		Repo:      repo,
-		Validator: validator.New(),
-		SWAPI:     swapi.New(),
+		Repo:     repo,
+		SWAPI:    sw,
+		CA:       ca,
+		Log:      log,
+		Keys:     keys,
+		Webhooks: hooks,
+		GraphQL:  gq,
+		Stats:    stats.New(repo),
 	}
 }
 
 func (h *Handlers) PostMovies(c echo.Context) error {
+	ctx := c.Request().Context()
 	var input api.Movie
 	if err := c.Bind(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid input"})
-	}
-	if err := h.Validator.Struct(input); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Validation failed", "details": err.Error()})
+		return problem.New(http.StatusBadRequest, "Invalid request body")
 	}
-	movie, err := h.Repo.CreateMovie(input.Title, input.ReleaseYear)
+	movie, err := h.Repo.CreateMovie(ctx, input.Title, input.ReleaseYear)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		return err
 	}
+	c.Response().Header().Set(headerETag, etag(movie.Version))
 	return c.JSON(http.StatusCreated, movie)
 }
 
 func (h *Handlers) PostCharacters(c echo.Context) error {
+	ctx := c.Request().Context()
 	var input api.Character
 	if err := c.Bind(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid input"})
+		return problem.New(http.StatusBadRequest, "Invalid request body")
 	}
-	if err := h.Validator.Struct(input); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Validation failed", "details": err.Error()})
+
+	var franchise *entity.Franchise
+	if input.FranchiseId != nil {
+		f, err := h.Repo.GetFranchise(ctx, *input.FranchiseId)
+		if err != nil {
+			return err
+		}
+		franchise = &f
 	}
 
-	if input.Movie != nil && *input.Movie == "Star Wars" {
-		exists, err := h.SWAPI.CharacterExists(input.Name)
+	if (franchise != nil && franchise.Name == swapiFranchise) || (input.Movie != nil && *input.Movie == swapiFranchise) {
+		if err := ratelimit.Charge(c, ratelimit.SWAPI); err != nil {
+			return err
+		}
+		exists, err := h.SWAPI.CharacterExists(ctx, input.Name)
 		if err != nil {
-			return c.JSON(http.StatusBadGateway, echo.Map{"error": "SWAPI lookup failed", "details": err.Error()})
+			return problem.Newf(http.StatusBadGateway, "SWAPI lookup failed: %v", err)
 		}
 		if !exists {
-			return c.JSON(http.StatusBadRequest, echo.Map{"error": "Character not found in Star Wars universe"})
+			return problem.New(http.StatusBadRequest, "Character not found in Star Wars universe")
 		}
 	}
 
-	char, err := h.Repo.CreateCharacter(input.Name)
+	var char entity.Character
+	var err error
+	if franchise != nil {
+		char, err = h.Repo.CreateCharacterInFranchise(ctx, input.Name, franchise.ID)
+	} else {
+		char, err = h.Repo.CreateCharacter(ctx, input.Name)
+	}
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		return err
 	}
+	c.Response().Header().Set(headerETag, etag(char.Version))
 	return c.JSON(http.StatusCreated, char)
 }
 
 func (h *Handlers) PostAppearances(c echo.Context) error {
+	ctx := c.Request().Context()
 	var input api.Appearance
 	if err := c.Bind(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid input"})
+		return problem.New(http.StatusBadRequest, "Invalid request body")
 	}
-	if err := h.Validator.Struct(input); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Validation failed", "details": err.Error()})
+	if !h.canManageMovie(c, input.MovieId) {
+		return problem.New(http.StatusForbidden, "Client certificate may not manage this movie")
 	}
-	movieID, err := uuid.Parse(input.MovieId)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid movie_id"})
+	if err := h.Repo.AddAppearance(ctx, input.MovieId, input.CharacterId); err != nil {
+		return err
 	}
-	charID, err := uuid.Parse(input.CharacterId)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid character_id"})
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handlers) DeleteAppearances(c echo.Context, params api.DeleteAppearancesParams) error {
+	ctx := c.Request().Context()
+	if !h.canManageMovie(c, params.MovieId) {
+		return problem.New(http.StatusForbidden, "Client certificate may not manage this movie")
 	}
-	if err := h.Repo.AddAppearance(movieID, charID); err != nil {
-		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	if err := h.Repo.RemoveAppearance(ctx, params.MovieId, params.CharacterId); err != nil {
+		return err
 	}
 	return c.NoContent(http.StatusNoContent)
 }
 
 func (h *Handlers) GetMovies(c echo.Context) error {
-	movies, err := h.Repo.ListAllMovies()
+	ctx := c.Request().Context()
+	movies, err := h.Repo.ListAllMovies(ctx)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+		return err
 	}
 	return c.JSON(http.StatusOK, movies)
 }
 
 func (h *Handlers) GetCharacters(c echo.Context) error {
-	chars, err := h.Repo.ListAllCharacters()
+	ctx := c.Request().Context()
+	chars, err := h.Repo.ListAllCharacters(ctx)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+		return err
 	}
 	return c.JSON(http.StatusOK, chars)
 }
 
-func (h *Handlers) GetCharactersByMovie(c echo.Context, params api.GetCharactersByMovieParams) error {
-	if params.Title == "" {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Missing title"})
+func (h *Handlers) GetMoviesId(c echo.Context, id uuid.UUID, params api.GetMoviesIdParams) error {
+	movie, err := h.Repo.GetMovie(c.Request().Context(), id)
+	if err != nil {
+		return err
 	}
-	chars, err := h.Repo.GetCharactersByMovieTitle(params.Title)
+	return withETag(c, params.IfNoneMatch, movie.Version, movie)
+}
+
+func (h *Handlers) GetCharactersId(c echo.Context, id uuid.UUID, params api.GetCharactersIdParams) error {
+	char, err := h.Repo.GetCharacter(c.Request().Context(), id)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+		return err
+	}
+	return withETag(c, params.IfNoneMatch, char.Version, char)
+}
+
+func (h *Handlers) GetCharactersByMovie(c echo.Context, params api.GetCharactersByMovieParams) error {
+	ctx := c.Request().Context()
+	chars, err := h.Repo.GetCharactersByMovieTitle(ctx, params.Title)
+	if err != nil {
+		return err
 	}
 	return c.JSON(http.StatusOK, chars)
 }
 
 func (h *Handlers) GetMoviesByCharacter(c echo.Context, params api.GetMoviesByCharacterParams) error {
-	if params.Name == "" {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Missing name"})
-	}
-	titles, err := h.Repo.GetMovieTitlesByCharacterName(params.Name)
+	ctx := c.Request().Context()
+	titles, err := h.Repo.GetMovieTitlesByCharacterName(ctx, params.Name)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+		return err
 	}
 	return c.JSON(http.StatusOK, titles)
 }
 
-func (h *Handlers) PutCharacters(c echo.Context) error {
+func (h *Handlers) PutCharacters(c echo.Context, params api.PutCharactersParams) error {
+	ctx := c.Request().Context()
 	var input api.Character
 	if err := c.Bind(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid input"})
+		return problem.New(http.StatusBadRequest, "Invalid request body")
 	}
-	if err := h.Validator.Struct(input); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Validation failed", "details": err.Error()})
+	if !h.canManageCharacter(c, params.Id) {
+		return problem.New(http.StatusForbidden, "Client certificate may not manage this character")
 	}
-	idStr := c.QueryParam("id")
-	if idStr == "" {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Missing id"})
-	}
-	id, err := uuid.Parse(idStr)
+	version, err := expectedVersion(params.IfMatch)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid UUID format"})
+		return err
 	}
-	if err := h.Repo.UpdateCharacter(id, input.Name); err != nil {
-		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	char, err := h.Repo.UpdateCharacter(ctx, params.Id, input.Name, version)
+	if err != nil {
+		return err
 	}
+	c.Response().Header().Set(headerETag, etag(char.Version))
 	return c.NoContent(http.StatusNoContent)
 }
 
+func (h *Handlers) PatchMoviesId(c echo.Context, id uuid.UUID, params api.PatchMoviesIdParams) error {
+	ctx := c.Request().Context()
+	var input api.MoviePatch
+	if err := c.Bind(&input); err != nil {
+		return problem.New(http.StatusBadRequest, "Invalid request body")
+	}
+	if !h.canManageMovie(c, id) {
+		return problem.New(http.StatusForbidden, "Client certificate may not manage this movie")
+	}
+	version, err := expectedVersion(params.IfMatch)
+	if err != nil {
+		return err
+	}
+	var title string
+	var year int
+	if input.Title != nil {
+		title = *input.Title
+	}
+	if input.ReleaseYear != nil {
+		year = *input.ReleaseYear
+	}
+	movie, err := h.Repo.UpdateMovie(ctx, id, title, year, version)
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set(headerETag, etag(movie.Version))
+	return c.JSON(http.StatusOK, movie)
+}
+
 func (h *Handlers) DeleteMovies(c echo.Context, params api.DeleteMoviesParams) error {
-	id, err := uuid.Parse(params.Id)
+	ctx := c.Request().Context()
+	if !h.canManageMovie(c, params.Id) {
+		return problem.New(http.StatusForbidden, "Client certificate may not manage this movie")
+	}
+	version, err := expectedVersion(params.IfMatch)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid UUID format"})
+		return err
 	}
-	if err := h.Repo.DeleteMovie(id); err != nil {
-		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	if err := h.Repo.DeleteMovie(ctx, params.Id, version); err != nil {
+		return err
 	}
 	return c.NoContent(http.StatusNoContent)
 }
 
-func (h *Handlers) DeleteCharactersId(c echo.Context, id string) error {
-	uid, err := uuid.Parse(id)
+func (h *Handlers) DeleteCharactersId(c echo.Context, id uuid.UUID, params api.DeleteCharactersIdParams) error {
+	ctx := c.Request().Context()
+	if !h.canManageCharacter(c, id) {
+		return problem.New(http.StatusForbidden, "Client certificate may not manage this character")
+	}
+	version, err := expectedVersion(params.IfMatch)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid UUID format"})
+		return err
 	}
-	if err := h.Repo.DeleteCharacter(uid); err != nil {
-		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	if err := h.Repo.DeleteCharacter(ctx, id, version); err != nil {
+		return err
 	}
 	return c.NoContent(http.StatusNoContent)
 }
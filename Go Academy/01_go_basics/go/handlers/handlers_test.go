@@ -0,0 +1,421 @@
+package handlers_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/handlers"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/problem"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/routes"
+	"example.com/go_basics/go/swapi"
+	"example.com/go_basics/go/validation"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func request(t *testing.T, e http.Handler, method, target, body string, header ...string) (*httptest.ResponseRecorder, problem.Problem) {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	var p problem.Problem
+	if rec.Header().Get("Content-Type") == problem.ContentType {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	}
+	return rec, p
+}
+
+func TestProblemResponses(t *testing.T) {
+	repo := repository.New(db.New(), nil, nil, nil)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	e := routes.NewEchoRouter(handlers.New(repo, &pki.Authority{Dir: t.TempDir()}, nil, nil, nil, nil, nil), nil, zap.NewNop(), nil, nil, nil, nil)
+	spec, err := api.GetSwagger()
+	require.NoError(t, err)
+	e.Use(validation.ResponseMiddleware(spec))
+
+	rec, p := request(t, e, http.MethodPost, "/movies", `{"title":"","release_year":1800}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, problem.TypeValidation, p.Type)
+	assert.ElementsMatch(t, []problem.FieldError{
+		{Field: "title", Message: "must not be empty"},
+		{Field: "release_year", Message: "must be at least 1900"},
+	}, p.Errors)
+
+	rec, p = request(t, e, http.MethodPost, "/movies", `{"title":"Shrek"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, []problem.FieldError{{Field: "release_year", Message: "is required"}}, p.Errors)
+
+	rec, p = request(t, e, http.MethodPost, "/movies", `{"title":`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "Invalid request body", p.Detail)
+
+	rec, p = request(t, e, http.MethodPut, "/characters?id="+uuid.NewString(), `{"name":"Ghost"}`, "If-Match", "*")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "/characters", p.Instance)
+
+	rec, _ = request(t, e, http.MethodPut, "/characters?id="+donkey.ID.String(), `{"name":"Donkey the Brave"}`, "If-Match", "*")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec, p = request(t, e, http.MethodPut, "/characters", `{"name":"Donkey"}`, "If-Match", "*")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, []problem.FieldError{{Field: "id", Message: "is required"}}, p.Errors)
+
+	rec, p = request(t, e, http.MethodDelete, "/characters/donkey", "", "If-Match", "*")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, []problem.FieldError{{Field: "id", Message: "must be a valid UUID"}}, p.Errors)
+
+	rec, p = request(t, e, http.MethodPost, "/appearances", `{"movie_id":"shrek","character_id":"`+donkey.ID.String()+`"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, []problem.FieldError{{Field: "movie_id", Message: "must be a valid UUID"}}, p.Errors)
+
+	rec, p = request(t, e, http.MethodGet, "/movies", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, http.StatusNotFound, p.Status)
+
+	rec, p = request(t, e, http.MethodGet, "/nowhere", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "Not Found", p.Title)
+
+	rec, p = request(t, e, http.MethodPost, "/certificates/csr", `{"id":"`+donkey.ID.String()+`","csr":"garbage"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, p.Detail, "invalid certificate signing request")
+
+	// Without a principal, as with auth off, no movie intermediate is signed.
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "Shrek"}}, key)
+	require.NoError(t, err)
+	csr, err := json.Marshal(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})))
+	require.NoError(t, err)
+	rec, p = request(t, e, http.MethodPost, "/certificates/csr", `{"id":"`+shrek.ID.String()+`","csr":`+string(csr)+`}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, p.Detail, "admin role is required")
+}
+
+func TestConditionalRequests(t *testing.T) {
+	repo := repository.New(db.New(), nil, nil, nil)
+	e := routes.NewEchoRouter(handlers.New(repo, &pki.Authority{Dir: t.TempDir()}, nil, nil, nil, nil, nil), nil, zap.NewNop(), nil, nil, nil, nil)
+	spec, err := api.GetSwagger()
+	require.NoError(t, err)
+	e.Use(validation.ResponseMiddleware(spec))
+
+	rec, _ := request(t, e, http.MethodPost, "/movies", `{"title":"Shrek","release_year":2000}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+	var movie struct{ ID uuid.UUID }
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &movie))
+	target := "/movies/" + movie.ID.String()
+
+	rec, _ = request(t, e, http.MethodGet, target, "", "If-None-Match", `"1"`)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	rec, p := request(t, e, http.MethodPatch, target, `{"release_year":2001}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, []problem.FieldError{{Field: "If-Match", Message: "is required"}}, p.Errors)
+
+	rec, _ = request(t, e, http.MethodPatch, target, `{"release_year":2001}`, "If-Match", `"1"`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+	// A client still holding version 1 neither overwrites nor deletes.
+	rec, p = request(t, e, http.MethodPatch, target, `{"title":"Shrek 2"}`, "If-Match", `"1"`)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	assert.Contains(t, p.Detail, "is at version 2, not 1")
+	rec, _ = request(t, e, http.MethodDelete, "/movies?id="+movie.ID.String(), "", "If-Match", `"1"`)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	rec, _ = request(t, e, http.MethodPatch, target, `{"title":"Shrek 2"}`, "If-Match", `W/"2"`)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	rec, _ = request(t, e, http.MethodGet, target, "", "If-None-Match", `"1"`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+	assert.Contains(t, rec.Body.String(), `"year":2001`)
+
+	rec, _ = request(t, e, http.MethodDelete, "/movies?id="+movie.ID.String(), "", "If-Match", `"2"`)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec, _ = request(t, e, http.MethodGet, target, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestBatch(t *testing.T) {
+	repo := repository.New(db.New(), nil, nil, nil)
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	e := routes.NewEchoRouter(handlers.New(repo, &pki.Authority{Dir: t.TempDir()}, nil, nil, nil, nil, nil), nil, zap.NewNop(), nil, nil, nil, nil)
+	spec, err := api.GetSwagger()
+	require.NoError(t, err)
+	e.Use(validation.ResponseMiddleware(spec))
+
+	rec, _ := request(t, e, http.MethodPost, "/batch", `{"operations":[
+		{"op":"create_movie","ref":"s2","title":"Shrek 2","release_year":2004},
+		{"op":"create_character","ref":"fiona","name":"Fiona"},
+		{"op":"link_appearance","movie_id":"$s2","character_id":"$fiona"},
+		{"op":"update_movie","id":"`+shrek.ID.String()+`","title":"Shrek!","version":1}]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var body struct {
+		Results []struct {
+			Op     string         `json:"op"`
+			Status int            `json:"status"`
+			Ref    string         `json:"ref"`
+			Movie  map[string]any `json:"movie"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Results, 4)
+	assert.Equal(t, "s2", body.Results[0].Ref)
+	assert.Equal(t, http.StatusCreated, body.Results[1].Status)
+	assert.Equal(t, http.StatusNoContent, body.Results[2].Status)
+	assert.Equal(t, "Shrek!", body.Results[3].Movie["title"])
+	snap := repo.Snapshot(t.Context())
+	assert.Len(t, snap.Movies, 2)
+	assert.Len(t, snap.Appearances, 1)
+
+	// The stale version fails the third operation and undoes the others.
+	rec, p := request(t, e, http.MethodPost, "/batch", `{"operations":[
+		{"op":"create_character","ref":"donkey","name":"Donkey"},
+		{"op":"link_appearance","movie_id":"`+shrek.ID.String()+`","character_id":"$donkey"},
+		{"op":"delete_movie","id":"`+shrek.ID.String()+`","version":1}]}`)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	assert.Equal(t, "operations.2", p.Errors[0].Field)
+	assert.Equal(t, snap, repo.Snapshot(t.Context()))
+
+	rec, p = request(t, e, http.MethodPost, "/batch", `{"operations":[
+		{"op":"link_appearance","movie_id":"$s3","character_id":"$fiona"},
+		{"op":"create_character","ref":"fiona","name":"Fiona"}]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, problem.TypeValidation, p.Type)
+	assert.Equal(t, []problem.FieldError{
+		{Field: "operations.0.movie_id", Message: `no earlier operation has the ref "s3"`},
+		{Field: "operations.0.character_id", Message: `no earlier operation has the ref "fiona"`},
+	}, p.Errors)
+}
+
+func TestTrash(t *testing.T) {
+	repo := repository.New(db.New(), nil, nil, nil)
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek.ID, donkey.ID))
+	e := routes.NewEchoRouter(handlers.New(repo, &pki.Authority{Dir: t.TempDir()}, nil, nil, nil, nil, nil), nil, zap.NewNop(), nil, nil, nil, nil)
+	spec, err := api.GetSwagger()
+	require.NoError(t, err)
+	e.Use(validation.ResponseMiddleware(spec))
+
+	rec, _ := request(t, e, http.MethodDelete, "/movies?id="+shrek.ID.String(), "", "If-Match", `"1"`)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	rec, _ = request(t, e, http.MethodGet, "/trash", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var trash api.Trash
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &trash))
+	require.Len(t, trash.Movies, 1)
+	assert.Equal(t, shrek.ID, trash.Movies[0].ID)
+	assert.Equal(t, 1, trash.Movies[0].Appearances)
+	assert.Empty(t, trash.Characters)
+
+	rec, _ = request(t, e, http.MethodPost, "/movies/"+shrek.ID.String()+"/restore", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+	assert.NotContains(t, rec.Body.String(), "deleted_at")
+	chars, err := repo.GetCharactersByMovie(t.Context(), shrek.ID)
+	require.NoError(t, err)
+	assert.Len(t, chars, 1)
+
+	rec, _ = request(t, e, http.MethodPost, "/movies/"+shrek.ID.String()+"/restore", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec, _ = request(t, e, http.MethodPost, "/characters/"+donkey.ID.String()+"/restore", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGraph(t *testing.T) {
+	repo := repository.New(db.New(), nil, nil, nil)
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	fiona, _ := repo.CreateCharacter(t.Context(), "Fiona")
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek.ID, donkey.ID))
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek.ID, fiona.ID))
+	e := routes.NewEchoRouter(handlers.New(repo, &pki.Authority{Dir: t.TempDir()}, nil, nil, nil, nil, nil), nil, zap.NewNop(), nil, nil, nil, nil)
+	spec, err := api.GetSwagger()
+	require.NoError(t, err)
+	e.Use(validation.ResponseMiddleware(spec))
+
+	rec, _ := request(t, e, http.MethodGet, "/characters/"+donkey.ID.String()+"/co-stars", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var coStars api.CoStars
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &coStars))
+	require.Len(t, coStars.CoStars, 1)
+	assert.Equal(t, fiona.ID, coStars.CoStars[0].Character.ID)
+	assert.Equal(t, 1, coStars.CoStars[0].Shared)
+
+	rec, _ = request(t, e, http.MethodGet, "/characters/"+donkey.ID.String()+"/path?to="+fiona.ID.String(), "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var path api.CharacterPath
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &path))
+	assert.Equal(t, 1, path.Degrees)
+	assert.Equal(t, []api.MovieNode{{ID: shrek.ID, Title: "Shrek", Year: 2001}}, path.Movies)
+	lonely, _ := repo.CreateCharacter(t.Context(), "Lonely")
+	rec, _ = request(t, e, http.MethodGet, "/characters/"+donkey.ID.String()+"/path?to="+lonely.ID.String(), "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec, _ = request(t, e, http.MethodGet, "/characters/"+donkey.ID.String()+"/path?to="+fiona.ID.String()+"&max_depth=13", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, _ = request(t, e, http.MethodGet, "/graph/components", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var components api.GraphComponents
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &components))
+	require.Len(t, components.Components, 2)
+	assert.Len(t, components.Components[0].Characters, 2)
+	assert.Empty(t, components.Components[1].Movies)
+}
+
+func TestStats(t *testing.T) {
+	repo := repository.New(db.New(), nil, nil, nil)
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	repo.CreateMovie(t.Context(), "The Empire Strikes Back", 1980)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	repo.CreateCharacter(t.Context(), "Yoda")
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek.ID, donkey.ID))
+	e := routes.NewEchoRouter(handlers.New(repo, &pki.Authority{Dir: t.TempDir()}, nil, nil, nil, nil, nil), nil, zap.NewNop(), nil, nil, nil, nil)
+	spec, err := api.GetSwagger()
+	require.NoError(t, err)
+	e.Use(validation.ResponseMiddleware(spec))
+
+	rec, _ := request(t, e, http.MethodGet, "/stats", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var summary api.StatsSummary
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summary))
+	assert.Equal(t, api.StatsSummary{Movies: 2, Characters: 2, Appearances: 1, AverageCast: 0.5, EmptyMovies: 1, OrphanCharacters: 1}, summary)
+
+	rec, _ = request(t, e, http.MethodGet, "/stats/movies-per-year?by=decade&format=csv", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "decade,movies\n1980,1\n2000,1\n", rec.Body.String())
+
+	rec, _ = request(t, e, http.MethodGet, "/stats/top-characters?limit=1", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var top api.TopCharacters
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &top))
+	assert.Equal(t, []api.CharacterCount{{ID: donkey.ID, Name: "Donkey", Movies: 1}}, top.Characters)
+
+	rec, _ = request(t, e, http.MethodGet, "/stats/orphan-characters?format=csv", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), ",Yoda\n")
+
+	// The cached stats follow the next change.
+	require.NoError(t, repo.RemoveAppearance(t.Context(), shrek.ID, donkey.ID))
+	rec, _ = request(t, e, http.MethodGet, "/stats/empty-movies", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var empty api.EmptyMovies
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &empty))
+	assert.Len(t, empty.Movies, 2)
+
+	rec, _ = request(t, e, http.MethodGet, "/stats?format=xml", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestFranchises(t *testing.T) {
+	fakeSWAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("search") == "Yoda" {
+			io.WriteString(w, `{"count":1}`)
+			return
+		}
+		io.WriteString(w, `{"count":0}`)
+	}))
+	t.Cleanup(fakeSWAPI.Close)
+	cfg := config.Default()
+	cfg.SWAPI.BaseURL = fakeSWAPI.URL
+	repo := repository.New(db.New(), nil, nil, nil)
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	shrek2, _ := repo.CreateMovie(t.Context(), "Shrek 2", 2004)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek2.ID, donkey.ID))
+	e := routes.NewEchoRouter(handlers.New(repo, &pki.Authority{Dir: t.TempDir()}, nil, swapi.New(cfg, nil), nil, nil, nil), nil, zap.NewNop(), nil, nil, nil, nil)
+	spec, err := api.GetSwagger()
+	require.NoError(t, err)
+	e.Use(validation.ResponseMiddleware(spec))
+
+	rec, _ := request(t, e, http.MethodPost, "/franchises", `{"name":"Shrek","installments":[{"movie_id":"`+shrek.ID.String()+`","kind":"original"}]}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+	var franchise api.Franchise
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &franchise))
+	id := franchise.ID.String()
+	rec, _ = request(t, e, http.MethodPost, "/franchises", `{"name":"Shrek","installments":[{"movie_id":"`+shrek.ID.String()+`","kind":"remake"}]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, _ = request(t, e, http.MethodPut, "/franchises/"+id, `{"name":"Shrek","installments":[{"movie_id":"`+shrek.ID.String()+`","kind":"original"},{"movie_id":"`+shrek2.ID.String()+`","kind":"sequel"}]}`, "If-Match", `"1"`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+	rec, _ = request(t, e, http.MethodPut, "/franchises/"+id, `{"name":"Shrek"}`, "If-Match", `"1"`)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	rec, _ = request(t, e, http.MethodGet, "/franchises/"+id, "", "If-None-Match", `"2"`)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	rec, _ = request(t, e, http.MethodPost, "/characters", `{"name":"Dragon","franchise_id":"`+id+`"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	rec, _ = request(t, e, http.MethodGet, "/franchises/"+id+"/characters", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var characters api.FranchiseCharacters
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &characters))
+	require.Len(t, characters.Characters, 2)
+	assert.Equal(t, "Donkey", characters.Characters[0].Character.Name)
+	assert.Equal(t, []api.InstallmentNode{{Position: 2, Kind: "sequel", Movie: api.MovieNode{ID: shrek2.ID, Title: "Shrek 2", Year: 2004}}}, characters.Characters[0].Installments)
+	assert.Equal(t, "Dragon", characters.Characters[1].Character.Name)
+	assert.True(t, characters.Characters[1].Linked)
+
+	rec, _ = request(t, e, http.MethodGet, "/characters/"+donkey.ID.String()+"/timeline", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var timeline api.CharacterTimeline
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &timeline))
+	require.Len(t, timeline.Franchises, 1)
+	assert.Equal(t, "Shrek", timeline.Franchises[0].Name)
+	assert.Len(t, timeline.Franchises[0].Installments, 1)
+
+	rec, _ = request(t, e, http.MethodDelete, "/franchises/"+id+"/characters?character_id="+donkey.ID.String(), "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec, _ = request(t, e, http.MethodPost, "/franchises/"+id+"/characters", `{"character_id":"`+donkey.ID.String()+`"}`)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec, _ = request(t, e, http.MethodDelete, "/franchises/"+id+"/characters?character_id="+donkey.ID.String(), "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	// Star Wars characters are checked against SWAPI by their franchise.
+	rec, _ = request(t, e, http.MethodPost, "/franchises", `{"name":"Star Wars"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &franchise))
+	rec, _ = request(t, e, http.MethodPost, "/characters", `{"name":"Shrek","franchise_id":"`+franchise.ID.String()+`"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = request(t, e, http.MethodPost, "/characters", `{"name":"Yoda","franchise_id":"`+franchise.ID.String()+`"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec, _ = request(t, e, http.MethodPost, "/characters", `{"name":"Yoda","franchise_id":"`+uuid.NewString()+`"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec, _ = request(t, e, http.MethodGet, "/franchises", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var list api.FranchiseList
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Franchises, 2)
+	assert.Len(t, list.Franchises[1].Characters, 1)
+	rec, _ = request(t, e, http.MethodDelete, "/franchises/"+id, "", "If-Match", "*")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec, _ = request(t, e, http.MethodGet, "/franchises/"+id, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/problem"
+	"example.com/go_basics/go/translog"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handlers) GetLogSth(c echo.Context) error {
+	sth, err := h.Log.SignedTreeHead()
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, api.SignedTreeHead{
+		TreeSize:  sth.TreeSize,
+		Timestamp: sth.Timestamp,
+		RootHash:  sth.RootHash,
+		Signature: sth.Signature,
+	})
+}
+
+func (h *Handlers) GetLogProofInclusion(c echo.Context, params api.GetLogProofInclusionParams) error {
+	var index int
+	var err error
+	switch {
+	case params.Hash != nil:
+		hash, decodeErr := translog.DecodeHash(*params.Hash)
+		if decodeErr != nil {
+			return problem.New(http.StatusBadRequest, decodeErr.Error())
+		}
+		index, err = h.Log.LeafIndex(hash)
+	case params.Serial != nil:
+		index, err = h.Log.SerialIndex(*params.Serial)
+	default:
+		return problem.New(http.StatusBadRequest, "Missing hash or serial")
+	}
+	if err != nil {
+		return err
+	}
+
+	size := h.Log.Size()
+	if params.TreeSize != nil {
+		size = *params.TreeSize
+	}
+	proof, err := h.Log.InclusionProof(index, size)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, api.InclusionProof{
+		LeafIndex: index,
+		TreeSize:  size,
+		AuditPath: emptyIfNil(proof),
+	})
+}
+
+func (h *Handlers) GetLogProofConsistency(c echo.Context, params api.GetLogProofConsistencyParams) error {
+	second := h.Log.Size()
+	if params.Second != nil {
+		second = *params.Second
+	}
+	proof, err := h.Log.ConsistencyProof(params.First, second)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, api.ConsistencyProof{
+		First:       params.First,
+		Second:      second,
+		Consistency: emptyIfNil(proof),
+	})
+}
+
+func emptyIfNil(proof [][]byte) [][]byte {
+	if proof == nil {
+		return [][]byte{}
+	}
+	return proof
+}
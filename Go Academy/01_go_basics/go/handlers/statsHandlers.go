@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+
+	"example.com/go_basics/go/api"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultTopCharacters applies when a query sets no limit.
+const defaultTopCharacters = 10
+
+// writeStats sends v as JSON, or the rows under the header as CSV.
+func writeStats(c echo.Context, format *api.StatsFormat, v any, header []string, rows func() [][]string) error {
+	if format == nil || *format != api.ReportFormatCsv {
+		return c.JSON(http.StatusOK, v)
+	}
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/csv")
+	res.WriteHeader(http.StatusOK)
+	w := csv.NewWriter(res)
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	if err := w.WriteAll(rows()); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+func (h *Handlers) GetStats(c echo.Context, params api.GetStatsParams) error {
+	r := h.Stats.Report(c.Request().Context())
+	summary := api.StatsSummary{
+		Movies:           r.Movies,
+		Characters:       r.Characters,
+		Appearances:      r.Appearances,
+		AverageCast:      r.AverageCast,
+		EmptyMovies:      len(r.EmptyMovies),
+		OrphanCharacters: len(r.OrphanCharacters),
+	}
+	return writeStats(c, params.Format, summary, []string{"statistic", "value"}, func() [][]string {
+		return [][]string{
+			{"movies", strconv.Itoa(summary.Movies)},
+			{"characters", strconv.Itoa(summary.Characters)},
+			{"appearances", strconv.Itoa(summary.Appearances)},
+			{"average_cast", strconv.FormatFloat(summary.AverageCast, 'f', -1, 64)},
+			{"empty_movies", strconv.Itoa(summary.EmptyMovies)},
+			{"orphan_characters", strconv.Itoa(summary.OrphanCharacters)},
+		}
+	})
+}
+
+func (h *Handlers) GetStatsMoviesPerYear(c echo.Context, params api.GetStatsMoviesPerYearParams) error {
+	r := h.Stats.Report(c.Request().Context())
+	result := api.MoviesPerYear{By: api.MoviesPerYearByYear, Counts: []api.YearCount{}}
+	counts := r.MoviesPerYear
+	if params.By != nil && *params.By == api.GetStatsMoviesPerYearParamsByDecade {
+		result.By, counts = api.MoviesPerYearByDecade, r.MoviesPerDecade()
+	}
+	for _, y := range counts {
+		result.Counts = append(result.Counts, api.YearCount{Year: y.Year, Movies: y.Movies})
+	}
+	return writeStats(c, params.Format, result, []string{string(result.By), "movies"}, func() [][]string {
+		rows := make([][]string, 0, len(counts))
+		for _, y := range counts {
+			rows = append(rows, []string{strconv.Itoa(y.Year), strconv.Itoa(y.Movies)})
+		}
+		return rows
+	})
+}
+
+func (h *Handlers) GetStatsTopCharacters(c echo.Context, params api.GetStatsTopCharactersParams) error {
+	top := h.Stats.Report(c.Request().Context()).TopCharacters
+	limit := defaultTopCharacters
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	top = top[:min(limit, len(top))]
+	result := api.TopCharacters{Characters: make([]api.CharacterCount, 0, len(top))}
+	for _, t := range top {
+		result.Characters = append(result.Characters, api.CharacterCount{ID: t.Character.ID, Name: t.Character.Name, Movies: t.Movies})
+	}
+	return writeStats(c, params.Format, result, []string{"id", "name", "movies"}, func() [][]string {
+		rows := make([][]string, 0, len(top))
+		for _, t := range top {
+			rows = append(rows, []string{t.Character.ID.String(), t.Character.Name, strconv.Itoa(t.Movies)})
+		}
+		return rows
+	})
+}
+
+func (h *Handlers) GetStatsEmptyMovies(c echo.Context, params api.GetStatsEmptyMoviesParams) error {
+	movies := h.Stats.Report(c.Request().Context()).EmptyMovies
+	return writeStats(c, params.Format, api.EmptyMovies{Movies: movieNodes(movies)}, []string{"id", "title", "year"}, func() [][]string {
+		rows := make([][]string, 0, len(movies))
+		for _, m := range movies {
+			rows = append(rows, []string{m.ID.String(), m.Title, strconv.Itoa(m.Year)})
+		}
+		return rows
+	})
+}
+
+func (h *Handlers) GetStatsOrphanCharacters(c echo.Context, params api.GetStatsOrphanCharactersParams) error {
+	characters := h.Stats.Report(c.Request().Context()).OrphanCharacters
+	return writeStats(c, params.Format, api.OrphanCharacters{Characters: characterNodes(characters)}, []string{"id", "name"}, func() [][]string {
+		rows := make([][]string, 0, len(characters))
+		for _, ch := range characters {
+			rows = append(rows, []string{ch.ID.String(), ch.Name})
+		}
+		return rows
+	})
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"example.com/go_basics/go/api"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handlers) GetTrash(c echo.Context) error {
+	trash := api.Trash{Movies: []api.TrashedMovie{}, Characters: []api.TrashedCharacter{}}
+	for _, d := range h.Repo.ListTrash(c.Request().Context()) {
+		if m := d.Movie; m != nil {
+			trash.Movies = append(trash.Movies, api.TrashedMovie{
+				ID:          m.ID,
+				Title:       m.Title,
+				Year:        m.Year,
+				Version:     m.Version,
+				DeletedAt:   *m.DeletedAt,
+				Appearances: len(d.Appearances),
+			})
+			continue
+		}
+		ch := d.Character
+		trash.Characters = append(trash.Characters, api.TrashedCharacter{
+			ID:          ch.ID,
+			Name:        ch.Name,
+			Movie:       &ch.Movie,
+			Version:     ch.Version,
+			DeletedAt:   *ch.DeletedAt,
+			Appearances: len(d.Appearances),
+		})
+	}
+	return c.JSON(http.StatusOK, trash)
+}
+
+func (h *Handlers) PostMoviesIdRestore(c echo.Context, id uuid.UUID) error {
+	movie, err := h.Repo.RestoreMovie(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set(headerETag, etag(movie.Version))
+	return c.JSON(http.StatusOK, movie)
+}
+
+func (h *Handlers) PostCharactersIdRestore(c echo.Context, id uuid.UUID) error {
+	character, err := h.Repo.RestoreCharacter(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set(headerETag, etag(character.Version))
+	return c.JSON(http.StatusOK, character)
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"net/http"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/events"
+	"example.com/go_basics/go/problem"
+	"example.com/go_basics/go/webhooks"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handlers) GetAdminWebhooks(c echo.Context) error {
+	if err := h.checkWebhooks(); err != nil {
+		return err
+	}
+	hooks := h.Webhooks.List()
+	out := make([]api.Webhook, len(hooks))
+	for i, w := range hooks {
+		out[i] = toAPIWebhook(w)
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+func (h *Handlers) PostAdminWebhooks(c echo.Context) error {
+	if err := h.checkWebhooks(); err != nil {
+		return err
+	}
+	var input api.NewWebhook
+	if err := c.Bind(&input); err != nil {
+		return problem.New(http.StatusBadRequest, "Invalid request body")
+	}
+	var types []events.Type
+	if input.Events != nil {
+		for _, t := range *input.Events {
+			types = append(types, events.Type(t))
+		}
+	}
+	w, secret, err := h.Webhooks.Create(input.Url, types)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, api.CreatedWebhook{Webhook: toAPIWebhook(w), Secret: secret})
+}
+
+func (h *Handlers) GetAdminWebhooksId(c echo.Context, id uuid.UUID) error {
+	if err := h.checkWebhooks(); err != nil {
+		return err
+	}
+	w, err := h.Webhooks.Get(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, toAPIWebhook(w))
+}
+
+func (h *Handlers) DeleteAdminWebhooksId(c echo.Context, id uuid.UUID) error {
+	if err := h.checkWebhooks(); err != nil {
+		return err
+	}
+	if err := h.Webhooks.Delete(id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handlers) GetAdminWebhooksIdDeliveries(c echo.Context, id uuid.UUID) error {
+	if err := h.checkWebhooks(); err != nil {
+		return err
+	}
+	deliveries, err := h.Webhooks.Deliveries(id)
+	if err != nil {
+		return err
+	}
+	out := make([]api.WebhookDelivery, len(deliveries))
+	for i, d := range deliveries {
+		out[i] = api.WebhookDelivery{
+			Id:          d.ID,
+			EventId:     int64(d.EventID),
+			EventType:   api.EventType(d.EventType),
+			Attempt:     d.Attempt,
+			DurationMs:  d.Duration.Milliseconds(),
+			DeliveredAt: d.DeliveredAt,
+		}
+		if d.StatusCode != 0 {
+			out[i].StatusCode = &d.StatusCode
+		}
+		if d.Error != "" {
+			out[i].Error = &d.Error
+		}
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+func (h *Handlers) GetAdminWebhooksIdDeadLetters(c echo.Context, id uuid.UUID) error {
+	if err := h.checkWebhooks(); err != nil {
+		return err
+	}
+	dead, err := h.Webhooks.DeadLetters(id)
+	if err != nil {
+		return err
+	}
+	out := make([]api.DeadLetter, len(dead))
+	for i, dl := range dead {
+		out[i] = api.DeadLetter{
+			DeliveryId: dl.DeliveryID,
+			Event: api.Event{
+				Id:   int64(dl.Event.ID),
+				Type: string(dl.Event.Type),
+				Time: dl.Event.Time,
+			},
+			Attempts:  dl.Attempts,
+			LastError: dl.LastError,
+			FailedAt:  dl.FailedAt,
+		}
+		if dl.Event.Data != nil {
+			out[i].Event.Data = &dl.Event.Data
+		}
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+func (h *Handlers) PostAdminWebhooksIdDeadLettersDeliveryIdRedeliver(c echo.Context, id uuid.UUID, deliveryId uuid.UUID) error {
+	if err := h.checkWebhooks(); err != nil {
+		return err
+	}
+	if err := h.Webhooks.Redeliver(id, deliveryId); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusAccepted)
+}
+
+func (h *Handlers) checkWebhooks() error {
+	if h.Webhooks == nil {
+		return problem.New(http.StatusNotFound, "Webhooks are disabled")
+	}
+	return nil
+}
+
+func toAPIWebhook(w webhooks.Webhook) api.Webhook {
+	types := make([]api.EventType, len(w.Events))
+	for i, t := range w.Events {
+		types[i] = api.EventType(t)
+	}
+	return api.Webhook{
+		Id:        w.ID,
+		Url:       w.URL,
+		Events:    types,
+		CreatedAt: w.CreatedAt,
+	}
+}
@@ -0,0 +1,114 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/swapi"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	LivePath  = "/healthz"
+	ReadyPath = "/readyz"
+)
+
+// checkTimeout bounds each readiness check so a slow SWAPI cannot stall the
+// probe.
+const checkTimeout = 2 * time.Second
+
+var (
+	errNotLoaded = errors.New("store is not loaded yet")
+	errDraining  = errors.New("server is shutting down")
+)
+
+type check struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// Health answers the liveness and readiness probes. The server is ready once
+// the store is loaded, until shutdown starts draining it.
+type Health struct {
+	checks   []check
+	loaded   atomic.Bool
+	draining atomic.Bool
+}
+
+// New checks the store and the CA, and SWAPI when the config asks for it.
+func New(cfg *config.Config, ca *pki.Authority, sw *swapi.Client) *Health {
+	h := &Health{}
+	h.checks = []check{
+		{"store", func(context.Context) error {
+			if !h.loaded.Load() {
+				return errNotLoaded
+			}
+			return nil
+		}},
+		{"ca", func(context.Context) error {
+			_, _, err := pki.LoadCertAndKey(ca.CAPath())
+			return err
+		}},
+	}
+	if cfg.Health.SWAPI {
+		h.checks = append(h.checks, check{"swapi", sw.Ping})
+	}
+	return h
+}
+
+// MarkLoaded reports that the store holds its initial data.
+func (h *Health) MarkLoaded() {
+	h.loaded.Store(true)
+}
+
+// Drain makes the readiness probe fail so load balancers stop sending
+// traffic while in-flight requests finish.
+func (h *Health) Drain() {
+	h.draining.Store(true)
+}
+
+// Status is the body of both probes. Checks maps every readiness check to
+// "ok" or the reason it failed.
+type Status struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Live reports that the process serves requests at all.
+func (h *Health) Live(c echo.Context) error {
+	return c.JSON(http.StatusOK, Status{Status: "ok"})
+}
+
+// Ready runs every check and answers 503 when one of them fails.
+func (h *Health) Ready(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), checkTimeout)
+	defer cancel()
+
+	status, code := Status{Status: "ok", Checks: map[string]string{}}, http.StatusOK
+	fail := func(name string, err error) {
+		status.Status, code = "unavailable", http.StatusServiceUnavailable
+		status.Checks[name] = err.Error()
+	}
+	if h.draining.Load() {
+		fail("server", errDraining)
+	}
+	for _, ch := range h.checks {
+		if err := ch.run(ctx); err != nil {
+			fail(ch.name, err)
+			continue
+		}
+		status.Checks[ch.name] = "ok"
+	}
+	return c.JSON(code, status)
+}
+
+// Register adds the probe routes to e.
+func (h *Health) Register(e *echo.Echo) {
+	e.GET(LivePath, h.Live)
+	e.GET(ReadyPath, h.Ready)
+}
@@ -0,0 +1,83 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/swapi"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func probe(t *testing.T, e *echo.Echo, path string) (int, Status) {
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var status Status
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	return rec.Code, status
+}
+
+func TestReadiness(t *testing.T) {
+	var swapiDown atomic.Bool
+	fakeSWAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if swapiDown.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	t.Cleanup(fakeSWAPI.Close)
+	cfg := config.Default()
+	cfg.SWAPI.BaseURL = fakeSWAPI.URL
+	cfg.Health.SWAPI = true
+
+	ca := &pki.Authority{Dir: t.TempDir()}
+	h := New(cfg, ca, swapi.New(cfg, nil))
+	e := echo.New()
+	h.Register(e)
+
+	code, status := probe(t, e, LivePath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", status.Status)
+
+	code, status = probe(t, e, ReadyPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unavailable", status.Status)
+	assert.Equal(t, errNotLoaded.Error(), status.Checks["store"])
+	assert.Contains(t, status.Checks["ca"], "no such file")
+	assert.Equal(t, "ok", status.Checks["swapi"])
+
+	cert, key, err := pki.Issue(pki.CATemplate(), nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(ca.CAPath(), pki.EncodeCert(cert), 0644))
+	require.NoError(t, os.WriteFile(pki.KeyPath(ca.CAPath()), pki.EncodeKey(key), 0600))
+	h.MarkLoaded()
+	code, status = probe(t, e, ReadyPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]string{"store": "ok", "ca": "ok", "swapi": "ok"}, status.Checks)
+
+	swapiDown.Store(true)
+	code, status = probe(t, e, ReadyPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, status.Checks["swapi"], "502")
+
+	swapiDown.Store(false)
+	h.Drain()
+	code, status = probe(t, e, ReadyPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, errDraining.Error(), status.Checks["server"])
+	code, _ = probe(t, e, LivePath)
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestSWAPICheckIsOptional(t *testing.T) {
+	h := New(config.Default(), &pki.Authority{Dir: t.TempDir()}, nil)
+	for _, c := range h.checks {
+		assert.NotEqual(t, "swapi", c.name)
+	}
+}
@@ -9,14 +9,19 @@ export let options = {
 export default function () {
   const payload = JSON.stringify({
     title: `Test Movie ${__VU}-${__ITER}`,
-    year: 2025
+    release_year: 2025
   });
 
-  const headers = { 'Content-Type': 'application/json' };
+  const headers = {
+    'Content-Type': 'application/json',
+    'X-API-Key': __ENV.API_KEY || 'dev-admin-key',
+  };
 
   let res = http.post('http://localhost:8080/movies', payload, { headers });
 
+  // A single client is throttled once its write bucket is empty.
   check(res, {
-    'status is 201': (r) => r.status === 201,
+    'status is 201 or 429': (r) => r.status === 201 || r.status === 429,
+    'rate limit headers': (r) => r.headers['Ratelimit-Limit'] !== undefined,
   });
 }
\ No newline at end of file
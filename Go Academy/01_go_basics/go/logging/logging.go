@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	"example.com/go_basics/go/config"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type contextKey struct{}
+
+var nop = zap.NewNop()
+
+// New builds the application logger from the log config.
+func New(lc fx.Lifecycle, cfg *config.Config) (*zap.Logger, error) {
+	level, err := zapcore.ParseLevel(cfg.Log.Level)
+	if err != nil {
+		return nil, err
+	}
+	zc := zap.NewProductionConfig()
+	if cfg.Log.Format == "console" {
+		zc = zap.NewDevelopmentConfig()
+	}
+	zc.Level = zap.NewAtomicLevelAt(level)
+	logger, err := zc.Build()
+	if err != nil {
+		return nil, err
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			// Sync fails on terminals and pipes, there is nothing to do about it.
+			_ = logger.Sync()
+			return nil
+		},
+	})
+	return logger, nil
+}
+
+// WithContext returns a copy of ctx carrying l.
+func WithContext(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx, or a no-op logger.
+func FromContext(ctx context.Context) *zap.Logger {
+	if l, ok := ctx.Value(contextKey{}).(*zap.Logger); ok {
+		return l
+	}
+	return nop
+}
+
+// Middleware tags every request with an ID, taken from X-Request-ID when the
+// client sends one, and stores a logger carrying it in the request context.
+// The logger also carries the trace ID of a span started before it.
+// Successful requests are logged at debug level only, like every request to
+// one of the quiet routes, such as probes that fail on purpose.
+func Middleware(l *zap.Logger, quiet ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			id := req.Header.Get(echo.HeaderXRequestID)
+			if id == "" {
+				id = uuid.NewString()
+			}
+			c.Response().Header().Set(echo.HeaderXRequestID, id)
+			logger := l.With(zap.String("request_id", id))
+			if sc := trace.SpanContextFromContext(req.Context()); sc.IsValid() {
+				logger = logger.With(zap.Stringer("trace_id", sc.TraceID()))
+			}
+			c.SetRequest(req.WithContext(WithContext(req.Context(), logger)))
+
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			level := zapcore.DebugLevel
+			switch {
+			case slices.Contains(quiet, c.Path()):
+			case status >= http.StatusInternalServerError:
+				level = zapcore.ErrorLevel
+			case status >= http.StatusBadRequest:
+				level = zapcore.InfoLevel
+			}
+			if ce := logger.Check(level, "request"); ce != nil {
+				ce.Write(
+					zap.String("method", req.Method),
+					zap.String("path", req.URL.Path),
+					zap.String("route", c.Path()),
+					zap.Int("status", status),
+					zap.Duration("duration", time.Since(start)),
+					zap.Error(err),
+				)
+			}
+			return nil
+		}
+	}
+}
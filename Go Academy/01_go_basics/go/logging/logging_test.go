@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMiddleware(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	e := echo.New()
+	e.Use(Middleware(zap.New(core), "/readyz"))
+	e.GET("/movies", func(c echo.Context) error {
+		FromContext(c.Request().Context()).Info("listing movies")
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/movies", nil)
+	req.Header.Set(echo.HeaderXRequestID, "req-42")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, "req-42", rec.Header().Get(echo.HeaderXRequestID))
+
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "listing movies", entries[0].Message)
+	assert.Equal(t, "req-42", entries[0].ContextMap()["request_id"])
+	assert.Equal(t, zapcore.DebugLevel, entries[1].Level)
+	assert.Equal(t, "/movies", entries[1].ContextMap()["route"])
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nowhere", nil))
+	assert.NotEmpty(t, rec.Header().Get(echo.HeaderXRequestID))
+	entries = logs.TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, zapcore.InfoLevel, entries[0].Level)
+	assert.EqualValues(t, http.StatusNotFound, entries[0].ContextMap()["status"])
+	assert.Equal(t, rec.Header().Get(echo.HeaderXRequestID), entries[0].ContextMap()["request_id"])
+
+	e.GET("/readyz", func(c echo.Context) error {
+		return c.NoContent(http.StatusServiceUnavailable)
+	})
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	entries = logs.TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, zapcore.DebugLevel, entries[0].Level)
+}
+
+func TestFromContextWithoutLogger(t *testing.T) {
+	assert.NotNil(t, FromContext(t.Context()))
+}
@@ -2,53 +2,170 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"net/http"
+	"os"
+	"time"
 
 	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/zap"
 
+	"example.com/go_basics/go/audit"
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/config"
 	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/events"
+	"example.com/go_basics/go/graphql"
+	"example.com/go_basics/go/grpcserver"
 	"example.com/go_basics/go/handlers"
+	"example.com/go_basics/go/health"
+	"example.com/go_basics/go/logging"
+	"example.com/go_basics/go/metrics"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/ratelimit"
 	"example.com/go_basics/go/repository"
 	"example.com/go_basics/go/routes"
+	"example.com/go_basics/go/signing"
+	"example.com/go_basics/go/swapi"
 	"example.com/go_basics/go/testdata"
+	"example.com/go_basics/go/tracing"
+	"example.com/go_basics/go/translog"
+	"example.com/go_basics/go/webhooks"
 
 	"github.com/labstack/echo/v4"
 )
 
 func main() {
+	cfg, err := config.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if cfg.PrintConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	app := fx.New(
+		fx.Supply(cfg),
+		fx.StopTimeout(cfg.Server.DrainDelay+cfg.Server.ShutdownTimeout+5*time.Second),
+		fx.WithLogger(func(l *zap.Logger) fxevent.Logger {
+			return &fxevent.ZapLogger{Logger: l.Named("fx")}
+		}),
 		fx.Provide(
+			logging.New,
 			db.New,
+			metrics.New,
+			events.New,
+			audit.New,
 			repository.New,
+			pki.New,
+			translog.New,
+			signing.New,
+			swapi.New,
+			auth.NewKeyStore,
+			auth.New,
+			webhooks.New,
+			graphql.New,
+			ratelimit.New,
 			handlers.New,
+			health.New,
 			routes.NewEchoRouter,
 		),
 		fx.Invoke(
+			tracing.Init,
 			StartEchoServer,
+			grpcserver.Start,
+			repository.StartPurge,
 			testdata.LoadTestData,
 		),
 	)
 	app.Run()
 }
 
-func StartEchoServer(lc fx.Lifecycle, e *echo.Echo) {
+func StartEchoServer(lc fx.Lifecycle, cfg *config.Config, e *echo.Echo, ca *pki.Authority, hc *health.Health, bus *events.Bus, logger *zap.Logger) {
+	e.HideBanner = true
+	e.HidePort = true
+	server := &http.Server{
+		Addr:    cfg.Server.Addr,
+		Handler: e,
+	}
+	servers := []*http.Server{server}
+	// Change feed streams never finish by themselves and Shutdown does not
+	// wait for hijacked WebSocket connections, closing the bus ends both.
+	server.RegisterOnShutdown(bus.Close)
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			server := &http.Server{
-				Addr:    ":8080",
-				Handler: e,
-			}
-			fmt.Println("Starting Echo server at :8080")
+			logger.Info("starting HTTP server", zap.String("addr", cfg.Server.Addr))
 			go func() {
 				if err := e.StartServer(server); err != nil && err != http.ErrServerClosed {
-					e.Logger.Error("Echo server stopped with error:", err)
+					logger.Error("HTTP server stopped", zap.Error(err))
 				}
 			}()
 			return nil
 		},
+	})
+
+	// An additional HTTPS listener requires client certificates issued by our CA.
+	if addr := cfg.Server.TLSAddr; addr != "" {
+		tlsServer := &http.Server{Addr: addr, Handler: e}
+		servers = append(servers, tlsServer)
+		tlsServer.RegisterOnShutdown(bus.Close)
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				tlsConfig, err := ca.ServerTLSConfig(cfg.Server.TLSHosts...)
+				if err != nil {
+					return fmt.Errorf("mutual TLS setup failed: %w", err)
+				}
+				tlsServer.TLSConfig = tlsConfig
+				logger.Info("starting mutual TLS server", zap.String("addr", addr))
+				go func() {
+					if err := tlsServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+						logger.Error("TLS server stopped", zap.Error(err))
+					}
+				}()
+				return nil
+			},
+		})
+	}
+
+	// Stop hooks run in reverse, so both listeners drain before the hooks
+	// registered earlier close the transparency log and flush the tracer.
+	lc.Append(fx.Hook{
 		OnStop: func(ctx context.Context) error {
-			return e.Shutdown(ctx)
+			return drain(ctx, cfg.Server, hc, logger, servers)
 		},
 	})
 }
+
+// drain fails the readiness probe, keeps serving for the drain delay and
+// then gives in-flight requests until the shutdown timeout to finish.
+// Connections still open after that are closed.
+func drain(ctx context.Context, cfg config.Server, hc *health.Health, logger *zap.Logger, servers []*http.Server) error {
+	hc.Drain()
+	logger.Info("draining", zap.Duration("delay", cfg.DrainDelay), zap.Duration("timeout", cfg.ShutdownTimeout))
+	select {
+	case <-time.After(cfg.DrainDelay):
+	case <-ctx.Done():
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.ShutdownTimeout)
+	defer cancel()
+	var errs []error
+	for _, s := range servers {
+		if err := s.Shutdown(ctx); err != nil {
+			logger.Warn("requests still running at the shutdown deadline", zap.String("addr", s.Addr), zap.Error(err))
+			errs = append(errs, s.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
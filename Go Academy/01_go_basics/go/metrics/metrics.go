@@ -0,0 +1,188 @@
+package metrics
+
+import (
+	"crypto/x509"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/pki"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Path is where the router serves the Prometheus text format.
+const Path = "/metrics"
+
+// Metrics owns the Prometheus registry of the API. A nil *Metrics records
+// nothing, so tests can leave it out.
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	requests      *prometheus.CounterVec
+	requestTime   *prometheus.HistogramVec
+	repository    *prometheus.HistogramVec
+	swapiRequests *prometheus.CounterVec
+	swapiTime     prometheus.Histogram
+	certsIssued   *prometheus.CounterVec
+	certsRevoked  *prometheus.CounterVec
+	rateLimited   *prometheus.CounterVec
+}
+
+// New registers the API metrics, gauges for the collection sizes of store
+// and a counter fed by every certificate ca issues.
+func New(store *db.MemoryDB, ca *pki.Authority) *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		requestTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method and route.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		repository: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "repository_operation_duration_seconds",
+			Help:    "Duration of repository operations.",
+			Buckets: []float64{.00001, .00005, .0001, .0005, .001, .005, .01, .05},
+		}, []string{"operation"}),
+		swapiRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "swapi_requests_total",
+			Help: "Star Wars API lookups by outcome: found, not_found or error.",
+		}, []string{"outcome"}),
+		swapiTime: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "swapi_request_duration_seconds",
+			Help:    "Star Wars API request latency.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		certsIssued: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "certificates_issued_total",
+			Help: "Certificates signed by the CA or a movie intermediate, by kind.",
+		}, []string{"kind"}),
+		certsRevoked: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "certificates_revoked_total",
+			Help: "Revoked certificates by kind.",
+		}, []string{"kind"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_rate_limited_total",
+			Help: "Requests rejected by the rate limiter, by limit class and client tier.",
+		}, []string{"class", "tier"}),
+	}
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.requestTime, m.repository,
+		m.swapiRequests, m.swapiTime,
+		m.certsIssued, m.certsRevoked,
+		m.rateLimited,
+		storeGauge("store_movies", "Movies in the store.", &store.Movies),
+		storeGauge("store_characters", "Characters in the store.", &store.Characters),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "store_appearances",
+			Help: "Character appearances in the store.",
+		}, func() float64 {
+			store.Mutex.Lock()
+			defer store.Mutex.Unlock()
+			return float64(len(store.Appearances))
+		}),
+	)
+	for _, kind := range []string{pki.UnitMovie, pki.UnitCharacter} {
+		m.certsIssued.WithLabelValues(strings.ToLower(kind))
+		m.certsRevoked.WithLabelValues(strings.ToLower(kind))
+	}
+	ca.OnIssue(func(cert *x509.Certificate) error {
+		m.certsIssued.WithLabelValues(certKind(cert)).Inc()
+		return nil
+	})
+	return m
+}
+
+func storeGauge(name, help string, entries *sync.Map) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, func() float64 {
+		n := 0
+		entries.Range(func(_, _ any) bool {
+			n++
+			return true
+		})
+		return float64(n)
+	})
+}
+
+// certKind labels movie and character certificates by their unit and the
+// server's own certificates by their purpose.
+func certKind(cert *x509.Certificate) string {
+	if len(cert.Subject.OrganizationalUnit) > 0 {
+		return strings.ToLower(cert.Subject.OrganizationalUnit[0])
+	}
+	if slices.Contains(cert.ExtKeyUsage, x509.ExtKeyUsageServerAuth) {
+		return "server"
+	}
+	return "signer"
+}
+
+// Middleware counts requests and their latency per route. Requests that
+// match no route share one label to bound the cardinality.
+func (m *Metrics) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+			method := c.Request().Method
+			m.requests.WithLabelValues(method, route, strconv.Itoa(c.Response().Status)).Inc()
+			m.requestTime.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+			return nil
+		}
+	}
+}
+
+// Handler serves the registry in the Prometheus text format.
+func (m *Metrics) Handler() echo.HandlerFunc {
+	return echo.WrapHandler(promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{}))
+}
+
+// ObserveRepository records how long a repository operation took since start.
+func (m *Metrics) ObserveRepository(operation string, start time.Time) {
+	if m == nil {
+		return
+	}
+	m.repository.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// ObserveSWAPI records the outcome and latency of a Star Wars API lookup.
+func (m *Metrics) ObserveSWAPI(outcome string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.swapiRequests.WithLabelValues(outcome).Inc()
+	m.swapiTime.Observe(d.Seconds())
+}
+
+// CertificateRevoked counts a revoked certificate of the given kind.
+func (m *Metrics) CertificateRevoked(kind string) {
+	if m == nil {
+		return
+	}
+	m.certsRevoked.WithLabelValues(kind).Inc()
+}
+
+// RateLimited counts a request the rate limiter rejected.
+func (m *Metrics) RateLimited(class, tier string) {
+	if m == nil {
+		return
+	}
+	m.rateLimited.WithLabelValues(class, tier).Inc()
+}
@@ -0,0 +1,98 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/handlers"
+	"example.com/go_basics/go/metrics"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/routes"
+	"example.com/go_basics/go/swapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, *pki.Authority) {
+	fakeSWAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("search") == "Luke Skywalker" {
+			io.WriteString(w, `{"count":1}`)
+			return
+		}
+		io.WriteString(w, `{"count":0}`)
+	}))
+	t.Cleanup(fakeSWAPI.Close)
+	cfg := config.Default()
+	cfg.SWAPI.BaseURL = fakeSWAPI.URL
+
+	ca := &pki.Authority{Dir: t.TempDir()}
+	cert, key, err := pki.Issue(pki.CATemplate(), nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(ca.CAPath(), pki.EncodeCert(cert), 0644))
+	require.NoError(t, os.WriteFile(pki.KeyPath(ca.CAPath()), pki.EncodeKey(key), 0600))
+
+	store := db.New()
+	m := metrics.New(store, ca)
+	repo := repository.New(store, m, nil, nil)
+	h := handlers.New(repo, ca, nil, swapi.New(cfg, m), nil, nil, nil)
+	server := httptest.NewServer(routes.NewEchoRouter(h, nil, zap.NewNop(), m, nil, nil, nil))
+	t.Cleanup(server.Close)
+	return server, ca
+}
+
+func post(t *testing.T, url, body string) int {
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestScrapeMetrics(t *testing.T) {
+	server, ca := newTestServer(t)
+	_, err := ca.ServerTLSConfig("localhost")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusCreated, post(t, server.URL+"/movies", `{"title":"Star Wars","release_year":1977}`))
+	assert.Equal(t, http.StatusCreated, post(t, server.URL+"/characters", `{"name":"Luke Skywalker","movie":"Star Wars"}`))
+	assert.Equal(t, http.StatusBadRequest, post(t, server.URL+"/characters", `{"name":"Shrek","movie":"Star Wars"}`))
+	resp, err := http.Get(server.URL + "/nowhere")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	resp, err = http.Get(server.URL + metrics.Path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	text := string(body)
+
+	for _, line := range []string{
+		`http_requests_total{method="POST",route="/movies",status="201"} 1`,
+		`http_requests_total{method="POST",route="/characters",status="400"} 1`,
+		`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`http_request_duration_seconds_count{method="POST",route="/characters"} 2`,
+		`repository_operation_duration_seconds_count{operation="CreateMovie"} 1`,
+		`repository_operation_duration_seconds_count{operation="CreateCharacter"} 1`,
+		`store_movies 1`,
+		`store_characters 1`,
+		`store_appearances 0`,
+		`swapi_requests_total{outcome="found"} 1`,
+		`swapi_requests_total{outcome="not_found"} 1`,
+		`swapi_request_duration_seconds_count 2`,
+		`certificates_issued_total{kind="server"} 1`,
+		`certificates_issued_total{kind="movie"} 0`,
+		`certificates_revoked_total{kind="character"} 0`,
+	} {
+		assert.Contains(t, text, line+"\n")
+	}
+}
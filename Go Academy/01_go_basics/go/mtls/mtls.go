@@ -0,0 +1,77 @@
+package mtls
+
+import (
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"slices"
+
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/problem"
+	"github.com/labstack/echo/v4"
+)
+
+const identityKey = "client_identity"
+
+// Identity is the verified subject of a client certificate. Cert is the
+// presented certificate. Movie and Issuer are set for character
+// certificates: the title of the movie that issued them and its certificate
+// from the verified chain.
+type Identity struct {
+	Kind   string
+	Name   string
+	Cert   *x509.Certificate
+	Movie  string
+	Issuer *x509.Certificate
+}
+
+func (i Identity) IsMovie() bool {
+	return i.Kind == pki.UnitMovie
+}
+
+func (i Identity) IsCharacter() bool {
+	return i.Kind == pki.UnitCharacter
+}
+
+// Middleware stores the identity of a verified TLS client in the echo context.
+// Plain HTTP requests pass through without an identity.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			state := c.Request().TLS
+			if state == nil || len(state.VerifiedChains) == 0 {
+				return next(c)
+			}
+			id, err := identityFromChain(state.VerifiedChains[0])
+			if err != nil {
+				return problem.New(http.StatusForbidden, err.Error())
+			}
+			c.Set(identityKey, id)
+			return next(c)
+		}
+	}
+}
+
+func FromContext(c echo.Context) (Identity, bool) {
+	id, ok := c.Get(identityKey).(Identity)
+	return id, ok
+}
+
+// identityFromChain expects movie certificates directly under the CA and
+// character certificates under a movie intermediate.
+func identityFromChain(chain []*x509.Certificate) (Identity, error) {
+	leaf := chain[0]
+	switch {
+	case slices.Contains(leaf.Subject.OrganizationalUnit, pki.UnitMovie) && len(chain) == 2:
+		return Identity{Kind: pki.UnitMovie, Name: leaf.Subject.CommonName, Cert: leaf}, nil
+	case slices.Contains(leaf.Subject.OrganizationalUnit, pki.UnitCharacter) && len(chain) == 3:
+		return Identity{
+			Kind:   pki.UnitCharacter,
+			Name:   leaf.Subject.CommonName,
+			Cert:   leaf,
+			Movie:  chain[1].Subject.CommonName,
+			Issuer: chain[1],
+		}, nil
+	}
+	return Identity{}, errors.New("client certificate is not a movie or character certificate")
+}
@@ -0,0 +1,187 @@
+package mtls_test
+
+import (
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/handlers"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/routes"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testPKI struct {
+	ca    *pki.Authority
+	roots *x509.CertPool
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	ca := &pki.Authority{Dir: t.TempDir()}
+	require.NoError(t, os.MkdirAll(ca.Dir+"/movies", 0755))
+	cert, key, err := pki.Issue(pki.CATemplate(), nil, nil)
+	require.NoError(t, err)
+	writeCertAndKey(t, ca.CAPath(), cert, key)
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	return &testPKI{ca: ca, roots: roots}
+}
+
+func writeCertAndKey(t *testing.T, path string, cert *x509.Certificate, key *rsa.PrivateKey) {
+	require.NoError(t, os.WriteFile(path, pki.EncodeCert(cert), 0644))
+	require.NoError(t, os.WriteFile(pki.KeyPath(path), pki.EncodeKey(key), 0600))
+}
+
+func (p *testPKI) movie(t *testing.T, title string) tls.Certificate {
+	caCert, caKey, err := pki.LoadCertAndKey(p.ca.CAPath())
+	require.NoError(t, err)
+	cert, key, err := pki.Issue(pki.MovieTemplate(title), caCert, caKey)
+	require.NoError(t, err)
+	writeCertAndKey(t, p.ca.MoviePath(title), cert, key)
+	return tls.Certificate{Certificate: [][]byte{cert.Raw}, PrivateKey: key}
+}
+
+func (p *testPKI) character(t *testing.T, name, movieTitle string) tls.Certificate {
+	movieCert, movieKey, err := pki.LoadCertAndKey(p.ca.MoviePath(movieTitle))
+	require.NoError(t, err)
+	cert, key, err := pki.Issue(pki.CharacterTemplate(name), movieCert, movieKey)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{cert.Raw, movieCert.Raw}, PrivateKey: key}
+}
+
+func (p *testPKI) client(certs ...tls.Certificate) *http.Client {
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      p.roots,
+		Certificates: certs,
+	}}}
+}
+
+func do(t *testing.T, client *http.Client, method, url, body string) (int, error) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", "*")
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func TestMutualTLSAuthorization(t *testing.T) {
+	p := newTestPKI(t)
+	shrekCert := p.movie(t, "Shrek")
+	p.movie(t, "The Lion King")
+	donkeyCert := p.character(t, "Donkey", "Shrek")
+
+	repo := repository.New(db.New(), nil, nil, nil)
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	lionKing, _ := repo.CreateMovie(t.Context(), "The Lion King", 1994)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	simba, _ := repo.CreateCharacter(t.Context(), "Simba")
+	repo.AddAppearance(t.Context(), shrek.ID, donkey.ID)
+	repo.AddAppearance(t.Context(), lionKing.ID, simba.ID)
+
+	server := httptest.NewUnstartedServer(routes.NewEchoRouter(handlers.New(repo, p.ca, nil, nil, nil, nil, nil), nil, zap.NewNop(), nil, nil, nil, nil))
+	tlsConfig, err := p.ca.ServerTLSConfig("127.0.0.1")
+	require.NoError(t, err)
+	server.TLS = tlsConfig
+	server.StartTLS()
+	defer server.Close()
+
+	movieClient := p.client(shrekCert)
+	status, err := do(t, movieClient, http.MethodPut, server.URL+"/characters?id="+donkey.ID.String(), `{"name":"Donkey the Brave"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+
+	status, err = do(t, movieClient, http.MethodDelete, server.URL+"/characters/"+simba.ID.String(), "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, err = do(t, movieClient, http.MethodDelete, server.URL+"/movies?id="+lionKing.ID.String(), "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, err = do(t, movieClient, http.MethodPost, server.URL+"/batch", `{"operations":[{"op":"update_movie","id":"`+lionKing.ID.String()+`","title":"Shrek","version":1}]}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	characterClient := p.client(donkeyCert)
+	status, err = do(t, characterClient, http.MethodPut, server.URL+"/characters?id="+simba.ID.String(), `{"name":"Scar"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, err = do(t, characterClient, http.MethodDelete, server.URL+"/movies?id="+shrek.ID.String(), "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, err = do(t, characterClient, http.MethodGet, server.URL+"/movies", "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+
+	// The movie certificate renamed Donkey above.
+	braveClient := p.client(p.character(t, "Donkey the Brave", "Shrek"))
+	status, err = do(t, braveClient, http.MethodPut, server.URL+"/characters?id="+donkey.ID.String(), `{"name":"Donkey the Brave"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+
+	// Simba does not appear in Shrek, so the Shrek certificate cannot vouch for him.
+	status, err = do(t, p.client(p.character(t, "Simba", "Shrek")), http.MethodPut, server.URL+"/characters?id="+simba.ID.String(), `{"name":"Scar"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	// A second Shrek intermediate under the CA is not the one kept for the movie.
+	caCert, caKey, err := pki.LoadCertAndKey(p.ca.CAPath())
+	require.NoError(t, err)
+	rogueCert, rogueKey, err := pki.Issue(pki.MovieTemplate("Shrek"), caCert, caKey)
+	require.NoError(t, err)
+	leaf, leafKey, err := pki.Issue(pki.CharacterTemplate("Donkey the Brave"), rogueCert, rogueKey)
+	require.NoError(t, err)
+	rogueClient := p.client(tls.Certificate{Certificate: [][]byte{leaf.Raw, rogueCert.Raw}, PrivateKey: leafKey})
+	status, err = do(t, rogueClient, http.MethodPut, server.URL+"/characters?id="+donkey.ID.String(), `{"name":"Donkey the Brave"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	// Nor does that intermediate manage Shrek itself.
+	rogueMovieClient := p.client(tls.Certificate{Certificate: [][]byte{rogueCert.Raw}, PrivateKey: rogueKey})
+	status, err = do(t, rogueMovieClient, http.MethodDelete, server.URL+"/movies?id="+shrek.ID.String(), "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, status)
+	status, err = do(t, rogueMovieClient, http.MethodPut, server.URL+"/characters?id="+donkey.ID.String(), `{"name":"Donkey the Brave"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	// Unknown entities are not managed by any certificate.
+	status, err = do(t, movieClient, http.MethodPut, server.URL+"/characters?id="+uuid.NewString(), `{"name":"Ghost"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, status)
+}
+
+func TestMutualTLSRejectsUnknownClients(t *testing.T) {
+	p := newTestPKI(t)
+	server := httptest.NewUnstartedServer(routes.NewEchoRouter(handlers.New(repository.New(db.New(), nil, nil, nil), p.ca, nil, nil, nil, nil, nil), nil, zap.NewNop(), nil, nil, nil, nil))
+	tlsConfig, err := p.ca.ServerTLSConfig("127.0.0.1")
+	require.NoError(t, err)
+	server.TLS = tlsConfig
+	server.StartTLS()
+	defer server.Close()
+
+	_, err = do(t, p.client(), http.MethodGet, server.URL+"/movies", "")
+	assert.Error(t, err)
+
+	cert, key, err := pki.Issue(pki.MovieTemplate("Shrek"), nil, nil)
+	require.NoError(t, err)
+	_, err = do(t, p.client(tls.Certificate{Certificate: [][]byte{cert.Raw}, PrivateKey: key}), http.MethodGet, server.URL+"/movies", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,362 @@
+package pki
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"example.com/go_basics/go/config"
+)
+
+// Organizational units tell movie and character subjects apart, since a
+// character may share its name with the movie it appears in. The response
+// signer has a unit of its own so no other certificate passes for it.
+const (
+	UnitMovie     = "Movie"
+	UnitCharacter = "Character"
+	UnitSigner    = "Signer"
+)
+
+var (
+	ErrInvalidCSR      = errors.New("invalid certificate signing request")
+	ErrSubjectMismatch = errors.New("CSR subject does not match entity")
+	ErrIssuerMissing   = errors.New("issuer certificate not available")
+	ErrInvalidName     = errors.New("name cannot be used as a certificate file name")
+	ErrIssuerExists    = errors.New("movie certificate already issued")
+)
+
+// Authority signs certificates with the CA and movie certificates kept in Dir.
+type Authority struct {
+	Dir     string
+	onIssue []func(*x509.Certificate) error
+}
+
+func New(cfg *config.Config) *Authority {
+	return &Authority{Dir: cfg.Certs.Dir}
+}
+
+func (a *Authority) CAPath() string {
+	return filepath.Join(a.Dir, "ca.pem")
+}
+
+// checkName rejects names that would leave the movies or characters
+// directory once joined into a path, such as "../ca".
+func checkName(name string) error {
+	if name == "" || strings.ContainsAny(name, "/\\\x00") || strings.Contains(name, "..") {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	return nil
+}
+
+// MoviePath returns where the certificate of a movie is kept. Callers check
+// the title with checkName first.
+func (a *Authority) MoviePath(title string) string {
+	return filepath.Join(a.Dir, "movies", title+".pem")
+}
+
+func (a *Authority) CharacterPath(name string) string {
+	return filepath.Join(a.Dir, "characters", name+".pem")
+}
+
+// OnIssue registers fn to be called with every certificate the authority
+// signs. An error from fn fails the issuance.
+func (a *Authority) OnIssue(fn func(*x509.Certificate) error) {
+	a.onIssue = append(a.onIssue, fn)
+}
+
+func (a *Authority) issued(cert *x509.Certificate, err error) (*x509.Certificate, error) {
+	if err != nil {
+		return nil, err
+	}
+	for _, fn := range a.onIssue {
+		if err := fn(cert); err != nil {
+			return nil, fmt.Errorf("recording issued certificate: %w", err)
+		}
+	}
+	return cert, nil
+}
+
+// HasMovieIssuer reports whether a movie certificate and key exist for title.
+func (a *Authority) HasMovieIssuer(title string) bool {
+	if checkName(title) != nil {
+		return false
+	}
+	_, _, err := LoadCertAndKey(a.MoviePath(title))
+	return err == nil
+}
+
+// MovieCert returns the movie certificate kept for title, the one that signs
+// the characters of the movie.
+func (a *Authority) MovieCert(title string) (*x509.Certificate, error) {
+	if err := checkName(title); err != nil {
+		return nil, err
+	}
+	cert, err := LoadCert(a.MoviePath(title))
+	if err != nil {
+		return nil, fmt.Errorf("%w: movie %q: %v", ErrIssuerMissing, title, err)
+	}
+	return cert, nil
+}
+
+// SignMovieCSR issues a movie certificate signed by the CA and keeps it as
+// the certificate of the movie. A movie has one certificate, so a title that
+// already has one is refused.
+func (a *Authority) SignMovieCSR(csr *x509.CertificateRequest, title string) (*x509.Certificate, error) {
+	if csr.Subject.CommonName != title {
+		return nil, fmt.Errorf("%w: got %q, want %q", ErrSubjectMismatch, csr.Subject.CommonName, title)
+	}
+	if err := checkName(title); err != nil {
+		return nil, err
+	}
+	caCert, caKey, err := LoadCertAndKey(a.CAPath())
+	if err != nil {
+		return nil, fmt.Errorf("%w: CA: %v", ErrIssuerMissing, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(a.MoviePath(title)), 0755); err != nil {
+		return nil, err
+	}
+	// O_EXCL claims the title, so concurrent requests cannot both get one.
+	f, err := os.OpenFile(a.MoviePath(title), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, os.ErrExist) {
+		return nil, fmt.Errorf("%w: movie %q", ErrIssuerExists, title)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cert, err := a.issued(Sign(MovieTemplate(title), caCert, csr.PublicKey, caKey))
+	if err == nil {
+		_, err = f.Write(EncodeCert(cert))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return cert, nil
+}
+
+// SignCharacterCSR issues a character certificate signed by the movie intermediate.
+func (a *Authority) SignCharacterCSR(csr *x509.CertificateRequest, name, movieTitle string) (*x509.Certificate, error) {
+	if csr.Subject.CommonName != name {
+		return nil, fmt.Errorf("%w: got %q, want %q", ErrSubjectMismatch, csr.Subject.CommonName, name)
+	}
+	if err := checkName(movieTitle); err != nil {
+		return nil, err
+	}
+	movieCert, movieKey, err := LoadCertAndKey(a.MoviePath(movieTitle))
+	if err != nil {
+		return nil, fmt.Errorf("%w: movie %q: %v", ErrIssuerMissing, movieTitle, err)
+	}
+	return a.issued(Sign(CharacterTemplate(name), movieCert, csr.PublicKey, movieKey))
+}
+
+// ServerTLSConfig issues a fresh server certificate from the CA for hosts and
+// requires client certificates that chain to the same CA.
+func (a *Authority) ServerTLSConfig(hosts ...string) (*tls.Config, error) {
+	caCert, caKey, err := LoadCertAndKey(a.CAPath())
+	if err != nil {
+		return nil, fmt.Errorf("%w: CA: %v", ErrIssuerMissing, err)
+	}
+	cert, key, err := Issue(ServerTemplate(hosts...), caCert, caKey)
+	if cert, err = a.issued(cert, err); err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	return &tls.Config{
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{cert.Raw, caCert.Raw},
+			PrivateKey:  key,
+			Leaf:        cert,
+		}},
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  pool,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
+// IssueSigner creates a key pair whose certificate is signed by the CA, for
+// signing API responses. It returns the chain from the signer up to the CA.
+func (a *Authority) IssueSigner() ([]*x509.Certificate, *rsa.PrivateKey, error) {
+	caCert, caKey, err := LoadCertAndKey(a.CAPath())
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: CA: %v", ErrIssuerMissing, err)
+	}
+	cert, key, err := Issue(SignerTemplate(), caCert, caKey)
+	if cert, err = a.issued(cert, err); err != nil {
+		return nil, nil, err
+	}
+	return []*x509.Certificate{cert, caCert}, key, nil
+}
+
+// ParseCSR decodes a PEM encoded PKCS#10 request and checks its signature.
+func ParseCSR(data []byte) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, fmt.Errorf("%w: PEM decode failed", ErrInvalidCSR)
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSR, err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSR, err)
+	}
+	return csr, nil
+}
+
+func CATemplate() *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "My CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+}
+
+// MovieTemplate describes a movie certificate. Movies act as intermediates
+// for their characters, so the certificate may sign leaf certificates only,
+// and only client ones: its usage nests into every certificate below it.
+func MovieTemplate(title string) *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: title, OrganizationalUnit: []string{UnitMovie}},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().AddDate(5, 0, 0),
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+}
+
+func CharacterTemplate(name string) *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name, OrganizationalUnit: []string{UnitCharacter}},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().AddDate(2, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+}
+
+// ServerTemplate describes a TLS server certificate valid for the given
+// DNS names or IP addresses.
+func ServerTemplate(hosts ...string) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "Movie Character API"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+	return template
+}
+
+// SignerTemplate describes the certificate of the response signer. Its unit
+// and code signing usage set it apart from movie and character certificates.
+func SignerTemplate() *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "Movie Character API Signer", OrganizationalUnit: []string{UnitSigner}},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+}
+
+// Issue generates a new key pair and signs its certificate with parent and
+// parentKey. A nil parent self-signs the template.
+func Issue(template, parent *x509.Certificate, parentKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	cert, err := Sign(template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// Sign creates a certificate from template for pub, signed by parent and key.
+func Sign(template, parent *x509.Certificate, pub any, key *rsa.PrivateKey) (*x509.Certificate, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, key)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+func EncodeCert(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+func EncodeKey(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+// KeyPath returns the path of the private key stored next to a certificate.
+func KeyPath(certPath string) string {
+	return certPath[:len(certPath)-len(filepath.Ext(certPath))] + ".key"
+}
+
+func LoadCert(path string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("invalid cert")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func LoadCertAndKey(path string) (*x509.Certificate, *rsa.PrivateKey, error) {
+	cert, err := LoadCert(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyData, err := os.ReadFile(KeyPath(path))
+	if err != nil {
+		return nil, nil, err
+	}
+	keyBlock, _ := pem.Decode(keyData)
+	if keyBlock == nil || keyBlock.Type != "RSA PRIVATE KEY" {
+		return nil, nil, errors.New("invalid key")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
@@ -0,0 +1,108 @@
+package pki
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeIssuer(t *testing.T, path string, template, parent *x509.Certificate, parentKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey) {
+	cert, key, err := Issue(template, parent, parentKey)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, EncodeCert(cert), 0644))
+	require.NoError(t, os.WriteFile(KeyPath(path), EncodeKey(key), 0600))
+	return cert, key
+}
+
+func newCSR(t *testing.T, cn string) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: cn}}, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+}
+
+func TestSignCSRChain(t *testing.T) {
+	a := &Authority{Dir: t.TempDir()}
+	require.NoError(t, os.MkdirAll(a.Dir+"/movies", 0755))
+	caCert, caKey := writeIssuer(t, a.CAPath(), CATemplate(), nil, nil)
+	writeIssuer(t, a.MoviePath("Shrek"), MovieTemplate("Shrek"), caCert, caKey)
+
+	csr, err := ParseCSR(newCSR(t, "Shrek 2"))
+	require.NoError(t, err)
+	movie, err := a.SignMovieCSR(csr, "Shrek 2")
+	require.NoError(t, err)
+	assert.Equal(t, "My CA", movie.Issuer.CommonName)
+	kept, err := a.MovieCert("Shrek 2")
+	require.NoError(t, err)
+	assert.Equal(t, movie.Raw, kept.Raw)
+
+	// A movie has one certificate, issued or provisioned.
+	for _, title := range []string{"Shrek 2", "Shrek"} {
+		csr, err := ParseCSR(newCSR(t, title))
+		require.NoError(t, err)
+		_, err = a.SignMovieCSR(csr, title)
+		assert.ErrorIs(t, err, ErrIssuerExists)
+	}
+
+	csr, err = ParseCSR(newCSR(t, "Donkey"))
+	require.NoError(t, err)
+	character, err := a.SignCharacterCSR(csr, "Donkey", "Shrek")
+	require.NoError(t, err)
+	assert.Equal(t, "Shrek", character.Issuer.CommonName)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	intermediates := x509.NewCertPool()
+	intermediate, err := LoadCert(a.MoviePath("Shrek"))
+	require.NoError(t, err)
+	intermediates.AddCert(intermediate)
+	_, err = character.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	assert.NoError(t, err)
+}
+
+func TestSignCSRErrors(t *testing.T) {
+	a := &Authority{Dir: t.TempDir()}
+	writeIssuer(t, a.CAPath(), CATemplate(), nil, nil)
+
+	_, err := ParseCSR([]byte("not a csr"))
+	assert.ErrorIs(t, err, ErrInvalidCSR)
+
+	csr, err := ParseCSR(newCSR(t, "Fiona"))
+	require.NoError(t, err)
+	_, err = a.SignMovieCSR(csr, "Shrek")
+	assert.ErrorIs(t, err, ErrSubjectMismatch)
+
+	_, err = a.SignCharacterCSR(csr, "Fiona", "Shrek")
+	assert.ErrorIs(t, err, ErrIssuerMissing)
+	assert.False(t, a.HasMovieIssuer("Shrek"))
+}
+
+func TestSignCSRRejectsPathNames(t *testing.T) {
+	a := &Authority{Dir: t.TempDir()}
+	require.NoError(t, os.MkdirAll(a.Dir+"/movies", 0755))
+	writeIssuer(t, a.CAPath(), CATemplate(), nil, nil)
+
+	// A movie titled "../ca" must not pick the CA as its intermediate.
+	assert.False(t, a.HasMovieIssuer("../ca"))
+	csr, err := ParseCSR(newCSR(t, "Donkey"))
+	require.NoError(t, err)
+	_, err = a.SignCharacterCSR(csr, "Donkey", "../ca")
+	assert.ErrorIs(t, err, ErrInvalidName)
+
+	csr, err = ParseCSR(newCSR(t, `..\ca`))
+	require.NoError(t, err)
+	_, err = a.SignMovieCSR(csr, `..\ca`)
+	assert.ErrorIs(t, err, ErrInvalidName)
+}
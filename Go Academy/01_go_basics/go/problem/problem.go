@@ -0,0 +1,93 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const ContentType = "application/problem+json"
+
+// Type URIs for problems that carry more than the HTTP status.
+const (
+	TypeDefault    = "about:blank"
+	TypeValidation = "/problems/validation"
+	TypeRateLimit  = "/problems/rate-limit"
+)
+
+// Problem is an RFC 7807 problem details body. It implements error so
+// handlers can return it and let the central error handler write it.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes why a single request field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+func New(status int, detail string) *Problem {
+	return &Problem{
+		Type:   TypeDefault,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+func Newf(status int, format string, args ...any) *Problem {
+	return New(status, fmt.Sprintf(format, args...))
+}
+
+// Validation reports every rejected field of a request.
+func Validation(errs []FieldError) *Problem {
+	return &Problem{
+		Type:   TypeValidation,
+		Title:  "Validation failed",
+		Status: http.StatusBadRequest,
+		Detail: "One or more fields are invalid",
+		Errors: errs,
+	}
+}
+
+// Write sends p as application/problem+json.
+func Write(c echo.Context, p *Problem) error {
+	if p.Instance == "" {
+		p.Instance = c.Request().URL.Path
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return c.Blob(p.Status, ContentType, body)
+}
+
+// From converts problems and errors raised by echo into problems.
+// Other errors are left to the caller.
+func From(err error) (*Problem, bool) {
+	var p *Problem
+	if errors.As(err, &p) {
+		return p, true
+	}
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		return New(he.Code, fmt.Sprint(he.Message)), true
+	}
+	return nil, false
+}
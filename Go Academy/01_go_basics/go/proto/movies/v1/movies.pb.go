@@ -0,0 +1,1620 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: movies/v1/movies.proto
+
+package moviesv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EventType int32
+
+const (
+	EventType_EVENT_TYPE_UNSPECIFIED         EventType = 0
+	EventType_EVENT_TYPE_MOVIE_CREATED       EventType = 1
+	EventType_EVENT_TYPE_MOVIE_UPDATED       EventType = 2
+	EventType_EVENT_TYPE_MOVIE_DELETED       EventType = 3
+	EventType_EVENT_TYPE_CHARACTER_CREATED   EventType = 4
+	EventType_EVENT_TYPE_CHARACTER_UPDATED   EventType = 5
+	EventType_EVENT_TYPE_CHARACTER_DELETED   EventType = 6
+	EventType_EVENT_TYPE_APPEARANCE_LINKED   EventType = 7
+	EventType_EVENT_TYPE_APPEARANCE_UNLINKED EventType = 8
+	// EVENT_TYPE_RESET means the events after after_id are no longer
+	// buffered, or after_id is from before a server restart. Clients should
+	// reload what they cache.
+	EventType_EVENT_TYPE_RESET              EventType = 9
+	EventType_EVENT_TYPE_MOVIE_RESTORED     EventType = 10
+	EventType_EVENT_TYPE_CHARACTER_RESTORED EventType = 11
+	EventType_EVENT_TYPE_FRANCHISE_CREATED  EventType = 12
+	EventType_EVENT_TYPE_FRANCHISE_UPDATED  EventType = 13
+	EventType_EVENT_TYPE_FRANCHISE_DELETED  EventType = 14
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0:  "EVENT_TYPE_UNSPECIFIED",
+		1:  "EVENT_TYPE_MOVIE_CREATED",
+		2:  "EVENT_TYPE_MOVIE_UPDATED",
+		3:  "EVENT_TYPE_MOVIE_DELETED",
+		4:  "EVENT_TYPE_CHARACTER_CREATED",
+		5:  "EVENT_TYPE_CHARACTER_UPDATED",
+		6:  "EVENT_TYPE_CHARACTER_DELETED",
+		7:  "EVENT_TYPE_APPEARANCE_LINKED",
+		8:  "EVENT_TYPE_APPEARANCE_UNLINKED",
+		9:  "EVENT_TYPE_RESET",
+		10: "EVENT_TYPE_MOVIE_RESTORED",
+		11: "EVENT_TYPE_CHARACTER_RESTORED",
+		12: "EVENT_TYPE_FRANCHISE_CREATED",
+		13: "EVENT_TYPE_FRANCHISE_UPDATED",
+		14: "EVENT_TYPE_FRANCHISE_DELETED",
+	}
+	EventType_value = map[string]int32{
+		"EVENT_TYPE_UNSPECIFIED":         0,
+		"EVENT_TYPE_MOVIE_CREATED":       1,
+		"EVENT_TYPE_MOVIE_UPDATED":       2,
+		"EVENT_TYPE_MOVIE_DELETED":       3,
+		"EVENT_TYPE_CHARACTER_CREATED":   4,
+		"EVENT_TYPE_CHARACTER_UPDATED":   5,
+		"EVENT_TYPE_CHARACTER_DELETED":   6,
+		"EVENT_TYPE_APPEARANCE_LINKED":   7,
+		"EVENT_TYPE_APPEARANCE_UNLINKED": 8,
+		"EVENT_TYPE_RESET":               9,
+		"EVENT_TYPE_MOVIE_RESTORED":      10,
+		"EVENT_TYPE_CHARACTER_RESTORED":  11,
+		"EVENT_TYPE_FRANCHISE_CREATED":   12,
+		"EVENT_TYPE_FRANCHISE_UPDATED":   13,
+		"EVENT_TYPE_FRANCHISE_DELETED":   14,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_movies_v1_movies_proto_enumTypes[0].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_movies_v1_movies_proto_enumTypes[0]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{0}
+}
+
+type Movie struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title       string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	ReleaseYear int32                  `protobuf:"varint,3,opt,name=release_year,json=releaseYear,proto3" json:"release_year,omitempty"`
+	// version starts at 1 and grows with every change.
+	Version       int64 `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Movie) Reset() {
+	*x = Movie{}
+	mi := &file_movies_v1_movies_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Movie) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Movie) ProtoMessage() {}
+
+func (x *Movie) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Movie.ProtoReflect.Descriptor instead.
+func (*Movie) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Movie) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Movie) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Movie) GetReleaseYear() int32 {
+	if x != nil {
+		return x.ReleaseYear
+	}
+	return 0
+}
+
+func (x *Movie) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type Character struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Version       int64                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Character) Reset() {
+	*x = Character{}
+	mi := &file_movies_v1_movies_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Character) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Character) ProtoMessage() {}
+
+func (x *Character) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Character.ProtoReflect.Descriptor instead.
+func (*Character) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Character) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Character) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Character) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type Appearance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MovieId       string                 `protobuf:"bytes,1,opt,name=movie_id,json=movieId,proto3" json:"movie_id,omitempty"`
+	CharacterId   string                 `protobuf:"bytes,2,opt,name=character_id,json=characterId,proto3" json:"character_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Appearance) Reset() {
+	*x = Appearance{}
+	mi := &file_movies_v1_movies_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Appearance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Appearance) ProtoMessage() {}
+
+func (x *Appearance) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Appearance.ProtoReflect.Descriptor instead.
+func (*Appearance) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Appearance) GetMovieId() string {
+	if x != nil {
+		return x.MovieId
+	}
+	return ""
+}
+
+func (x *Appearance) GetCharacterId() string {
+	if x != nil {
+		return x.CharacterId
+	}
+	return ""
+}
+
+// Franchise is only sent in events; it is managed over REST.
+type Franchise struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name         string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Installments []*Installment         `protobuf:"bytes,3,rep,name=installments,proto3" json:"installments,omitempty"`
+	// character_ids are linked to the franchise as a whole.
+	CharacterIds  []string `protobuf:"bytes,4,rep,name=character_ids,json=characterIds,proto3" json:"character_ids,omitempty"`
+	Version       int64    `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Franchise) Reset() {
+	*x = Franchise{}
+	mi := &file_movies_v1_movies_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Franchise) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Franchise) ProtoMessage() {}
+
+func (x *Franchise) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Franchise.ProtoReflect.Descriptor instead.
+func (*Franchise) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Franchise) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Franchise) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Franchise) GetInstallments() []*Installment {
+	if x != nil {
+		return x.Installments
+	}
+	return nil
+}
+
+func (x *Franchise) GetCharacterIds() []string {
+	if x != nil {
+		return x.CharacterIds
+	}
+	return nil
+}
+
+func (x *Franchise) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type Installment struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	MovieId string                 `protobuf:"bytes,1,opt,name=movie_id,json=movieId,proto3" json:"movie_id,omitempty"`
+	// kind is original, sequel, prequel or spin-off.
+	Kind          string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Installment) Reset() {
+	*x = Installment{}
+	mi := &file_movies_v1_movies_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Installment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Installment) ProtoMessage() {}
+
+func (x *Installment) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Installment.ProtoReflect.Descriptor instead.
+func (*Installment) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Installment) GetMovieId() string {
+	if x != nil {
+		return x.MovieId
+	}
+	return ""
+}
+
+func (x *Installment) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+type CreateMovieRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	ReleaseYear   int32                  `protobuf:"varint,2,opt,name=release_year,json=releaseYear,proto3" json:"release_year,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateMovieRequest) Reset() {
+	*x = CreateMovieRequest{}
+	mi := &file_movies_v1_movies_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateMovieRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateMovieRequest) ProtoMessage() {}
+
+func (x *CreateMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateMovieRequest.ProtoReflect.Descriptor instead.
+func (*CreateMovieRequest) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateMovieRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateMovieRequest) GetReleaseYear() int32 {
+	if x != nil {
+		return x.ReleaseYear
+	}
+	return 0
+}
+
+type GetMovieRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMovieRequest) Reset() {
+	*x = GetMovieRequest{}
+	mi := &file_movies_v1_movies_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMovieRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMovieRequest) ProtoMessage() {}
+
+func (x *GetMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMovieRequest.ProtoReflect.Descriptor instead.
+func (*GetMovieRequest) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetMovieRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// UpdateMovieRequest changes the fields that are set.
+type UpdateMovieRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title       *string                `protobuf:"bytes,2,opt,name=title,proto3,oneof" json:"title,omitempty"`
+	ReleaseYear *int32                 `protobuf:"varint,3,opt,name=release_year,json=releaseYear,proto3,oneof" json:"release_year,omitempty"`
+	// version the change is based on and is required, 0 skips the check.
+	Version       *int64 `protobuf:"varint,4,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMovieRequest) Reset() {
+	*x = UpdateMovieRequest{}
+	mi := &file_movies_v1_movies_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMovieRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMovieRequest) ProtoMessage() {}
+
+func (x *UpdateMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMovieRequest.ProtoReflect.Descriptor instead.
+func (*UpdateMovieRequest) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateMovieRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateMovieRequest) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *UpdateMovieRequest) GetReleaseYear() int32 {
+	if x != nil && x.ReleaseYear != nil {
+		return *x.ReleaseYear
+	}
+	return 0
+}
+
+func (x *UpdateMovieRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type DeleteMovieRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// version the change is based on and is required, 0 skips the check.
+	Version       *int64 `protobuf:"varint,2,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMovieRequest) Reset() {
+	*x = DeleteMovieRequest{}
+	mi := &file_movies_v1_movies_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMovieRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMovieRequest) ProtoMessage() {}
+
+func (x *DeleteMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMovieRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMovieRequest) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteMovieRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteMovieRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type DeleteMovieResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMovieResponse) Reset() {
+	*x = DeleteMovieResponse{}
+	mi := &file_movies_v1_movies_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMovieResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMovieResponse) ProtoMessage() {}
+
+func (x *DeleteMovieResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMovieResponse.ProtoReflect.Descriptor instead.
+func (*DeleteMovieResponse) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{9}
+}
+
+type ListMoviesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMoviesRequest) Reset() {
+	*x = ListMoviesRequest{}
+	mi := &file_movies_v1_movies_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMoviesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMoviesRequest) ProtoMessage() {}
+
+func (x *ListMoviesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMoviesRequest.ProtoReflect.Descriptor instead.
+func (*ListMoviesRequest) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{10}
+}
+
+type CreateCharacterRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// movie "Star Wars" checks that SWAPI knows the character.
+	Movie         string `protobuf:"bytes,2,opt,name=movie,proto3" json:"movie,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCharacterRequest) Reset() {
+	*x = CreateCharacterRequest{}
+	mi := &file_movies_v1_movies_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCharacterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCharacterRequest) ProtoMessage() {}
+
+func (x *CreateCharacterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCharacterRequest.ProtoReflect.Descriptor instead.
+func (*CreateCharacterRequest) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateCharacterRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateCharacterRequest) GetMovie() string {
+	if x != nil {
+		return x.Movie
+	}
+	return ""
+}
+
+type GetCharacterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCharacterRequest) Reset() {
+	*x = GetCharacterRequest{}
+	mi := &file_movies_v1_movies_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCharacterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCharacterRequest) ProtoMessage() {}
+
+func (x *GetCharacterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCharacterRequest.ProtoReflect.Descriptor instead.
+func (*GetCharacterRequest) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetCharacterRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UpdateCharacterRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// version the change is based on and is required, 0 skips the check.
+	Version       *int64 `protobuf:"varint,3,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCharacterRequest) Reset() {
+	*x = UpdateCharacterRequest{}
+	mi := &file_movies_v1_movies_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCharacterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCharacterRequest) ProtoMessage() {}
+
+func (x *UpdateCharacterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCharacterRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCharacterRequest) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateCharacterRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateCharacterRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateCharacterRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type DeleteCharacterRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// version the change is based on and is required, 0 skips the check.
+	Version       *int64 `protobuf:"varint,2,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCharacterRequest) Reset() {
+	*x = DeleteCharacterRequest{}
+	mi := &file_movies_v1_movies_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCharacterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCharacterRequest) ProtoMessage() {}
+
+func (x *DeleteCharacterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCharacterRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCharacterRequest) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DeleteCharacterRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteCharacterRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type DeleteCharacterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCharacterResponse) Reset() {
+	*x = DeleteCharacterResponse{}
+	mi := &file_movies_v1_movies_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCharacterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCharacterResponse) ProtoMessage() {}
+
+func (x *DeleteCharacterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCharacterResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCharacterResponse) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{15}
+}
+
+type ListCharactersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCharactersRequest) Reset() {
+	*x = ListCharactersRequest{}
+	mi := &file_movies_v1_movies_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCharactersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCharactersRequest) ProtoMessage() {}
+
+func (x *ListCharactersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCharactersRequest.ProtoReflect.Descriptor instead.
+func (*ListCharactersRequest) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{16}
+}
+
+type LinkAppearanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MovieId       string                 `protobuf:"bytes,1,opt,name=movie_id,json=movieId,proto3" json:"movie_id,omitempty"`
+	CharacterId   string                 `protobuf:"bytes,2,opt,name=character_id,json=characterId,proto3" json:"character_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkAppearanceRequest) Reset() {
+	*x = LinkAppearanceRequest{}
+	mi := &file_movies_v1_movies_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkAppearanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkAppearanceRequest) ProtoMessage() {}
+
+func (x *LinkAppearanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkAppearanceRequest.ProtoReflect.Descriptor instead.
+func (*LinkAppearanceRequest) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *LinkAppearanceRequest) GetMovieId() string {
+	if x != nil {
+		return x.MovieId
+	}
+	return ""
+}
+
+func (x *LinkAppearanceRequest) GetCharacterId() string {
+	if x != nil {
+		return x.CharacterId
+	}
+	return ""
+}
+
+type UnlinkAppearanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MovieId       string                 `protobuf:"bytes,1,opt,name=movie_id,json=movieId,proto3" json:"movie_id,omitempty"`
+	CharacterId   string                 `protobuf:"bytes,2,opt,name=character_id,json=characterId,proto3" json:"character_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlinkAppearanceRequest) Reset() {
+	*x = UnlinkAppearanceRequest{}
+	mi := &file_movies_v1_movies_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlinkAppearanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlinkAppearanceRequest) ProtoMessage() {}
+
+func (x *UnlinkAppearanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlinkAppearanceRequest.ProtoReflect.Descriptor instead.
+func (*UnlinkAppearanceRequest) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UnlinkAppearanceRequest) GetMovieId() string {
+	if x != nil {
+		return x.MovieId
+	}
+	return ""
+}
+
+func (x *UnlinkAppearanceRequest) GetCharacterId() string {
+	if x != nil {
+		return x.CharacterId
+	}
+	return ""
+}
+
+type ListMovieCharactersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MovieId       string                 `protobuf:"bytes,1,opt,name=movie_id,json=movieId,proto3" json:"movie_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMovieCharactersRequest) Reset() {
+	*x = ListMovieCharactersRequest{}
+	mi := &file_movies_v1_movies_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMovieCharactersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMovieCharactersRequest) ProtoMessage() {}
+
+func (x *ListMovieCharactersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMovieCharactersRequest.ProtoReflect.Descriptor instead.
+func (*ListMovieCharactersRequest) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListMovieCharactersRequest) GetMovieId() string {
+	if x != nil {
+		return x.MovieId
+	}
+	return ""
+}
+
+type ListCharacterMoviesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CharacterId   string                 `protobuf:"bytes,1,opt,name=character_id,json=characterId,proto3" json:"character_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCharacterMoviesRequest) Reset() {
+	*x = ListCharacterMoviesRequest{}
+	mi := &file_movies_v1_movies_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCharacterMoviesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCharacterMoviesRequest) ProtoMessage() {}
+
+func (x *ListCharacterMoviesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCharacterMoviesRequest.ProtoReflect.Descriptor instead.
+func (*ListCharacterMoviesRequest) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListCharacterMoviesRequest) GetCharacterId() string {
+	if x != nil {
+		return x.CharacterId
+	}
+	return ""
+}
+
+type WatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// after_id resumes after the last event a client saw, 0 starts with the
+	// next change.
+	AfterId       uint64 `protobuf:"varint,1,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_movies_v1_movies_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *WatchRequest) GetAfterId() uint64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type  EventType              `protobuf:"varint,2,opt,name=type,proto3,enum=movies.v1.EventType" json:"type,omitempty"`
+	Time  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	// data is the resource after the change, or before a delete.
+	//
+	// Types that are valid to be assigned to Data:
+	//
+	//	*Event_Movie
+	//	*Event_Character
+	//	*Event_Appearance
+	//	*Event_Franchise
+	Data          isEvent_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_movies_v1_movies_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_movies_v1_movies_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_movies_v1_movies_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Event) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Event) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *Event) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Event) GetData() isEvent_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Event) GetMovie() *Movie {
+	if x != nil {
+		if x, ok := x.Data.(*Event_Movie); ok {
+			return x.Movie
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetCharacter() *Character {
+	if x != nil {
+		if x, ok := x.Data.(*Event_Character); ok {
+			return x.Character
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetAppearance() *Appearance {
+	if x != nil {
+		if x, ok := x.Data.(*Event_Appearance); ok {
+			return x.Appearance
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetFranchise() *Franchise {
+	if x != nil {
+		if x, ok := x.Data.(*Event_Franchise); ok {
+			return x.Franchise
+		}
+	}
+	return nil
+}
+
+type isEvent_Data interface {
+	isEvent_Data()
+}
+
+type Event_Movie struct {
+	Movie *Movie `protobuf:"bytes,4,opt,name=movie,proto3,oneof"`
+}
+
+type Event_Character struct {
+	Character *Character `protobuf:"bytes,5,opt,name=character,proto3,oneof"`
+}
+
+type Event_Appearance struct {
+	Appearance *Appearance `protobuf:"bytes,6,opt,name=appearance,proto3,oneof"`
+}
+
+type Event_Franchise struct {
+	Franchise *Franchise `protobuf:"bytes,7,opt,name=franchise,proto3,oneof"`
+}
+
+func (*Event_Movie) isEvent_Data() {}
+
+func (*Event_Character) isEvent_Data() {}
+
+func (*Event_Appearance) isEvent_Data() {}
+
+func (*Event_Franchise) isEvent_Data() {}
+
+var File_movies_v1_movies_proto protoreflect.FileDescriptor
+
+const file_movies_v1_movies_proto_rawDesc = "" +
+	"\n" +
+	"\x16movies/v1/movies.proto\x12\tmovies.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"j\n" +
+	"\x05Movie\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12!\n" +
+	"\frelease_year\x18\x03 \x01(\x05R\vreleaseYear\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x03R\aversion\"I\n" +
+	"\tCharacter\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion\"J\n" +
+	"\n" +
+	"Appearance\x12\x19\n" +
+	"\bmovie_id\x18\x01 \x01(\tR\amovieId\x12!\n" +
+	"\fcharacter_id\x18\x02 \x01(\tR\vcharacterId\"\xaa\x01\n" +
+	"\tFranchise\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12:\n" +
+	"\finstallments\x18\x03 \x03(\v2\x16.movies.v1.InstallmentR\finstallments\x12#\n" +
+	"\rcharacter_ids\x18\x04 \x03(\tR\fcharacterIds\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\x03R\aversion\"<\n" +
+	"\vInstallment\x12\x19\n" +
+	"\bmovie_id\x18\x01 \x01(\tR\amovieId\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\"M\n" +
+	"\x12CreateMovieRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12!\n" +
+	"\frelease_year\x18\x02 \x01(\x05R\vreleaseYear\"!\n" +
+	"\x0fGetMovieRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xad\x01\n" +
+	"\x12UpdateMovieRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\x05title\x18\x02 \x01(\tH\x00R\x05title\x88\x01\x01\x12&\n" +
+	"\frelease_year\x18\x03 \x01(\x05H\x01R\vreleaseYear\x88\x01\x01\x12\x1d\n" +
+	"\aversion\x18\x04 \x01(\x03H\x02R\aversion\x88\x01\x01B\b\n" +
+	"\x06_titleB\x0f\n" +
+	"\r_release_yearB\n" +
+	"\n" +
+	"\b_version\"O\n" +
+	"\x12DeleteMovieRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\aversion\x18\x02 \x01(\x03H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"\x15\n" +
+	"\x13DeleteMovieResponse\"\x13\n" +
+	"\x11ListMoviesRequest\"B\n" +
+	"\x16CreateCharacterRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05movie\x18\x02 \x01(\tR\x05movie\"%\n" +
+	"\x13GetCharacterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"g\n" +
+	"\x16UpdateCharacterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\aversion\x18\x03 \x01(\x03H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"S\n" +
+	"\x16DeleteCharacterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\aversion\x18\x02 \x01(\x03H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"\x19\n" +
+	"\x17DeleteCharacterResponse\"\x17\n" +
+	"\x15ListCharactersRequest\"U\n" +
+	"\x15LinkAppearanceRequest\x12\x19\n" +
+	"\bmovie_id\x18\x01 \x01(\tR\amovieId\x12!\n" +
+	"\fcharacter_id\x18\x02 \x01(\tR\vcharacterId\"W\n" +
+	"\x17UnlinkAppearanceRequest\x12\x19\n" +
+	"\bmovie_id\x18\x01 \x01(\tR\amovieId\x12!\n" +
+	"\fcharacter_id\x18\x02 \x01(\tR\vcharacterId\"7\n" +
+	"\x1aListMovieCharactersRequest\x12\x19\n" +
+	"\bmovie_id\x18\x01 \x01(\tR\amovieId\"?\n" +
+	"\x1aListCharacterMoviesRequest\x12!\n" +
+	"\fcharacter_id\x18\x01 \x01(\tR\vcharacterId\")\n" +
+	"\fWatchRequest\x12\x19\n" +
+	"\bafter_id\x18\x01 \x01(\x04R\aafterId\"\xc8\x02\n" +
+	"\x05Event\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12(\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x14.movies.v1.EventTypeR\x04type\x12.\n" +
+	"\x04time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12(\n" +
+	"\x05movie\x18\x04 \x01(\v2\x10.movies.v1.MovieH\x00R\x05movie\x124\n" +
+	"\tcharacter\x18\x05 \x01(\v2\x14.movies.v1.CharacterH\x00R\tcharacter\x127\n" +
+	"\n" +
+	"appearance\x18\x06 \x01(\v2\x15.movies.v1.AppearanceH\x00R\n" +
+	"appearance\x124\n" +
+	"\tfranchise\x18\a \x01(\v2\x14.movies.v1.FranchiseH\x00R\tfranchiseB\x06\n" +
+	"\x04data*\xeb\x03\n" +
+	"\tEventType\x12\x1a\n" +
+	"\x16EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18EVENT_TYPE_MOVIE_CREATED\x10\x01\x12\x1c\n" +
+	"\x18EVENT_TYPE_MOVIE_UPDATED\x10\x02\x12\x1c\n" +
+	"\x18EVENT_TYPE_MOVIE_DELETED\x10\x03\x12 \n" +
+	"\x1cEVENT_TYPE_CHARACTER_CREATED\x10\x04\x12 \n" +
+	"\x1cEVENT_TYPE_CHARACTER_UPDATED\x10\x05\x12 \n" +
+	"\x1cEVENT_TYPE_CHARACTER_DELETED\x10\x06\x12 \n" +
+	"\x1cEVENT_TYPE_APPEARANCE_LINKED\x10\a\x12\"\n" +
+	"\x1eEVENT_TYPE_APPEARANCE_UNLINKED\x10\b\x12\x14\n" +
+	"\x10EVENT_TYPE_RESET\x10\t\x12\x1d\n" +
+	"\x19EVENT_TYPE_MOVIE_RESTORED\x10\n" +
+	"\x12!\n" +
+	"\x1dEVENT_TYPE_CHARACTER_RESTORED\x10\v\x12 \n" +
+	"\x1cEVENT_TYPE_FRANCHISE_CREATED\x10\f\x12 \n" +
+	"\x1cEVENT_TYPE_FRANCHISE_UPDATED\x10\r\x12 \n" +
+	"\x1cEVENT_TYPE_FRANCHISE_DELETED\x10\x0e2\xd4\b\n" +
+	"\x0eCatalogService\x12>\n" +
+	"\vCreateMovie\x12\x1d.movies.v1.CreateMovieRequest\x1a\x10.movies.v1.Movie\x128\n" +
+	"\bGetMovie\x12\x1a.movies.v1.GetMovieRequest\x1a\x10.movies.v1.Movie\x12>\n" +
+	"\vUpdateMovie\x12\x1d.movies.v1.UpdateMovieRequest\x1a\x10.movies.v1.Movie\x12L\n" +
+	"\vDeleteMovie\x12\x1d.movies.v1.DeleteMovieRequest\x1a\x1e.movies.v1.DeleteMovieResponse\x12>\n" +
+	"\n" +
+	"ListMovies\x12\x1c.movies.v1.ListMoviesRequest\x1a\x10.movies.v1.Movie0\x01\x12J\n" +
+	"\x0fCreateCharacter\x12!.movies.v1.CreateCharacterRequest\x1a\x14.movies.v1.Character\x12D\n" +
+	"\fGetCharacter\x12\x1e.movies.v1.GetCharacterRequest\x1a\x14.movies.v1.Character\x12J\n" +
+	"\x0fUpdateCharacter\x12!.movies.v1.UpdateCharacterRequest\x1a\x14.movies.v1.Character\x12X\n" +
+	"\x0fDeleteCharacter\x12!.movies.v1.DeleteCharacterRequest\x1a\".movies.v1.DeleteCharacterResponse\x12J\n" +
+	"\x0eListCharacters\x12 .movies.v1.ListCharactersRequest\x1a\x14.movies.v1.Character0\x01\x12I\n" +
+	"\x0eLinkAppearance\x12 .movies.v1.LinkAppearanceRequest\x1a\x15.movies.v1.Appearance\x12M\n" +
+	"\x10UnlinkAppearance\x12\".movies.v1.UnlinkAppearanceRequest\x1a\x15.movies.v1.Appearance\x12T\n" +
+	"\x13ListMovieCharacters\x12%.movies.v1.ListMovieCharactersRequest\x1a\x14.movies.v1.Character0\x01\x12P\n" +
+	"\x13ListCharacterMovies\x12%.movies.v1.ListCharacterMoviesRequest\x1a\x10.movies.v1.Movie0\x01\x124\n" +
+	"\x05Watch\x12\x17.movies.v1.WatchRequest\x1a\x10.movies.v1.Event0\x01B3Z1example.com/go_basics/go/proto/movies/v1;moviesv1b\x06proto3"
+
+var (
+	file_movies_v1_movies_proto_rawDescOnce sync.Once
+	file_movies_v1_movies_proto_rawDescData []byte
+)
+
+func file_movies_v1_movies_proto_rawDescGZIP() []byte {
+	file_movies_v1_movies_proto_rawDescOnce.Do(func() {
+		file_movies_v1_movies_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_movies_v1_movies_proto_rawDesc), len(file_movies_v1_movies_proto_rawDesc)))
+	})
+	return file_movies_v1_movies_proto_rawDescData
+}
+
+var file_movies_v1_movies_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_movies_v1_movies_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_movies_v1_movies_proto_goTypes = []any{
+	(EventType)(0),                     // 0: movies.v1.EventType
+	(*Movie)(nil),                      // 1: movies.v1.Movie
+	(*Character)(nil),                  // 2: movies.v1.Character
+	(*Appearance)(nil),                 // 3: movies.v1.Appearance
+	(*Franchise)(nil),                  // 4: movies.v1.Franchise
+	(*Installment)(nil),                // 5: movies.v1.Installment
+	(*CreateMovieRequest)(nil),         // 6: movies.v1.CreateMovieRequest
+	(*GetMovieRequest)(nil),            // 7: movies.v1.GetMovieRequest
+	(*UpdateMovieRequest)(nil),         // 8: movies.v1.UpdateMovieRequest
+	(*DeleteMovieRequest)(nil),         // 9: movies.v1.DeleteMovieRequest
+	(*DeleteMovieResponse)(nil),        // 10: movies.v1.DeleteMovieResponse
+	(*ListMoviesRequest)(nil),          // 11: movies.v1.ListMoviesRequest
+	(*CreateCharacterRequest)(nil),     // 12: movies.v1.CreateCharacterRequest
+	(*GetCharacterRequest)(nil),        // 13: movies.v1.GetCharacterRequest
+	(*UpdateCharacterRequest)(nil),     // 14: movies.v1.UpdateCharacterRequest
+	(*DeleteCharacterRequest)(nil),     // 15: movies.v1.DeleteCharacterRequest
+	(*DeleteCharacterResponse)(nil),    // 16: movies.v1.DeleteCharacterResponse
+	(*ListCharactersRequest)(nil),      // 17: movies.v1.ListCharactersRequest
+	(*LinkAppearanceRequest)(nil),      // 18: movies.v1.LinkAppearanceRequest
+	(*UnlinkAppearanceRequest)(nil),    // 19: movies.v1.UnlinkAppearanceRequest
+	(*ListMovieCharactersRequest)(nil), // 20: movies.v1.ListMovieCharactersRequest
+	(*ListCharacterMoviesRequest)(nil), // 21: movies.v1.ListCharacterMoviesRequest
+	(*WatchRequest)(nil),               // 22: movies.v1.WatchRequest
+	(*Event)(nil),                      // 23: movies.v1.Event
+	(*timestamppb.Timestamp)(nil),      // 24: google.protobuf.Timestamp
+}
+var file_movies_v1_movies_proto_depIdxs = []int32{
+	5,  // 0: movies.v1.Franchise.installments:type_name -> movies.v1.Installment
+	0,  // 1: movies.v1.Event.type:type_name -> movies.v1.EventType
+	24, // 2: movies.v1.Event.time:type_name -> google.protobuf.Timestamp
+	1,  // 3: movies.v1.Event.movie:type_name -> movies.v1.Movie
+	2,  // 4: movies.v1.Event.character:type_name -> movies.v1.Character
+	3,  // 5: movies.v1.Event.appearance:type_name -> movies.v1.Appearance
+	4,  // 6: movies.v1.Event.franchise:type_name -> movies.v1.Franchise
+	6,  // 7: movies.v1.CatalogService.CreateMovie:input_type -> movies.v1.CreateMovieRequest
+	7,  // 8: movies.v1.CatalogService.GetMovie:input_type -> movies.v1.GetMovieRequest
+	8,  // 9: movies.v1.CatalogService.UpdateMovie:input_type -> movies.v1.UpdateMovieRequest
+	9,  // 10: movies.v1.CatalogService.DeleteMovie:input_type -> movies.v1.DeleteMovieRequest
+	11, // 11: movies.v1.CatalogService.ListMovies:input_type -> movies.v1.ListMoviesRequest
+	12, // 12: movies.v1.CatalogService.CreateCharacter:input_type -> movies.v1.CreateCharacterRequest
+	13, // 13: movies.v1.CatalogService.GetCharacter:input_type -> movies.v1.GetCharacterRequest
+	14, // 14: movies.v1.CatalogService.UpdateCharacter:input_type -> movies.v1.UpdateCharacterRequest
+	15, // 15: movies.v1.CatalogService.DeleteCharacter:input_type -> movies.v1.DeleteCharacterRequest
+	17, // 16: movies.v1.CatalogService.ListCharacters:input_type -> movies.v1.ListCharactersRequest
+	18, // 17: movies.v1.CatalogService.LinkAppearance:input_type -> movies.v1.LinkAppearanceRequest
+	19, // 18: movies.v1.CatalogService.UnlinkAppearance:input_type -> movies.v1.UnlinkAppearanceRequest
+	20, // 19: movies.v1.CatalogService.ListMovieCharacters:input_type -> movies.v1.ListMovieCharactersRequest
+	21, // 20: movies.v1.CatalogService.ListCharacterMovies:input_type -> movies.v1.ListCharacterMoviesRequest
+	22, // 21: movies.v1.CatalogService.Watch:input_type -> movies.v1.WatchRequest
+	1,  // 22: movies.v1.CatalogService.CreateMovie:output_type -> movies.v1.Movie
+	1,  // 23: movies.v1.CatalogService.GetMovie:output_type -> movies.v1.Movie
+	1,  // 24: movies.v1.CatalogService.UpdateMovie:output_type -> movies.v1.Movie
+	10, // 25: movies.v1.CatalogService.DeleteMovie:output_type -> movies.v1.DeleteMovieResponse
+	1,  // 26: movies.v1.CatalogService.ListMovies:output_type -> movies.v1.Movie
+	2,  // 27: movies.v1.CatalogService.CreateCharacter:output_type -> movies.v1.Character
+	2,  // 28: movies.v1.CatalogService.GetCharacter:output_type -> movies.v1.Character
+	2,  // 29: movies.v1.CatalogService.UpdateCharacter:output_type -> movies.v1.Character
+	16, // 30: movies.v1.CatalogService.DeleteCharacter:output_type -> movies.v1.DeleteCharacterResponse
+	2,  // 31: movies.v1.CatalogService.ListCharacters:output_type -> movies.v1.Character
+	3,  // 32: movies.v1.CatalogService.LinkAppearance:output_type -> movies.v1.Appearance
+	3,  // 33: movies.v1.CatalogService.UnlinkAppearance:output_type -> movies.v1.Appearance
+	2,  // 34: movies.v1.CatalogService.ListMovieCharacters:output_type -> movies.v1.Character
+	1,  // 35: movies.v1.CatalogService.ListCharacterMovies:output_type -> movies.v1.Movie
+	23, // 36: movies.v1.CatalogService.Watch:output_type -> movies.v1.Event
+	22, // [22:37] is the sub-list for method output_type
+	7,  // [7:22] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_movies_v1_movies_proto_init() }
+func file_movies_v1_movies_proto_init() {
+	if File_movies_v1_movies_proto != nil {
+		return
+	}
+	file_movies_v1_movies_proto_msgTypes[7].OneofWrappers = []any{}
+	file_movies_v1_movies_proto_msgTypes[8].OneofWrappers = []any{}
+	file_movies_v1_movies_proto_msgTypes[13].OneofWrappers = []any{}
+	file_movies_v1_movies_proto_msgTypes[14].OneofWrappers = []any{}
+	file_movies_v1_movies_proto_msgTypes[22].OneofWrappers = []any{
+		(*Event_Movie)(nil),
+		(*Event_Character)(nil),
+		(*Event_Appearance)(nil),
+		(*Event_Franchise)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_movies_v1_movies_proto_rawDesc), len(file_movies_v1_movies_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   23,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_movies_v1_movies_proto_goTypes,
+		DependencyIndexes: file_movies_v1_movies_proto_depIdxs,
+		EnumInfos:         file_movies_v1_movies_proto_enumTypes,
+		MessageInfos:      file_movies_v1_movies_proto_msgTypes,
+	}.Build()
+	File_movies_v1_movies_proto = out.File
+	file_movies_v1_movies_proto_goTypes = nil
+	file_movies_v1_movies_proto_depIdxs = nil
+}
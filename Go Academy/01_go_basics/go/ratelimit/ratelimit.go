@@ -0,0 +1,255 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/metrics"
+	"example.com/go_basics/go/problem"
+	"example.com/go_basics/go/validation"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+)
+
+// ClassExtension puts an operation of the API spec into a limit class other
+// than the one of its method, e.g. x-rate-limit: read for a POST that only
+// reads. Handlers charge the SWAPI class with Charge where they call SWAPI.
+const ClassExtension = "x-rate-limit"
+
+// Limit classes.
+const (
+	Read  = "read"
+	Write = "write"
+	SWAPI = "swapi"
+)
+
+// Response headers following the IETF RateLimit header fields draft.
+const (
+	HeaderLimit     = "RateLimit-Limit"
+	HeaderRemaining = "RateLimit-Remaining"
+	HeaderReset     = "RateLimit-Reset"
+	HeaderPolicy    = "RateLimit-Policy"
+)
+
+const anonymous = "anonymous"
+
+// sweepInterval is how often buckets that refilled completely are dropped.
+// A full bucket behaves like a new one, so forgetting it changes nothing.
+const sweepInterval = time.Minute
+
+type bucketKey struct {
+	client string
+	// limit is the route for routes with their own limit, else the class.
+	limit string
+}
+
+// Limiter keeps a token bucket per client and limit class.
+type Limiter struct {
+	cfg     config.RateLimit
+	metrics *metrics.Metrics
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*rate.Limiter
+	lastSweep time.Time
+}
+
+// New returns nil when rate limiting is disabled.
+func New(cfg *config.Config, m *metrics.Metrics) *Limiter {
+	if !cfg.RateLimit.Enabled {
+		return nil
+	}
+	return &Limiter{
+		cfg:       cfg.RateLimit,
+		metrics:   m,
+		buckets:   make(map[bucketKey]*rate.Limiter),
+		lastSweep: time.Now(),
+	}
+}
+
+// Middleware takes a token from the caller's bucket for every request and
+// rejects the request with a 429 problem when the bucket is empty. Clients
+// are told their quota in RateLimit headers. Requests to the skip routes
+// are not limited.
+func (l *Limiter) Middleware(spec *openapi3.T, skip ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if slices.Contains(skip, c.Path()) {
+				return next(c)
+			}
+			tier, client := l.client(c)
+			factor := l.factor(tier)
+			if factor == 0 {
+				return next(c)
+			}
+			c.Set(chargeKey, func(class string) error {
+				key, limit := l.classLimit(class)
+				return l.take(c, tier, client, factor, key, limit, class)
+			})
+			key, limit, class := l.limit(spec, c)
+			if err := l.take(c, tier, client, factor, key, limit, class); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
+
+const chargeKey = "rate_limit_charge"
+
+// Charge takes a token of class from the caller's buckets on top of the one
+// the request took, for handlers whose work costs more than their route's
+// class, such as GraphQL mutations. Without a limiter it always succeeds.
+func Charge(c echo.Context, class string) error {
+	if charge, ok := c.Get(chargeKey).(func(string) error); ok {
+		return charge(class)
+	}
+	return nil
+}
+
+// Take draws a token of class for a call that does not come through the
+// middleware, such as a gRPC call, from the same buckets HTTP requests use.
+// The caller is the principal in ctx, else host. When the bucket is empty it
+// returns false and how long until a token is back. A nil limiter allows
+// every call.
+func (l *Limiter) Take(ctx context.Context, host, class string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+	tier, client := anonymous, "ip:"+host
+	if p, ok := auth.PrincipalFrom(ctx); ok {
+		tier, client = string(p.Role), p.Subject
+	}
+	factor := l.factor(tier)
+	if factor == 0 {
+		return true, 0
+	}
+	key, limit := l.classLimit(class)
+	limit, tokens, allowed := l.draw(tier, client, factor, key, limit, class)
+	if allowed {
+		return true, 0
+	}
+	return false, time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+}
+
+// factor scales the limits of a tier, 0 lifts them.
+func (l *Limiter) factor(tier string) float64 {
+	if factor, ok := l.cfg.Tiers[tier]; ok {
+		return factor
+	}
+	return 1
+}
+
+// draw takes a token from the client's bucket of limit scaled by the tier
+// factor. It returns the scaled limit and the tokens left.
+func (l *Limiter) draw(tier, client string, factor float64, key string, limit config.Limit, class string) (config.Limit, float64, bool) {
+	limit.Rate *= factor
+	limit.Burst = max(1, int(float64(limit.Burst)*factor))
+
+	now := time.Now()
+	b := l.bucket(bucketKey{client: client, limit: key}, limit, now)
+	allowed := b.AllowN(now, 1)
+	if !allowed {
+		l.metrics.RateLimited(class, tier)
+	}
+	return limit, b.TokensAt(now), allowed
+}
+
+// take draws a token from the client's bucket of limit scaled by the tier
+// factor, setting the RateLimit headers to what is left.
+func (l *Limiter) take(c echo.Context, tier, client string, factor float64, key string, limit config.Limit, class string) error {
+	limit, tokens, allowed := l.draw(tier, client, factor, key, limit, class)
+
+	h := c.Response().Header()
+	h.Set(HeaderLimit, strconv.Itoa(limit.Burst))
+	h.Set(HeaderRemaining, strconv.Itoa(max(0, int(tokens))))
+	h.Set(HeaderReset, seconds((float64(limit.Burst)-tokens)/limit.Rate))
+	h.Set(HeaderPolicy, strconv.Itoa(limit.Burst)+";w="+seconds(float64(limit.Burst)/limit.Rate))
+	if !allowed {
+		retry := seconds((1 - tokens) / limit.Rate)
+		h.Set(echo.HeaderRetryAfter, retry)
+		p := problem.Newf(http.StatusTooManyRequests, "Rate limit of %s requests exceeded, retry in %ss", class, retry)
+		p.Type = problem.TypeRateLimit
+		return p
+	}
+	return nil
+}
+
+// client identifies the caller by the principal auth found, or by IP for
+// anonymous requests.
+func (l *Limiter) client(c echo.Context) (tier, client string) {
+	if p, ok := auth.FromContext(c); ok {
+		return string(p.Role), p.Subject
+	}
+	if l.cfg.TrustProxy {
+		return anonymous, "ip:" + c.RealIP()
+	}
+	host, _, err := net.SplitHostPort(c.Request().RemoteAddr)
+	if err != nil {
+		host = c.Request().RemoteAddr
+	}
+	return anonymous, "ip:" + host
+}
+
+// limit picks the route's own limit or that of its class.
+func (l *Limiter) limit(spec *openapi3.T, c echo.Context) (key string, limit config.Limit, class string) {
+	method := c.Request().Method
+	class = Write
+	if method == http.MethodGet || method == http.MethodHead {
+		class = Read
+	}
+	if op := validation.Operation(spec, c); op != nil {
+		if v, ok := op.Extensions[ClassExtension].(string); ok {
+			class = v
+		}
+	}
+	route := method + " " + c.Path()
+	if limit, ok := l.cfg.Routes[route]; ok {
+		return route, limit, class
+	}
+	key, limit = l.classLimit(class)
+	return key, limit, class
+}
+
+func (l *Limiter) classLimit(class string) (key string, limit config.Limit) {
+	switch class {
+	case Read:
+		return class, l.cfg.Read
+	case SWAPI:
+		return class, l.cfg.SWAPI
+	default:
+		return class, l.cfg.Write
+	}
+}
+
+func (l *Limiter) bucket(key bucketKey, limit config.Limit, now time.Time) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastSweep) > sweepInterval {
+		for k, b := range l.buckets {
+			if b.TokensAt(now) >= float64(b.Burst()) {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)
+		l.buckets[key] = b
+	}
+	return b
+}
+
+// seconds rounds up to whole seconds, as the RateLimit headers expect.
+func seconds(s float64) string {
+	return strconv.Itoa(int(math.Ceil(max(0, s))))
+}
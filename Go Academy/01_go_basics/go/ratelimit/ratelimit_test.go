@@ -0,0 +1,190 @@
+package ratelimit_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/go_basics/go/auth"
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/graphql"
+	"example.com/go_basics/go/handlers"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/problem"
+	"example.com/go_basics/go/ratelimit"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/routes"
+	"example.com/go_basics/go/swapi"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const adminKey = "mca_test-admin-key"
+
+// slow limits refill so slowly that no token comes back during a test.
+func slow(burst int) config.Limit {
+	return config.Limit{Rate: 0.001, Burst: burst}
+}
+
+func newRouter(t *testing.T, configure func(*config.Config)) http.Handler {
+	fakeSWAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"count":1}`)
+	}))
+	t.Cleanup(fakeSWAPI.Close)
+	cfg := config.Default()
+	cfg.SWAPI.BaseURL = fakeSWAPI.URL
+	cfg.Auth.AdminKey = adminKey
+	cfg.RateLimit.Read = slow(2)
+	cfg.RateLimit.Write = slow(1)
+	cfg.RateLimit.SWAPI = slow(1)
+	configure(cfg)
+	keys := auth.NewKeyStore(cfg)
+	repo := repository.New(db.New(), nil, nil, nil)
+	sw := swapi.New(cfg, nil)
+	gq, err := graphql.New(cfg, repo, sw)
+	require.NoError(t, err)
+	h := handlers.New(repo, &pki.Authority{Dir: t.TempDir()}, nil, sw, keys, nil, gq)
+	return routes.NewEchoRouter(h, nil, zap.NewNop(), nil, nil, auth.New(cfg, keys), ratelimit.New(cfg, nil))
+}
+
+func call(e http.Handler, method, target, body, ip string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.RemoteAddr = ip + ":40000"
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReadLimitPerIP(t *testing.T) {
+	e := newRouter(t, func(*config.Config) {})
+
+	rec := call(e, http.MethodGet, "/movies", "", "192.0.2.1")
+	assert.NotEqual(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get(ratelimit.HeaderLimit))
+	assert.Equal(t, "1", rec.Header().Get(ratelimit.HeaderRemaining))
+	assert.Equal(t, "1000", rec.Header().Get(ratelimit.HeaderReset))
+	assert.Equal(t, "2;w=2000", rec.Header().Get(ratelimit.HeaderPolicy))
+	assert.NotEqual(t, http.StatusTooManyRequests, call(e, http.MethodGet, "/characters", "", "192.0.2.1").Code)
+
+	rec = call(e, http.MethodGet, "/movies", "", "192.0.2.1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get(ratelimit.HeaderRemaining))
+	assert.Equal(t, "1000", rec.Header().Get(echo.HeaderRetryAfter))
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, problem.TypeRateLimit, p.Type)
+	assert.Contains(t, p.Detail, "read requests")
+
+	assert.NotEqual(t, http.StatusTooManyRequests, call(e, http.MethodGet, "/movies", "", "192.0.2.2").Code)
+}
+
+func TestClassesAndTiers(t *testing.T) {
+	e := newRouter(t, func(cfg *config.Config) {
+		cfg.RateLimit.Tiers["admin"] = 0
+	})
+	movie := `{"title":"Shrek","release_year":2001}`
+	character := `{"name":"Donkey"}`
+	editor := func() string {
+		rec := call(e, http.MethodPost, "/admin/api-keys", `{"name":"ci","role":"editor"}`, "192.0.2.9", auth.HeaderAPIKey, adminKey)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var created struct{ Secret string }
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+		return created.Secret
+	}()
+
+	// Editors get twice the write burst.
+	for range 2 {
+		assert.Equal(t, http.StatusCreated, call(e, http.MethodPost, "/movies", movie, "192.0.2.1", auth.HeaderAPIKey, editor).Code)
+	}
+	rec := call(e, http.MethodPost, "/movies", movie, "192.0.2.1", auth.HeaderAPIKey, editor)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get(ratelimit.HeaderLimit))
+	rec = call(e, http.MethodPost, "/characters", character, "192.0.2.1", auth.HeaderAPIKey, editor)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), "write requests")
+
+	// The key, not the IP, identifies the editor.
+	assert.Equal(t, http.StatusTooManyRequests, call(e, http.MethodPost, "/movies", movie, "192.0.2.2", auth.HeaderAPIKey, editor).Code)
+
+	// Admins are not limited at all.
+	for range 5 {
+		rec := call(e, http.MethodPost, "/movies", movie, "192.0.2.1", auth.HeaderAPIKey, adminKey)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Empty(t, rec.Header().Get(ratelimit.HeaderLimit))
+	}
+}
+
+func TestSWAPILimitOnlyForStarWars(t *testing.T) {
+	e := newRouter(t, func(cfg *config.Config) {
+		cfg.RateLimit.Write = slow(4)
+		cfg.RateLimit.Tiers["admin"] = 1
+	})
+	create := func(body string) *httptest.ResponseRecorder {
+		return call(e, http.MethodPost, "/characters", body, "192.0.2.1", auth.HeaderAPIKey, adminKey)
+	}
+	batch := func(body string) *httptest.ResponseRecorder {
+		return call(e, http.MethodPost, "/batch", `{"operations":[`+body+`]}`, "192.0.2.1", auth.HeaderAPIKey, adminKey)
+	}
+
+	// Star Wars characters take the only SWAPI token on top of a write one.
+	assert.Equal(t, http.StatusCreated, create(`{"name":"Yoda","movie":"Star Wars"}`).Code)
+	rec := create(`{"name":"Luke","movie":"Star Wars"}`)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), "swapi requests")
+	rec = batch(`{"op":"create_character","name":"Leia","movie":"Star Wars"}`)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), "swapi requests")
+
+	// Other characters only take write tokens, of which one is left.
+	assert.Equal(t, http.StatusOK, batch(`{"op":"create_character","name":"Fiona"}`).Code)
+	rec = create(`{"name":"Donkey"}`)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), "write requests")
+}
+
+func TestGraphQLMutationsUseWriteLimit(t *testing.T) {
+	e := newRouter(t, func(cfg *config.Config) {
+		cfg.RateLimit.Tiers["admin"] = 1
+	})
+	mutation := `{"query":"mutation { createMovie(title: \"Shrek\", releaseYear: 2001) { id } }"}`
+
+	rec := call(e, http.MethodPost, "/graphql", mutation, "192.0.2.1", auth.HeaderAPIKey, adminKey)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "errors")
+
+	// The mutation took the only write token although /graphql is a read route.
+	rec = call(e, http.MethodPost, "/graphql", `{"query":"mutation { a: createMovie(title: \"Shrek 2\", releaseYear: 2004) { id } }"}`, "192.0.2.1", auth.HeaderAPIKey, adminKey)
+	assert.Contains(t, rec.Body.String(), graphql.CodeRateLimited)
+	assert.Equal(t, http.StatusTooManyRequests, call(e, http.MethodPost, "/movies", `{"title":"Shrek 2","release_year":2004}`, "192.0.2.1", auth.HeaderAPIKey, adminKey).Code)
+	assert.Equal(t, http.StatusOK, call(e, http.MethodPost, "/graphql", `{"query":"{ movies { id } }"}`, "192.0.2.1").Code)
+}
+
+func TestRouteLimitAndProxy(t *testing.T) {
+	e := newRouter(t, func(cfg *config.Config) {
+		cfg.RateLimit.TrustProxy = true
+		cfg.RateLimit.Routes = map[string]config.Limit{"GET /characters": slow(5)}
+	})
+
+	for range 5 {
+		assert.NotEqual(t, http.StatusTooManyRequests, call(e, http.MethodGet, "/characters", "", "10.0.0.1", "X-Forwarded-For", "198.51.100.7").Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, call(e, http.MethodGet, "/characters", "", "10.0.0.1", "X-Forwarded-For", "198.51.100.7").Code)
+	assert.NotEqual(t, http.StatusTooManyRequests, call(e, http.MethodGet, "/characters", "", "10.0.0.1", "X-Forwarded-For", "198.51.100.8").Code)
+	assert.NotEqual(t, http.StatusTooManyRequests, call(e, http.MethodGet, "/movies", "", "10.0.0.1", "X-Forwarded-For", "198.51.100.7").Code)
+}
+
+func TestDisabled(t *testing.T) {
+	cfg := config.Default()
+	cfg.RateLimit.Enabled = false
+	assert.Nil(t, ratelimit.New(cfg, nil))
+}
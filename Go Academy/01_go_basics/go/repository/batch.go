@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"example.com/go_basics/go/audit"
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/entity"
+	"example.com/go_basics/go/events"
+	"example.com/go_basics/go/logging"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Tx stages the changes of a batch. Each call sees the changes staged
+// before it; none reaches the store unless the whole batch succeeds.
+type Tx struct {
+	r   *Repository
+	ctx context.Context
+	// movies and characters hold the staged versions, nil when deleted.
+	movies     map[uuid.UUID]*entity.Movie
+	characters map[uuid.UUID]*entity.Character
+	// appearances is the staged list once an appearance changed.
+	appearances []entity.Appearance
+	linksDirty  bool
+	// trash holds the movies and characters the batch deleted.
+	trash  map[uuid.UUID]db.Deleted
+	events []stagedEvent
+	audits []audit.Entry
+}
+
+type stagedEvent struct {
+	typ  events.Type
+	data any
+}
+
+// Batch runs fn on a transaction while every other change waits, and
+// commits what fn staged only when it returns nil. Events are published
+// once the batch is committed, in the order of the calls, and so are the
+// audit entries. What fn checks against the store holds until the commit.
+//
+// Snapshots see a batch all or nothing. Plain reads take no lock, so one
+// running during the commit may see some of its changes before the rest.
+func (r *Repository) Batch(ctx context.Context, fn func(tx *Tx) error) error {
+	ctx, end := r.observe(ctx, "Batch")
+	defer end()
+	r.DB.Writes.Lock()
+	defer r.DB.Writes.Unlock()
+	tx := &Tx{
+		r:          r,
+		ctx:        ctx,
+		movies:     make(map[uuid.UUID]*entity.Movie),
+		characters: make(map[uuid.UUID]*entity.Character),
+		trash:      make(map[uuid.UUID]db.Deleted),
+	}
+	if err := fn(tx); err != nil {
+		logging.FromContext(ctx).Debug("batch rolled back", zap.Int("changes", len(tx.events)), zap.Error(err))
+		return err
+	}
+	tx.commit()
+	logging.FromContext(ctx).Debug("batch committed", zap.Int("changes", len(tx.events)))
+	return nil
+}
+
+func (tx *Tx) commit() {
+	store := tx.r.DB
+	for id, m := range tx.movies {
+		if m == nil {
+			store.Movies.Delete(id)
+		} else {
+			store.Movies.Store(id, *m)
+		}
+	}
+	for id, c := range tx.characters {
+		if c == nil {
+			store.Characters.Delete(id)
+		} else {
+			store.Characters.Store(id, *c)
+		}
+	}
+	store.Mutex.Lock()
+	if tx.linksDirty {
+		store.Appearances = tx.appearances
+	}
+	for id, d := range tx.trash {
+		store.Trash[id] = d
+	}
+	store.Mutex.Unlock()
+	for _, e := range tx.events {
+		tx.r.publish(e.typ, e.data)
+	}
+	tx.r.Audit.Append(tx.audits...)
+}
+
+func (tx *Tx) publish(typ events.Type, data any) {
+	tx.events = append(tx.events, stagedEvent{typ: typ, data: data})
+}
+
+func (tx *Tx) record(e audit.Entry) {
+	tx.audits = append(tx.audits, e)
+}
+
+// GetMovie returns the movie as staged so far.
+func (tx *Tx) GetMovie(id uuid.UUID) (entity.Movie, error) {
+	if m, ok := tx.movies[id]; ok {
+		if m == nil {
+			return entity.Movie{}, fmt.Errorf("%w [ID: %s]", ErrMovieNotFound, id)
+		}
+		return *m, nil
+	}
+	mRaw, ok := tx.r.DB.Movies.Load(id)
+	if !ok {
+		return entity.Movie{}, fmt.Errorf("%w [ID: %s]", ErrMovieNotFound, id)
+	}
+	return mRaw.(entity.Movie), nil
+}
+
+// GetCharacter returns the character as staged so far.
+func (tx *Tx) GetCharacter(id uuid.UUID) (entity.Character, error) {
+	if c, ok := tx.characters[id]; ok {
+		if c == nil {
+			return entity.Character{}, fmt.Errorf("%w [ID: %s]", ErrCharacterNotFound, id)
+		}
+		return *c, nil
+	}
+	cRaw, ok := tx.r.DB.Characters.Load(id)
+	if !ok {
+		return entity.Character{}, fmt.Errorf("%w [ID: %s]", ErrCharacterNotFound, id)
+	}
+	return cRaw.(entity.Character), nil
+}
+
+func (tx *Tx) CreateMovie(title string, year int) (entity.Movie, error) {
+	if title == "" {
+		return entity.Movie{}, fmt.Errorf("%w: movie title cannot be empty", ErrInvalidInput)
+	}
+	movie := entity.NewMovie(entity.WithTitle(title), entity.WithYear(year))
+	tx.movies[movie.ID] = &movie
+	tx.publish(events.MovieCreated, movie)
+	tx.record(auditEntry(tx.ctx, events.MovieCreated, movie.ID, nil, movie))
+	return movie, nil
+}
+
+func (tx *Tx) CreateCharacter(name string) (entity.Character, error) {
+	if name == "" {
+		return entity.Character{}, fmt.Errorf("%w: character name cannot be empty", ErrInvalidInput)
+	}
+	character := entity.NewCharacter(entity.WithName(name))
+	tx.characters[character.ID] = &character
+	tx.publish(events.CharacterCreated, character)
+	tx.record(auditEntry(tx.ctx, events.CharacterCreated, character.ID, nil, character))
+	return character, nil
+}
+
+// UpdateMovie changes the title and year of the movie at version. Empty
+// values keep the current ones.
+func (tx *Tx) UpdateMovie(id uuid.UUID, title string, year int, version int64) (entity.Movie, error) {
+	before, err := tx.GetMovie(id)
+	if err != nil {
+		return entity.Movie{}, err
+	}
+	if err := checkVersion("movie", id, before.Version, version); err != nil {
+		return entity.Movie{}, err
+	}
+	movie := before
+	if title != "" {
+		movie.Title = title
+	}
+	if year != 0 {
+		movie.Year = year
+	}
+	movie.Version++
+	tx.movies[id] = &movie
+	tx.publish(events.MovieUpdated, movie)
+	tx.record(auditEntry(tx.ctx, events.MovieUpdated, id, before, movie))
+	return movie, nil
+}
+
+// UpdateCharacter renames the character at version.
+func (tx *Tx) UpdateCharacter(id uuid.UUID, newName string, version int64) (entity.Character, error) {
+	if newName == "" {
+		return entity.Character{}, fmt.Errorf("%w: new character name cannot be empty", ErrInvalidInput)
+	}
+	before, err := tx.GetCharacter(id)
+	if err != nil {
+		return entity.Character{}, err
+	}
+	if err := checkVersion("character", id, before.Version, version); err != nil {
+		return entity.Character{}, err
+	}
+	character := before
+	character.Name = newName
+	character.Version++
+	tx.characters[id] = &character
+	tx.publish(events.CharacterUpdated, character)
+	tx.record(auditEntry(tx.ctx, events.CharacterUpdated, id, before, character))
+	return character, nil
+}
+
+// DeleteMovie moves the movie at version to the trash, together with its
+// appearances.
+func (tx *Tx) DeleteMovie(id uuid.UUID, version int64) error {
+	movie, err := tx.GetMovie(id)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion("movie", id, movie.Version, version); err != nil {
+		return err
+	}
+	tx.movies[id] = nil
+	deleted := movie
+	deleted.DeletedAt = deletedAt()
+	removed := tx.unlink(func(a entity.Appearance) bool { return a.MovieID == id })
+	tx.trash[id] = db.Deleted{Movie: &deleted, Appearances: removed}
+	tx.publish(events.MovieDeleted, deleted)
+	tx.record(auditEntry(tx.ctx, events.MovieDeleted, id, movie, deleted))
+	return nil
+}
+
+// DeleteCharacter moves the character at version to the trash, together
+// with its appearances.
+func (tx *Tx) DeleteCharacter(id uuid.UUID, version int64) error {
+	character, err := tx.GetCharacter(id)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion("character", id, character.Version, version); err != nil {
+		return err
+	}
+	tx.characters[id] = nil
+	deleted := character
+	deleted.DeletedAt = deletedAt()
+	removed := tx.unlink(func(a entity.Appearance) bool { return a.CharacterID == id })
+	tx.trash[id] = db.Deleted{Character: &deleted, Appearances: removed}
+	tx.publish(events.CharacterDeleted, deleted)
+	tx.record(auditEntry(tx.ctx, events.CharacterDeleted, id, character, deleted))
+	return nil
+}
+
+func (tx *Tx) AddAppearance(movieID, characterID uuid.UUID) error {
+	if _, err := tx.GetMovie(movieID); err != nil {
+		return err
+	}
+	if _, err := tx.GetCharacter(characterID); err != nil {
+		return err
+	}
+	appearance := entity.New(entity.WithMovieId(movieID), entity.WithCharacterId(characterID))
+	tx.appearances = append(tx.links(), appearance)
+	tx.linksDirty = true
+	tx.publish(events.AppearanceLinked, appearance)
+	tx.record(linkEntry(tx.ctx, events.AppearanceLinked, appearance))
+	return nil
+}
+
+// RemoveAppearance unlinks a character from a movie.
+func (tx *Tx) RemoveAppearance(movieID, characterID uuid.UUID) error {
+	removed := tx.unlink(func(a entity.Appearance) bool {
+		return a.MovieID == movieID && a.CharacterID == characterID
+	})
+	if len(removed) == 0 {
+		return fmt.Errorf("%w [movie: %s, character: %s]", ErrAppearanceNotFound, movieID, characterID)
+	}
+	return nil
+}
+
+// links returns the staged appearances, copying the stored ones first.
+func (tx *Tx) links() []entity.Appearance {
+	if !tx.linksDirty {
+		tx.r.DB.Mutex.Lock()
+		tx.appearances = append([]entity.Appearance(nil), tx.r.DB.Appearances...)
+		tx.r.DB.Mutex.Unlock()
+	}
+	return tx.appearances
+}
+
+func (tx *Tx) unlink(remove func(entity.Appearance) bool) []entity.Appearance {
+	var kept, removed []entity.Appearance
+	for _, a := range tx.links() {
+		if remove(a) {
+			removed = append(removed, a)
+		} else {
+			kept = append(kept, a)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	tx.appearances = kept
+	tx.linksDirty = true
+	for _, a := range removed {
+		tx.publish(events.AppearanceUnlinked, a)
+		tx.record(linkEntry(tx.ctx, events.AppearanceUnlinked, a))
+	}
+	return removed
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Callers match these with errors.Is; the returned errors add the ID, title
+// or name that was looked up.
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrMovieNotFound      = fmt.Errorf("movie %w", ErrNotFound)
+	ErrCharacterNotFound  = fmt.Errorf("character %w", ErrNotFound)
+	ErrAppearanceNotFound = fmt.Errorf("appearance %w", ErrNotFound)
+	ErrPathNotFound       = fmt.Errorf("path %w", ErrNotFound)
+	ErrFranchiseNotFound  = fmt.Errorf("franchise %w", ErrNotFound)
+	ErrNoMovies           = fmt.Errorf("no movies available: %w", ErrNotFound)
+	ErrNoCharacters       = fmt.Errorf("no characters available: %w", ErrNotFound)
+	ErrInvalidInput       = errors.New("invalid input")
+	ErrVersionMismatch    = errors.New("version mismatch")
+)
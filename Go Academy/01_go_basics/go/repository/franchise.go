@@ -0,0 +1,348 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"example.com/go_basics/go/entity"
+	"example.com/go_basics/go/events"
+	"example.com/go_basics/go/logging"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// checkFranchise validates a franchise name and its installments, which
+// have to be stored movies, each at most once.
+func (r *Repository) checkFranchise(name string, installments []entity.Installment) ([]entity.Installment, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: franchise name cannot be empty", ErrInvalidInput)
+	}
+	result := make([]entity.Installment, 0, len(installments))
+	seen := map[uuid.UUID]bool{}
+	for _, in := range installments {
+		if !slices.Contains(entity.InstallmentKinds, in.Kind) {
+			return nil, fmt.Errorf("%w: unknown installment kind %q", ErrInvalidInput, in.Kind)
+		}
+		if seen[in.MovieID] {
+			return nil, fmt.Errorf("%w: movie %s is in the franchise twice", ErrInvalidInput, in.MovieID)
+		}
+		seen[in.MovieID] = true
+		if _, ok := r.DB.Movies.Load(in.MovieID); !ok {
+			return nil, fmt.Errorf("%w [ID: %s]", ErrMovieNotFound, in.MovieID)
+		}
+		result = append(result, in)
+	}
+	return result, nil
+}
+
+func (r *Repository) CreateFranchise(ctx context.Context, name string, installments []entity.Installment) (entity.Franchise, error) {
+	ctx, end := r.observe(ctx, "CreateFranchise")
+	defer end()
+	r.DB.Writes.RLock()
+	defer r.DB.Writes.RUnlock()
+	installments, err := r.checkFranchise(name, installments)
+	if err != nil {
+		return entity.Franchise{}, err
+	}
+	franchise := entity.NewFranchise(entity.WithFranchiseName(name), entity.WithInstallments(installments...))
+	r.DB.Mutex.Lock()
+	r.DB.Franchises[franchise.ID] = franchise
+	r.DB.Mutex.Unlock()
+	r.publish(events.FranchiseCreated, franchise)
+	r.Audit.Append(auditEntry(ctx, events.FranchiseCreated, franchise.ID, nil, franchise))
+	logging.FromContext(ctx).Debug("franchise created", zap.Stringer("franchise_id", franchise.ID), zap.String("name", name))
+	return franchise, nil
+}
+
+func (r *Repository) GetFranchise(ctx context.Context, id uuid.UUID) (entity.Franchise, error) {
+	_, end := r.observe(ctx, "GetFranchise")
+	defer end()
+	r.DB.Mutex.Lock()
+	defer r.DB.Mutex.Unlock()
+	franchise, ok := r.DB.Franchises[id]
+	if !ok {
+		return entity.Franchise{}, fmt.Errorf("%w [ID: %s]", ErrFranchiseNotFound, id)
+	}
+	return franchise, nil
+}
+
+// ListFranchises returns every franchise, by name.
+func (r *Repository) ListFranchises(ctx context.Context) []entity.Franchise {
+	ctx, end := r.observe(ctx, "ListFranchises")
+	defer end()
+	r.DB.Mutex.Lock()
+	result := make([]entity.Franchise, 0, len(r.DB.Franchises))
+	for _, f := range r.DB.Franchises {
+		result = append(result, f)
+	}
+	r.DB.Mutex.Unlock()
+	slices.SortFunc(result, func(a, b entity.Franchise) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	logging.FromContext(ctx).Debug("franchises listed", zap.Int("count", len(result)))
+	return result
+}
+
+// UpdateFranchise replaces the name and installments of the franchise at
+// version.
+func (r *Repository) UpdateFranchise(ctx context.Context, id uuid.UUID, name string, installments []entity.Installment, version int64) (entity.Franchise, error) {
+	ctx, end := r.observe(ctx, "UpdateFranchise")
+	defer end()
+	r.DB.Writes.RLock()
+	defer r.DB.Writes.RUnlock()
+	installments, err := r.checkFranchise(name, installments)
+	if err != nil {
+		return entity.Franchise{}, err
+	}
+	return r.changeFranchise(ctx, id, version, func(f *entity.Franchise) bool {
+		f.Name, f.Installments = name, installments
+		return true
+	})
+}
+
+// changeFranchise applies change to the franchise at version and stores it
+// with the next version unless change reports that nothing changed. The
+// caller holds Writes.
+func (r *Repository) changeFranchise(ctx context.Context, id uuid.UUID, version int64, change func(*entity.Franchise) bool) (entity.Franchise, error) {
+	r.DB.Mutex.Lock()
+	current, ok := r.DB.Franchises[id]
+	if !ok {
+		r.DB.Mutex.Unlock()
+		return entity.Franchise{}, fmt.Errorf("%w [ID: %s]", ErrFranchiseNotFound, id)
+	}
+	if err := checkVersion("franchise", id, current.Version, version); err != nil {
+		r.DB.Mutex.Unlock()
+		return entity.Franchise{}, err
+	}
+	franchise := current
+	if !change(&franchise) {
+		r.DB.Mutex.Unlock()
+		return current, nil
+	}
+	franchise.Version++
+	r.DB.Franchises[id] = franchise
+	r.DB.Mutex.Unlock()
+	r.publish(events.FranchiseUpdated, franchise)
+	r.Audit.Append(auditEntry(ctx, events.FranchiseUpdated, id, current, franchise))
+	logging.FromContext(ctx).Debug("franchise updated", zap.Stringer("franchise_id", id), zap.Int64("version", franchise.Version))
+	return franchise, nil
+}
+
+// DeleteFranchise removes the franchise at version. Its movies and
+// characters stay.
+func (r *Repository) DeleteFranchise(ctx context.Context, id uuid.UUID, version int64) error {
+	ctx, end := r.observe(ctx, "DeleteFranchise")
+	defer end()
+	r.DB.Writes.RLock()
+	defer r.DB.Writes.RUnlock()
+	r.DB.Mutex.Lock()
+	franchise, ok := r.DB.Franchises[id]
+	if !ok {
+		r.DB.Mutex.Unlock()
+		return fmt.Errorf("%w [ID: %s]", ErrFranchiseNotFound, id)
+	}
+	if err := checkVersion("franchise", id, franchise.Version, version); err != nil {
+		r.DB.Mutex.Unlock()
+		return err
+	}
+	delete(r.DB.Franchises, id)
+	r.DB.Mutex.Unlock()
+	r.publish(events.FranchiseDeleted, franchise)
+	r.Audit.Append(auditEntry(ctx, events.FranchiseDeleted, id, franchise, nil))
+	logging.FromContext(ctx).Debug("franchise deleted", zap.Stringer("franchise_id", id))
+	return nil
+}
+
+// LinkFranchiseCharacter links the character to the franchise as a whole.
+// Linking it again changes nothing.
+func (r *Repository) LinkFranchiseCharacter(ctx context.Context, franchiseID, characterID uuid.UUID) (entity.Franchise, error) {
+	ctx, end := r.observe(ctx, "LinkFranchiseCharacter")
+	defer end()
+	r.DB.Writes.RLock()
+	defer r.DB.Writes.RUnlock()
+	if _, ok := r.DB.Characters.Load(characterID); !ok {
+		return entity.Franchise{}, fmt.Errorf("%w [ID: %s]", ErrCharacterNotFound, characterID)
+	}
+	return r.changeFranchise(ctx, franchiseID, AnyVersion, func(f *entity.Franchise) bool {
+		if slices.Contains(f.Characters, characterID) {
+			return false
+		}
+		f.Characters = append(slices.Clip(f.Characters), characterID)
+		return true
+	})
+}
+
+// CreateCharacterInFranchise creates a character linked to the franchise in
+// one change: without the franchise no character is created.
+func (r *Repository) CreateCharacterInFranchise(ctx context.Context, name string, franchiseID uuid.UUID) (entity.Character, error) {
+	ctx, end := r.observe(ctx, "CreateCharacterInFranchise")
+	defer end()
+	r.DB.Writes.RLock()
+	defer r.DB.Writes.RUnlock()
+	if name == "" {
+		return entity.Character{}, fmt.Errorf("%w: character name cannot be empty", ErrInvalidInput)
+	}
+	character := entity.NewCharacter(entity.WithName(name))
+	r.DB.Mutex.Lock()
+	current, ok := r.DB.Franchises[franchiseID]
+	if !ok {
+		r.DB.Mutex.Unlock()
+		return entity.Character{}, fmt.Errorf("%w [ID: %s]", ErrFranchiseNotFound, franchiseID)
+	}
+	franchise := current
+	franchise.Characters = append(slices.Clip(franchise.Characters), character.ID)
+	franchise.Version++
+	r.DB.Characters.Store(character.ID, character)
+	r.DB.Franchises[franchiseID] = franchise
+	r.DB.Mutex.Unlock()
+	r.publish(events.CharacterCreated, character)
+	r.Audit.Append(auditEntry(ctx, events.CharacterCreated, character.ID, nil, character))
+	r.publish(events.FranchiseUpdated, franchise)
+	r.Audit.Append(auditEntry(ctx, events.FranchiseUpdated, franchiseID, current, franchise))
+	logging.FromContext(ctx).Debug("character added to franchise", zap.Stringer("character_id", character.ID), zap.String("name", name), zap.Stringer("franchise_id", franchiseID))
+	return character, nil
+}
+
+// UnlinkFranchiseCharacter drops the link of the character to the
+// franchise. Its appearances in the installments stay.
+func (r *Repository) UnlinkFranchiseCharacter(ctx context.Context, franchiseID, characterID uuid.UUID) (entity.Franchise, error) {
+	ctx, end := r.observe(ctx, "UnlinkFranchiseCharacter")
+	defer end()
+	r.DB.Writes.RLock()
+	defer r.DB.Writes.RUnlock()
+	linked := true
+	franchise, err := r.changeFranchise(ctx, franchiseID, AnyVersion, func(f *entity.Franchise) bool {
+		i := slices.Index(f.Characters, characterID)
+		if i < 0 {
+			linked = false
+			return false
+		}
+		f.Characters = slices.Delete(slices.Clone(f.Characters), i, i+1)
+		return true
+	})
+	if err == nil && !linked {
+		return entity.Franchise{}, fmt.Errorf("%w in the franchise [franchise: %s, character: %s]", ErrCharacterNotFound, franchiseID, characterID)
+	}
+	return franchise, err
+}
+
+// Installment is a stored movie at its place in a franchise.
+type Installment struct {
+	// Position counts from 1, including installments in the trash.
+	Position int
+	Kind     string
+	Movie    entity.Movie
+}
+
+// installments returns the stored movies of the franchise in order.
+func (r *Repository) installments(f entity.Franchise) []Installment {
+	var result []Installment
+	for i, in := range f.Installments {
+		if mRaw, ok := r.DB.Movies.Load(in.MovieID); ok {
+			result = append(result, Installment{Position: i + 1, Kind: in.Kind, Movie: mRaw.(entity.Movie)})
+		}
+	}
+	return result
+}
+
+// FranchiseCharacter is a character of a franchise.
+type FranchiseCharacter struct {
+	Character entity.Character
+	// Linked is set when the character is linked to the franchise as a
+	// whole.
+	Linked bool
+	// Installments are those the character appears in, in order.
+	Installments []Installment
+}
+
+// FranchiseCharacters returns the characters appearing in an installment of
+// the franchise, by their first installment, followed by those only linked
+// to the franchise as a whole.
+func (r *Repository) FranchiseCharacters(ctx context.Context, id uuid.UUID) ([]FranchiseCharacter, error) {
+	ctx, end := r.observe(ctx, "FranchiseCharacters")
+	defer end()
+	franchise, err := r.GetFranchise(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	idx := r.index()
+	var order []uuid.UUID
+	byID := map[uuid.UUID]*FranchiseCharacter{}
+	add := func(characterID uuid.UUID) *FranchiseCharacter {
+		if c, ok := byID[characterID]; ok {
+			return c
+		}
+		cRaw, ok := r.DB.Characters.Load(characterID)
+		if !ok {
+			return nil
+		}
+		byID[characterID] = &FranchiseCharacter{Character: cRaw.(entity.Character)}
+		order = append(order, characterID)
+		return byID[characterID]
+	}
+	for _, in := range r.installments(franchise) {
+		for _, characterID := range idx.characters[in.Movie.ID] {
+			if c := add(characterID); c != nil {
+				c.Installments = append(c.Installments, in)
+			}
+		}
+	}
+	linked := slices.Clone(franchise.Characters)
+	slices.SortStableFunc(linked, func(a, b uuid.UUID) int { return cmp.Compare(r.characterName(a), r.characterName(b)) })
+	for _, characterID := range linked {
+		if c := add(characterID); c != nil {
+			c.Linked = true
+		}
+	}
+	result := make([]FranchiseCharacter, 0, len(order))
+	for _, characterID := range order {
+		result = append(result, *byID[characterID])
+	}
+	logging.FromContext(ctx).Debug("franchise characters", zap.Stringer("franchise_id", id), zap.Int("count", len(result)))
+	return result, nil
+}
+
+func (r *Repository) characterName(id uuid.UUID) string {
+	if cRaw, ok := r.DB.Characters.Load(id); ok {
+		return cRaw.(entity.Character).Name
+	}
+	return ""
+}
+
+// FranchiseTimeline is the part a character plays in a franchise.
+type FranchiseTimeline struct {
+	Franchise entity.Franchise
+	// Linked is set when the character is linked to the franchise as a
+	// whole.
+	Linked bool
+	// Installments are those the character appears in, in order.
+	Installments []Installment
+}
+
+// CharacterTimeline returns the franchises the character is linked to or
+// appears in an installment of, by name, each with the installments the
+// character appears in.
+func (r *Repository) CharacterTimeline(ctx context.Context, characterID uuid.UUID) ([]FranchiseTimeline, error) {
+	ctx, end := r.observe(ctx, "CharacterTimeline")
+	defer end()
+	if _, ok := r.DB.Characters.Load(characterID); !ok {
+		return nil, fmt.Errorf("%w [ID: %s]", ErrCharacterNotFound, characterID)
+	}
+	idx := r.index()
+	var result []FranchiseTimeline
+	for _, franchise := range r.ListFranchises(ctx) {
+		t := FranchiseTimeline{Franchise: franchise, Linked: slices.Contains(franchise.Characters, characterID)}
+		for _, in := range r.installments(franchise) {
+			if slices.Contains(idx.movies[characterID], in.Movie.ID) {
+				t.Installments = append(t.Installments, in)
+			}
+		}
+		if t.Linked || len(t.Installments) > 0 {
+			result = append(result, t)
+		}
+	}
+	logging.FromContext(ctx).Debug("character timeline", zap.Stringer("character_id", characterID), zap.Int("franchises", len(result)))
+	return result, nil
+}
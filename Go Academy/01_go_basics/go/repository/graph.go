@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"example.com/go_basics/go/entity"
+	"example.com/go_basics/go/logging"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MaxPathDepth bounds the movies a path between two characters may go
+// through.
+const MaxPathDepth = 12
+
+// appearanceIndex is the appearances as a graph of characters and movies,
+// linked both ways. A character linked to a movie twice is linked once.
+type appearanceIndex struct {
+	movies     map[uuid.UUID][]uuid.UUID // of a character
+	characters map[uuid.UUID][]uuid.UUID // of a movie
+}
+
+func (r *Repository) index() appearanceIndex {
+	idx := appearanceIndex{movies: map[uuid.UUID][]uuid.UUID{}, characters: map[uuid.UUID][]uuid.UUID{}}
+	seen := map[[2]uuid.UUID]bool{}
+	r.DB.Mutex.Lock()
+	defer r.DB.Mutex.Unlock()
+	for _, a := range r.DB.Appearances {
+		if link := [2]uuid.UUID{a.MovieID, a.CharacterID}; !seen[link] {
+			seen[link] = true
+			idx.movies[a.CharacterID] = append(idx.movies[a.CharacterID], a.MovieID)
+			idx.characters[a.MovieID] = append(idx.characters[a.MovieID], a.CharacterID)
+		}
+	}
+	return idx
+}
+
+func byYear(a, b entity.Movie) int {
+	return cmp.Or(cmp.Compare(a.Year, b.Year), cmp.Compare(a.Title, b.Title), cmp.Compare(a.ID.String(), b.ID.String()))
+}
+
+func byName(a, b entity.Character) int {
+	return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID.String(), b.ID.String()))
+}
+
+// CoStar is a character sharing movies with another one.
+type CoStar struct {
+	Character entity.Character
+	// Movies are the shared movies, the oldest first.
+	Movies []entity.Movie
+}
+
+// CoStars returns the characters appearing in a movie with the character,
+// the most shared movies first, at most limit of them when it is positive.
+func (r *Repository) CoStars(ctx context.Context, id uuid.UUID, limit int) ([]CoStar, error) {
+	ctx, end := r.observe(ctx, "CoStars")
+	defer end()
+	if _, ok := r.DB.Characters.Load(id); !ok {
+		return nil, fmt.Errorf("%w [ID: %s]", ErrCharacterNotFound, id)
+	}
+	idx := r.index()
+	shared := map[uuid.UUID][]entity.Movie{}
+	for _, movieID := range idx.movies[id] {
+		mRaw, ok := r.DB.Movies.Load(movieID)
+		if !ok {
+			continue
+		}
+		for _, characterID := range idx.characters[movieID] {
+			if characterID != id {
+				shared[characterID] = append(shared[characterID], mRaw.(entity.Movie))
+			}
+		}
+	}
+	result := make([]CoStar, 0, len(shared))
+	for characterID, movies := range shared {
+		if cRaw, ok := r.DB.Characters.Load(characterID); ok {
+			slices.SortFunc(movies, byYear)
+			result = append(result, CoStar{Character: cRaw.(entity.Character), Movies: movies})
+		}
+	}
+	slices.SortFunc(result, func(a, b CoStar) int {
+		return cmp.Or(cmp.Compare(len(b.Movies), len(a.Movies)), byName(a.Character, b.Character))
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	logging.FromContext(ctx).Debug("co-stars", zap.Stringer("character_id", id), zap.Int("count", len(result)))
+	return result, nil
+}
+
+// Path links two characters through movies: Movies[i] has both
+// Characters[i] and Characters[i+1].
+type Path struct {
+	Characters []entity.Character
+	Movies     []entity.Movie
+}
+
+// ShortestPath finds the fewest movies that link the characters from and to,
+// searching at most maxDepth movies deep.
+func (r *Repository) ShortestPath(ctx context.Context, from, to uuid.UUID, maxDepth int) (Path, error) {
+	ctx, end := r.observe(ctx, "ShortestPath")
+	defer end()
+	if maxDepth < 1 || maxDepth > MaxPathDepth {
+		return Path{}, fmt.Errorf("%w: depth must be between 1 and %d", ErrInvalidInput, MaxPathDepth)
+	}
+	for _, id := range []uuid.UUID{from, to} {
+		if _, ok := r.DB.Characters.Load(id); !ok {
+			return Path{}, fmt.Errorf("%w [ID: %s]", ErrCharacterNotFound, id)
+		}
+	}
+
+	// via holds the character and movie each reached character came from.
+	type hop struct{ character, movie uuid.UUID }
+	idx := r.index()
+	via := map[uuid.UUID]hop{from: {}}
+	visited := map[uuid.UUID]bool{}
+	frontier := []uuid.UUID{from}
+	for depth := 0; depth < maxDepth && len(frontier) > 0; depth++ {
+		if _, ok := via[to]; ok {
+			break
+		}
+		var next []uuid.UUID
+		for _, characterID := range frontier {
+			for _, movieID := range idx.movies[characterID] {
+				if visited[movieID] {
+					continue
+				}
+				visited[movieID] = true
+				for _, other := range idx.characters[movieID] {
+					if _, ok := via[other]; !ok {
+						via[other] = hop{character: characterID, movie: movieID}
+						next = append(next, other)
+					}
+				}
+			}
+		}
+		frontier = next
+	}
+	if _, ok := via[to]; !ok {
+		return Path{}, fmt.Errorf("%w within %d movies [from: %s, to: %s]", ErrPathNotFound, maxDepth, from, to)
+	}
+
+	var path Path
+	for id := to; ; id = via[id].character {
+		cRaw, ok := r.DB.Characters.Load(id)
+		if !ok {
+			return Path{}, fmt.Errorf("%w [ID: %s]", ErrCharacterNotFound, id)
+		}
+		path.Characters = append(path.Characters, cRaw.(entity.Character))
+		if id == from {
+			break
+		}
+		mRaw, ok := r.DB.Movies.Load(via[id].movie)
+		if !ok {
+			return Path{}, fmt.Errorf("%w [ID: %s]", ErrMovieNotFound, via[id].movie)
+		}
+		path.Movies = append(path.Movies, mRaw.(entity.Movie))
+	}
+	slices.Reverse(path.Characters)
+	slices.Reverse(path.Movies)
+	logging.FromContext(ctx).Debug("shortest path", zap.Stringer("from", from), zap.Stringer("to", to), zap.Int("movies", len(path.Movies)))
+	return path, nil
+}
+
+// Component is a set of characters and movies linked through appearances
+// with none outside it.
+type Component struct {
+	Characters []entity.Character
+	Movies     []entity.Movie
+}
+
+func (c Component) size() int {
+	return len(c.Characters) + len(c.Movies)
+}
+
+// Components splits the characters and movies into connected components,
+// the largest first. A character or movie without appearances is a
+// component of its own.
+func (r *Repository) Components(ctx context.Context) []Component {
+	ctx, end := r.observe(ctx, "Components")
+	defer end()
+	characters := map[uuid.UUID]entity.Character{}
+	r.DB.Characters.Range(func(key, value any) bool {
+		characters[key.(uuid.UUID)] = value.(entity.Character)
+		return true
+	})
+	movies := map[uuid.UUID]entity.Movie{}
+	r.DB.Movies.Range(func(key, value any) bool {
+		movies[key.(uuid.UUID)] = value.(entity.Movie)
+		return true
+	})
+	idx := r.index()
+
+	seen := map[uuid.UUID]bool{}
+	walk := func(start uuid.UUID) Component {
+		var c Component
+		seen[start] = true
+		for queue := []uuid.UUID{start}; len(queue) > 0; queue = queue[1:] {
+			id := queue[0]
+			var linked []uuid.UUID
+			if ch, ok := characters[id]; ok {
+				c.Characters = append(c.Characters, ch)
+				linked = idx.movies[id]
+			} else if m, ok := movies[id]; ok {
+				c.Movies = append(c.Movies, m)
+				linked = idx.characters[id]
+			}
+			for _, other := range linked {
+				if !seen[other] {
+					seen[other] = true
+					queue = append(queue, other)
+				}
+			}
+		}
+		slices.SortFunc(c.Characters, byName)
+		slices.SortFunc(c.Movies, byYear)
+		return c
+	}
+	var result []Component
+	for id := range characters {
+		if !seen[id] {
+			result = append(result, walk(id))
+		}
+	}
+	for id := range movies {
+		if !seen[id] {
+			result = append(result, walk(id))
+		}
+	}
+	slices.SortFunc(result, func(a, b Component) int {
+		if n := cmp.Compare(b.size(), a.size()); n != 0 {
+			return n
+		}
+		if len(a.Characters) > 0 && len(b.Characters) > 0 {
+			return byName(a.Characters[0], b.Characters[0])
+		}
+		if len(a.Movies) > 0 && len(b.Movies) > 0 {
+			return byYear(a.Movies[0], b.Movies[0])
+		}
+		return cmp.Compare(len(b.Characters), len(a.Characters))
+	})
+	logging.FromContext(ctx).Debug("components", zap.Int("count", len(result)))
+	return result
+}
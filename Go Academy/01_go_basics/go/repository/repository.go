@@ -1,69 +1,151 @@
 package repository
 
 import (
-	"errors"
+	"cmp"
+	"context"
 	"fmt"
-	"log"
+	"slices"
+	"strings"
+	"time"
 
+	"example.com/go_basics/go/audit"
 	"example.com/go_basics/go/db"
 	"example.com/go_basics/go/entity"
+	"example.com/go_basics/go/events"
+	"example.com/go_basics/go/logging"
+	"example.com/go_basics/go/metrics"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
 )
 
 type Repository struct {
-	DB *db.MemoryDB
+	DB      *db.MemoryDB
+	Metrics *metrics.Metrics
+	// Events receives every change, nil drops them.
+	Events *events.Bus
+	// Audit records every change with its actor, nil drops them.
+	Audit *audit.Log
 }
 
-func New(db *db.MemoryDB) *Repository {
-	return &Repository{DB: db}
+var tracer = otel.Tracer("example.com/go_basics/go/repository")
+
+func New(db *db.MemoryDB, m *metrics.Metrics, bus *events.Bus, log *audit.Log) *Repository {
+	return &Repository{DB: db, Metrics: m, Events: bus, Audit: log}
+}
+
+// Changes returns the count of changes made so far. A result computed from
+// a Snapshot is stale once this differs from its Changes.
+func (r *Repository) Changes() uint64 {
+	return r.DB.Changes.Load()
 }
 
-func (r *Repository) CreateMovie(title string, year int) (entity.Movie, error) {
+// publish counts a change and sends its event. The caller holds Writes.
+func (r *Repository) publish(typ events.Type, data any) {
+	r.DB.Changes.Add(1)
+	r.Events.Publish(typ, data)
+}
+
+// observe starts the span of a repository operation. The returned func ends
+// the span and records the duration.
+func (r *Repository) observe(ctx context.Context, operation string) (context.Context, func()) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, "repository."+operation)
+	return ctx, func() {
+		span.End()
+		r.Metrics.ObserveRepository(operation, start)
+	}
+}
+
+func (r *Repository) CreateMovie(ctx context.Context, title string, year int) (entity.Movie, error) {
+	ctx, end := r.observe(ctx, "CreateMovie")
+	defer end()
+	r.DB.Writes.RLock()
+	defer r.DB.Writes.RUnlock()
 	if title == "" {
-		return entity.Movie{}, errors.New("movie title cannot be empty")
+		return entity.Movie{}, fmt.Errorf("%w: movie title cannot be empty", ErrInvalidInput)
 	}
 	movie := entity.NewMovie(entity.WithTitle(title), entity.WithYear(year))
 	r.DB.Movies.Store(movie.ID, movie)
-	log.Printf("Movie added: %s (%d) [ID: %s]", title, year, movie.ID)
+	r.publish(events.MovieCreated, movie)
+	r.Audit.Append(auditEntry(ctx, events.MovieCreated, movie.ID, nil, movie))
+	logging.FromContext(ctx).Debug("movie added", zap.Stringer("movie_id", movie.ID), zap.String("title", title), zap.Int("year", year))
 	return movie, nil
 }
 
-func (r *Repository) CreateCharacter(name string) (entity.Character, error) {
+func (r *Repository) CreateCharacter(ctx context.Context, name string) (entity.Character, error) {
+	ctx, end := r.observe(ctx, "CreateCharacter")
+	defer end()
+	r.DB.Writes.RLock()
+	defer r.DB.Writes.RUnlock()
 	if name == "" {
-		return entity.Character{}, errors.New("character name cannot be empty")
+		return entity.Character{}, fmt.Errorf("%w: character name cannot be empty", ErrInvalidInput)
 	}
 	character := entity.NewCharacter(entity.WithName(name))
 	r.DB.Characters.Store(character.ID, character)
-	log.Printf("Character added: %s [ID: %s]", name, character.ID)
+	r.publish(events.CharacterCreated, character)
+	r.Audit.Append(auditEntry(ctx, events.CharacterCreated, character.ID, nil, character))
+	logging.FromContext(ctx).Debug("character added", zap.Stringer("character_id", character.ID), zap.String("name", name))
 	return character, nil
 }
 
-func (r *Repository) AddAppearance(movieID, characterID uuid.UUID) error {
+func (r *Repository) AddAppearance(ctx context.Context, movieID, characterID uuid.UUID) error {
+	ctx, end := r.observe(ctx, "AddAppearance")
+	defer end()
+	r.DB.Writes.RLock()
+	defer r.DB.Writes.RUnlock()
 	mRaw, ok := r.DB.Movies.Load(movieID)
 	if !ok {
-		return fmt.Errorf("movie not found [ID: %s]", movieID)
+		return fmt.Errorf("%w [ID: %s]", ErrMovieNotFound, movieID)
 	}
 	cRaw, ok := r.DB.Characters.Load(characterID)
 	if !ok {
-		return fmt.Errorf("character not found [ID: %s]", characterID)
+		return fmt.Errorf("%w [ID: %s]", ErrCharacterNotFound, characterID)
 	}
 	movie := mRaw.(entity.Movie)
 	character := cRaw.(entity.Character)
 
-	r.DB.Mutex.Lock()
-	r.DB.Appearances = append(r.DB.Appearances, entity.New(
+	appearance := entity.New(
 		entity.WithMovieId(movieID),
 		entity.WithCharacterId(characterID),
-	))
+	)
+	r.DB.Mutex.Lock()
+	r.DB.Appearances = append(r.DB.Appearances, appearance)
 	r.DB.Mutex.Unlock()
+	r.publish(events.AppearanceLinked, appearance)
+	r.Audit.Append(linkEntry(ctx, events.AppearanceLinked, appearance))
 
-	log.Printf("Linked character '%s' to movie '%s'", character.Name, movie.Title)
+	logging.FromContext(ctx).Debug("appearance added",
+		zap.Stringer("movie_id", movieID), zap.String("title", movie.Title),
+		zap.Stringer("character_id", characterID), zap.String("name", character.Name))
 	return nil
 }
 
-func (r *Repository) GetCharactersByMovie(movieID uuid.UUID) ([]entity.Character, error) {
+func (r *Repository) GetMovie(ctx context.Context, id uuid.UUID) (entity.Movie, error) {
+	_, end := r.observe(ctx, "GetMovie")
+	defer end()
+	mRaw, ok := r.DB.Movies.Load(id)
+	if !ok {
+		return entity.Movie{}, fmt.Errorf("%w [ID: %s]", ErrMovieNotFound, id)
+	}
+	return mRaw.(entity.Movie), nil
+}
+
+func (r *Repository) GetCharacter(ctx context.Context, id uuid.UUID) (entity.Character, error) {
+	_, end := r.observe(ctx, "GetCharacter")
+	defer end()
+	cRaw, ok := r.DB.Characters.Load(id)
+	if !ok {
+		return entity.Character{}, fmt.Errorf("%w [ID: %s]", ErrCharacterNotFound, id)
+	}
+	return cRaw.(entity.Character), nil
+}
+
+func (r *Repository) GetCharactersByMovie(ctx context.Context, movieID uuid.UUID) ([]entity.Character, error) {
+	ctx, end := r.observe(ctx, "GetCharactersByMovie")
+	defer end()
 	if _, ok := r.DB.Movies.Load(movieID); !ok {
-		return nil, fmt.Errorf("movie not found [ID: %s]", movieID)
+		return nil, fmt.Errorf("%w [ID: %s]", ErrMovieNotFound, movieID)
 	}
 	var result []entity.Character
 	r.DB.Mutex.Lock()
@@ -77,13 +159,15 @@ func (r *Repository) GetCharactersByMovie(movieID uuid.UUID) ([]entity.Character
 		}
 	}
 	r.DB.Mutex.Unlock()
-	log.Printf("Retrieved %d characters for movie ID %s", len(result), movieID)
+	logging.FromContext(ctx).Debug("characters by movie", zap.Stringer("movie_id", movieID), zap.Int("count", len(result)))
 	return result, nil
 }
 
-func (r *Repository) GetMoviesByCharacter(characterID uuid.UUID) ([]entity.Movie, error) {
+func (r *Repository) GetMoviesByCharacter(ctx context.Context, characterID uuid.UUID) ([]entity.Movie, error) {
+	ctx, end := r.observe(ctx, "GetMoviesByCharacter")
+	defer end()
 	if _, ok := r.DB.Characters.Load(characterID); !ok {
-		return nil, fmt.Errorf("character not found [ID: %s]", characterID)
+		return nil, fmt.Errorf("%w [ID: %s]", ErrCharacterNotFound, characterID)
 	}
 	var result []entity.Movie
 	r.DB.Mutex.Lock()
@@ -97,11 +181,58 @@ func (r *Repository) GetMoviesByCharacter(characterID uuid.UUID) ([]entity.Movie
 		}
 	}
 	r.DB.Mutex.Unlock()
-	log.Printf("Retrieved %d movies for character ID %s", len(result), characterID)
+	logging.FromContext(ctx).Debug("movies by character", zap.Stringer("character_id", characterID), zap.Int("count", len(result)))
 	return result, nil
 }
 
-func (r *Repository) GetCharactersByMovieTitle(title string) ([]entity.Character, error) {
+// GetCharactersByMovies returns the characters of many movies in one pass over
+// the appearances. Every requested movie has an entry, empty for unknown ones.
+func (r *Repository) GetCharactersByMovies(ctx context.Context, movieIDs []uuid.UUID) map[uuid.UUID][]entity.Character {
+	ctx, end := r.observe(ctx, "GetCharactersByMovies")
+	defer end()
+	result := make(map[uuid.UUID][]entity.Character, len(movieIDs))
+	for _, id := range movieIDs {
+		result[id] = []entity.Character{}
+	}
+	r.DB.Mutex.Lock()
+	for _, a := range r.DB.Appearances {
+		if chars, ok := result[a.MovieID]; ok {
+			if cRaw, ok := r.DB.Characters.Load(a.CharacterID); ok {
+				result[a.MovieID] = append(chars, cRaw.(entity.Character))
+			}
+		}
+	}
+	r.DB.Mutex.Unlock()
+	logging.FromContext(ctx).Debug("characters by movies", zap.Int("movies", len(movieIDs)))
+	return result
+}
+
+// GetMoviesByCharacters returns the movies of many characters in one pass over
+// the appearances. Every requested character has an entry, empty for unknown
+// ones.
+func (r *Repository) GetMoviesByCharacters(ctx context.Context, characterIDs []uuid.UUID) map[uuid.UUID][]entity.Movie {
+	ctx, end := r.observe(ctx, "GetMoviesByCharacters")
+	defer end()
+	result := make(map[uuid.UUID][]entity.Movie, len(characterIDs))
+	for _, id := range characterIDs {
+		result[id] = []entity.Movie{}
+	}
+	r.DB.Mutex.Lock()
+	for _, a := range r.DB.Appearances {
+		if movies, ok := result[a.CharacterID]; ok {
+			if mRaw, ok := r.DB.Movies.Load(a.MovieID); ok {
+				result[a.CharacterID] = append(movies, mRaw.(entity.Movie))
+			}
+		}
+	}
+	r.DB.Mutex.Unlock()
+	logging.FromContext(ctx).Debug("movies by characters", zap.Int("characters", len(characterIDs)))
+	return result
+}
+
+func (r *Repository) GetCharactersByMovieTitle(ctx context.Context, title string) ([]entity.Character, error) {
+	ctx, end := r.observe(ctx, "GetCharactersByMovieTitle")
+	defer end()
 	var movieID uuid.UUID
 	found := false
 	r.DB.Movies.Range(func(_, value any) bool {
@@ -114,14 +245,14 @@ func (r *Repository) GetCharactersByMovieTitle(title string) ([]entity.Character
 		return true
 	})
 	if !found {
-		log.Printf("No movie found with title: %s", title)
-		return nil, fmt.Errorf("movie not found with title: %s", title)
+		return nil, fmt.Errorf("%w with title: %s", ErrMovieNotFound, title)
 	}
-	log.Printf("Searching characters for movie title: %s", title)
-	return r.GetCharactersByMovie(movieID)
+	return r.GetCharactersByMovie(ctx, movieID)
 }
 
-func (r *Repository) GetMovieTitlesByCharacterName(name string) ([]string, error) {
+func (r *Repository) GetMovieTitlesByCharacterName(ctx context.Context, name string) ([]string, error) {
+	ctx, end := r.observe(ctx, "GetMovieTitlesByCharacterName")
+	defer end()
 	var characterID uuid.UUID
 	found := false
 	r.DB.Characters.Range(func(_, value any) bool {
@@ -134,10 +265,9 @@ func (r *Repository) GetMovieTitlesByCharacterName(name string) ([]string, error
 		return true
 	})
 	if !found {
-		log.Printf("No character found with name: %s", name)
-		return nil, fmt.Errorf("character not found with name: %s", name)
+		return nil, fmt.Errorf("%w with name: %s", ErrCharacterNotFound, name)
 	}
-	movies, err := r.GetMoviesByCharacter(characterID)
+	movies, err := r.GetMoviesByCharacter(ctx, characterID)
 	if err != nil {
 		return nil, err
 	}
@@ -145,91 +275,273 @@ func (r *Repository) GetMovieTitlesByCharacterName(name string) ([]string, error
 	for _, m := range movies {
 		titles = append(titles, m.Title)
 	}
-	log.Printf("Found %d movies for character '%s'", len(titles), name)
+	logging.FromContext(ctx).Debug("movie titles by character", zap.String("name", name), zap.Int("count", len(titles)))
 	return titles, nil
 }
 
-func (r *Repository) ListAllMovies() (map[uuid.UUID]entity.Movie, error) {
+func (r *Repository) ListAllMovies(ctx context.Context) (map[uuid.UUID]entity.Movie, error) {
+	ctx, end := r.observe(ctx, "ListAllMovies")
+	defer end()
 	result := make(map[uuid.UUID]entity.Movie)
 	r.DB.Movies.Range(func(key, value any) bool {
 		id := key.(uuid.UUID)
 		m := value.(entity.Movie)
 		result[id] = m
-		log.Printf("- %s (%d) [ID: %s]", m.Title, m.Year, m.ID)
 		return true
 	})
 	if len(result) == 0 {
-		log.Println("No movies found in the database.")
-		return nil, errors.New("no movies available")
+		return nil, ErrNoMovies
 	}
-	log.Println("All movies listed.")
+	logging.FromContext(ctx).Debug("movies listed", zap.Int("count", len(result)))
 	return result, nil
 }
 
-func (r *Repository) ListAllCharacters() (map[uuid.UUID]entity.Character, error) {
+func (r *Repository) ListAllCharacters(ctx context.Context) (map[uuid.UUID]entity.Character, error) {
+	ctx, end := r.observe(ctx, "ListAllCharacters")
+	defer end()
 	result := make(map[uuid.UUID]entity.Character)
 	r.DB.Characters.Range(func(key, value any) bool {
 		id := key.(uuid.UUID)
 		c := value.(entity.Character)
 		result[id] = c
-		log.Printf("- %s [ID: %s]", c.Name, c.ID)
 		return true
 	})
 	if len(result) == 0 {
-		log.Println("No characters found in the database.")
-		return nil, errors.New("no characters available")
+		return nil, ErrNoCharacters
 	}
-	log.Println("All characters listed.")
+	logging.FromContext(ctx).Debug("characters listed", zap.Int("count", len(result)))
 	return result, nil
 }
 
-func (r *Repository) UpdateCharacter(id uuid.UUID, newName string) error {
+// Snapshot is a consistent copy of the whole store.
+type Snapshot struct {
+	Movies      []entity.Movie     // by title
+	Characters  []entity.Character // by name
+	Appearances []entity.Appearance
+	// Changes is the count of changes the snapshot holds.
+	Changes uint64
+}
+
+// Snapshot copies the store while changes wait, so no change is half in
+// it and the appearances only name movies and characters it holds.
+func (r *Repository) Snapshot(ctx context.Context) Snapshot {
+	ctx, end := r.observe(ctx, "Snapshot")
+	defer end()
+	var s Snapshot
+	r.DB.Writes.Lock()
+	r.DB.Movies.Range(func(_, value any) bool {
+		s.Movies = append(s.Movies, value.(entity.Movie))
+		return true
+	})
+	r.DB.Characters.Range(func(_, value any) bool {
+		s.Characters = append(s.Characters, value.(entity.Character))
+		return true
+	})
+	r.DB.Mutex.Lock()
+	s.Appearances = slices.Clone(r.DB.Appearances)
+	r.DB.Mutex.Unlock()
+	s.Changes = r.DB.Changes.Load()
+	r.DB.Writes.Unlock()
+
+	slices.SortFunc(s.Movies, func(a, b entity.Movie) int {
+		return cmp.Or(cmp.Compare(a.Title, b.Title), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	slices.SortFunc(s.Characters, func(a, b entity.Character) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	logging.FromContext(ctx).Debug("snapshot taken", zap.Int("movies", len(s.Movies)), zap.Int("characters", len(s.Characters)), zap.Int("appearances", len(s.Appearances)))
+	return s
+}
+
+// AnyVersion makes an update or delete skip the version check.
+const AnyVersion int64 = 0
+
+func checkVersion(kind string, id uuid.UUID, current, expected int64) error {
+	if expected != AnyVersion && current != expected {
+		return fmt.Errorf("%w: %s %s is at version %d, not %d", ErrVersionMismatch, kind, id, current, expected)
+	}
+	return nil
+}
+
+// UpdateMovie changes the title and year of the movie at version. Empty
+// values keep the current ones.
+func (r *Repository) UpdateMovie(ctx context.Context, id uuid.UUID, title string, year int, version int64) (entity.Movie, error) {
+	ctx, end := r.observe(ctx, "UpdateMovie")
+	defer end()
+	r.DB.Writes.RLock()
+	defer r.DB.Writes.RUnlock()
+	for {
+		mRaw, ok := r.DB.Movies.Load(id)
+		if !ok {
+			return entity.Movie{}, fmt.Errorf("%w [ID: %s]", ErrMovieNotFound, id)
+		}
+		current := mRaw.(entity.Movie)
+		if err := checkVersion("movie", id, current.Version, version); err != nil {
+			return entity.Movie{}, err
+		}
+		movie := current
+		if title != "" {
+			movie.Title = title
+		}
+		if year != 0 {
+			movie.Year = year
+		}
+		movie.Version++
+		// When a concurrent update won the race, check its version again.
+		if r.DB.Movies.CompareAndSwap(id, current, movie) {
+			r.publish(events.MovieUpdated, movie)
+			r.Audit.Append(auditEntry(ctx, events.MovieUpdated, id, current, movie))
+			logging.FromContext(ctx).Debug("movie updated", zap.Stringer("movie_id", id), zap.Int64("version", movie.Version))
+			return movie, nil
+		}
+	}
+}
+
+// UpdateCharacter renames the character at version.
+func (r *Repository) UpdateCharacter(ctx context.Context, id uuid.UUID, newName string, version int64) (entity.Character, error) {
+	ctx, end := r.observe(ctx, "UpdateCharacter")
+	defer end()
+	r.DB.Writes.RLock()
+	defer r.DB.Writes.RUnlock()
 	if newName == "" {
-		return errors.New("new character name cannot be empty")
+		return entity.Character{}, fmt.Errorf("%w: new character name cannot be empty", ErrInvalidInput)
 	}
-	cRaw, ok := r.DB.Characters.Load(id)
-	if !ok {
-		return fmt.Errorf("character not found [ID: %s]", id)
+	for {
+		cRaw, ok := r.DB.Characters.Load(id)
+		if !ok {
+			return entity.Character{}, fmt.Errorf("%w [ID: %s]", ErrCharacterNotFound, id)
+		}
+		current := cRaw.(entity.Character)
+		if err := checkVersion("character", id, current.Version, version); err != nil {
+			return entity.Character{}, err
+		}
+		character := current
+		character.Name = newName
+		character.Version++
+		if r.DB.Characters.CompareAndSwap(id, current, character) {
+			r.publish(events.CharacterUpdated, character)
+			r.Audit.Append(auditEntry(ctx, events.CharacterUpdated, id, current, character))
+			logging.FromContext(ctx).Debug("character updated", zap.Stringer("character_id", id), zap.String("name", newName), zap.Int64("version", character.Version))
+			return character, nil
+		}
 	}
-	character := cRaw.(entity.Character)
-	character.Name = newName
-	r.DB.Characters.Store(id, character)
-	log.Printf("Character updated: %s [ID: %s]", newName, id)
-	return nil
 }
 
-func (r *Repository) DeleteMovie(id uuid.UUID) error {
-	if _, ok := r.DB.Movies.Load(id); !ok {
-		return fmt.Errorf("movie not found [ID: %s]", id)
+// DeleteMovie moves the movie at version to the trash, together with its
+// appearances.
+func (r *Repository) DeleteMovie(ctx context.Context, id uuid.UUID, version int64) error {
+	ctx, end := r.observe(ctx, "DeleteMovie")
+	defer end()
+	r.DB.Writes.RLock()
+	defer r.DB.Writes.RUnlock()
+	var mRaw any
+	for {
+		var ok bool
+		mRaw, ok = r.DB.Movies.Load(id)
+		if !ok {
+			return fmt.Errorf("%w [ID: %s]", ErrMovieNotFound, id)
+		}
+		if err := checkVersion("movie", id, mRaw.(entity.Movie).Version, version); err != nil {
+			return err
+		}
+		if r.DB.Movies.CompareAndDelete(id, mRaw) {
+			break
+		}
 	}
-	r.DB.Movies.Delete(id)
-	r.DB.Mutex.Lock()
-	var updated []entity.Appearance
-	for _, a := range r.DB.Appearances {
-		if a.MovieID != id {
-			updated = append(updated, a)
+	movie := mRaw.(entity.Movie)
+	movie.DeletedAt = deletedAt()
+	r.unlink(ctx, func(a entity.Appearance) bool { return a.MovieID == id }, &db.Deleted{Movie: &movie})
+	r.publish(events.MovieDeleted, movie)
+	r.Audit.Append(auditEntry(ctx, events.MovieDeleted, id, mRaw, movie))
+	logging.FromContext(ctx).Debug("movie moved to the trash", zap.Stringer("movie_id", id))
+	return nil
+}
+
+// DeleteCharacter moves the character at version to the trash, together
+// with its appearances.
+func (r *Repository) DeleteCharacter(ctx context.Context, id uuid.UUID, version int64) error {
+	ctx, end := r.observe(ctx, "DeleteCharacter")
+	defer end()
+	r.DB.Writes.RLock()
+	defer r.DB.Writes.RUnlock()
+	var cRaw any
+	for {
+		var ok bool
+		cRaw, ok = r.DB.Characters.Load(id)
+		if !ok {
+			return fmt.Errorf("%w [ID: %s]", ErrCharacterNotFound, id)
+		}
+		if err := checkVersion("character", id, cRaw.(entity.Character).Version, version); err != nil {
+			return err
+		}
+		if r.DB.Characters.CompareAndDelete(id, cRaw) {
+			break
 		}
 	}
-	r.DB.Appearances = updated
-	r.DB.Mutex.Unlock()
-	log.Printf("Movie deleted [ID: %s]", id)
+	character := cRaw.(entity.Character)
+	character.DeletedAt = deletedAt()
+	r.unlink(ctx, func(a entity.Appearance) bool { return a.CharacterID == id }, &db.Deleted{Character: &character})
+	r.publish(events.CharacterDeleted, character)
+	r.Audit.Append(auditEntry(ctx, events.CharacterDeleted, id, cRaw, character))
+	logging.FromContext(ctx).Debug("character moved to the trash", zap.Stringer("character_id", id))
 	return nil
 }
 
-func (r *Repository) DeleteCharacter(id uuid.UUID) error {
-	if _, ok := r.DB.Characters.Load(id); !ok {
-		return fmt.Errorf("character not found [ID: %s]", id)
+// RemoveAppearance unlinks a character from a movie.
+func (r *Repository) RemoveAppearance(ctx context.Context, movieID, characterID uuid.UUID) error {
+	ctx, end := r.observe(ctx, "RemoveAppearance")
+	defer end()
+	r.DB.Writes.RLock()
+	defer r.DB.Writes.RUnlock()
+	removed := r.unlink(ctx, func(a entity.Appearance) bool {
+		return a.MovieID == movieID && a.CharacterID == characterID
+	}, nil)
+	if len(removed) == 0 {
+		return fmt.Errorf("%w [movie: %s, character: %s]", ErrAppearanceNotFound, movieID, characterID)
 	}
-	r.DB.Characters.Delete(id)
+	logging.FromContext(ctx).Debug("appearance removed", zap.Stringer("movie_id", movieID), zap.Stringer("character_id", characterID))
+	return nil
+}
+
+// unlink drops the appearances matching remove and publishes their removal.
+// A deleted movie or character goes to the trash along with them.
+func (r *Repository) unlink(ctx context.Context, remove func(entity.Appearance) bool, deleted *db.Deleted) []entity.Appearance {
 	r.DB.Mutex.Lock()
-	var updated []entity.Appearance
+	var kept, removed []entity.Appearance
 	for _, a := range r.DB.Appearances {
-		if a.CharacterID != id {
-			updated = append(updated, a)
+		if remove(a) {
+			removed = append(removed, a)
+		} else {
+			kept = append(kept, a)
 		}
 	}
-	r.DB.Appearances = updated
+	r.DB.Appearances = kept
+	if deleted != nil {
+		deleted.Appearances = removed
+		r.DB.Trash[deleted.ID()] = *deleted
+	}
 	r.DB.Mutex.Unlock()
-	log.Printf("Character deleted [ID: %s]", id)
-	return nil
+	for _, a := range removed {
+		r.publish(events.AppearanceUnlinked, a)
+		r.Audit.Append(linkEntry(ctx, events.AppearanceUnlinked, a))
+	}
+	return removed
+}
+
+// auditEntry describes a change of a movie, character or franchise for the
+// audit log.
+func auditEntry(ctx context.Context, op events.Type, id uuid.UUID, before, after any) audit.Entry {
+	entityType, _, _ := strings.Cut(string(op), ".")
+	return audit.NewEntry(ctx, string(op), entityType, id, before, after)
+}
+
+// linkEntry describes a linked or unlinked appearance for the audit log.
+func linkEntry(ctx context.Context, op events.Type, a entity.Appearance) audit.Entry {
+	var before, after any = a, nil
+	if op == events.AppearanceLinked {
+		before, after = nil, a
+	}
+	e := audit.NewEntry(ctx, string(op), audit.Appearance, a.MovieID, before, after)
+	e.RelatedID = &a.CharacterID
+	return e
 }
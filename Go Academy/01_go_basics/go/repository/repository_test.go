@@ -1,21 +1,29 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
+	"example.com/go_basics/go/audit"
+	"example.com/go_basics/go/config"
 	"example.com/go_basics/go/db"
 	"example.com/go_basics/go/entity"
+	"example.com/go_basics/go/events"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateMovieAndCharacter(t *testing.T) {
 	mem := db.New()
-	repo := New(mem)
+	repo := New(mem, nil, nil, nil)
 
-	movie, err := repo.CreateMovie("Shrek", 2001)
+	movie, err := repo.CreateMovie(t.Context(), "Shrek", 2001)
 	assert.NoError(t, err)
-	character, err := repo.CreateCharacter("Shrek")
+	character, err := repo.CreateCharacter(t.Context(), "Shrek")
 	assert.NoError(t, err)
 
 	assert.Equal(t, "Shrek", movie.Title)
@@ -24,132 +32,171 @@ func TestCreateMovieAndCharacter(t *testing.T) {
 	assert.NotEmpty(t, movie.ID)
 	assert.NotEmpty(t, character.ID)
 
-	_, err = repo.CreateMovie("", 2001)
+	_, err = repo.CreateMovie(t.Context(), "", 2001)
 	assert.Error(t, err)
 
-	_, err = repo.CreateCharacter("")
+	_, err = repo.CreateCharacter(t.Context(), "")
 	assert.Error(t, err)
 }
 
 func TestAddAppearanceAndGetCharactersByMovie(t *testing.T) {
 	mem := db.New()
-	repo := New(mem)
+	repo := New(mem, nil, nil, nil)
 
-	movie, _ := repo.CreateMovie("Shrek 2", 2004)
-	character, _ := repo.CreateCharacter("Donkey")
+	movie, _ := repo.CreateMovie(t.Context(), "Shrek 2", 2004)
+	character, _ := repo.CreateCharacter(t.Context(), "Donkey")
 
-	err := repo.AddAppearance(movie.ID, character.ID)
+	err := repo.AddAppearance(t.Context(), movie.ID, character.ID)
 	assert.NoError(t, err)
 
-	chars, err := repo.GetCharactersByMovie(movie.ID)
+	chars, err := repo.GetCharactersByMovie(t.Context(), movie.ID)
 	assert.NoError(t, err)
 	assert.Len(t, chars, 1)
 	assert.Equal(t, "Donkey", chars[0].Name)
 
 	fakeID := uuid.New()
-	err = repo.AddAppearance(fakeID, character.ID)
+	err = repo.AddAppearance(t.Context(), fakeID, character.ID)
 	assert.Error(t, err)
 
-	_, err = repo.GetCharactersByMovie(fakeID)
+	_, err = repo.GetCharactersByMovie(t.Context(), fakeID)
 	assert.Error(t, err)
 }
 
+func TestRemoveAppearance(t *testing.T) {
+	repo := New(db.New(), nil, nil, nil)
+
+	movie, _ := repo.CreateMovie(t.Context(), "Shrek 2", 2004)
+	character, _ := repo.CreateCharacter(t.Context(), "Puss in Boots")
+	require.NoError(t, repo.AddAppearance(t.Context(), movie.ID, character.ID))
+
+	require.NoError(t, repo.RemoveAppearance(t.Context(), movie.ID, character.ID))
+	chars, err := repo.GetCharactersByMovie(t.Context(), movie.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, chars)
+
+	err = repo.RemoveAppearance(t.Context(), movie.ID, character.ID)
+	assert.ErrorIs(t, err, ErrAppearanceNotFound)
+}
+
 func TestGetMoviesByCharacter(t *testing.T) {
 	mem := db.New()
-	repo := New(mem)
+	repo := New(mem, nil, nil, nil)
 
-	m1, _ := repo.CreateMovie("Shrek", 2001)
-	m2, _ := repo.CreateMovie("Shrek 2", 2004)
-	c, _ := repo.CreateCharacter("Fiona")
+	m1, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	m2, _ := repo.CreateMovie(t.Context(), "Shrek 2", 2004)
+	c, _ := repo.CreateCharacter(t.Context(), "Fiona")
 
-	repo.AddAppearance(m1.ID, c.ID)
-	repo.AddAppearance(m2.ID, c.ID)
+	repo.AddAppearance(t.Context(), m1.ID, c.ID)
+	repo.AddAppearance(t.Context(), m2.ID, c.ID)
 
-	movies, err := repo.GetMoviesByCharacter(c.ID)
+	movies, err := repo.GetMoviesByCharacter(t.Context(), c.ID)
 	assert.NoError(t, err)
 	assert.Len(t, movies, 2)
 	assert.Contains(t, []string{movies[0].Title, movies[1].Title}, "Shrek")
 	assert.Contains(t, []string{movies[0].Title, movies[1].Title}, "Shrek 2")
 
-	_, err = repo.GetMoviesByCharacter(uuid.New())
+	_, err = repo.GetMoviesByCharacter(t.Context(), uuid.New())
 	assert.Error(t, err)
 }
 
+func TestBatchLookups(t *testing.T) {
+	repo := New(db.New(), nil, nil, nil)
+
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	shrek2, _ := repo.CreateMovie(t.Context(), "Shrek 2", 2004)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	puss, _ := repo.CreateCharacter(t.Context(), "Puss in Boots")
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek.ID, donkey.ID))
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek2.ID, donkey.ID))
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek2.ID, puss.ID))
+	unknown := uuid.New()
+
+	chars := repo.GetCharactersByMovies(t.Context(), []uuid.UUID{shrek.ID, shrek2.ID, unknown})
+	assert.Equal(t, []entity.Character{donkey}, chars[shrek.ID])
+	assert.Equal(t, []entity.Character{donkey, puss}, chars[shrek2.ID])
+	assert.Equal(t, []entity.Character{}, chars[unknown])
+
+	movies := repo.GetMoviesByCharacters(t.Context(), []uuid.UUID{donkey.ID, puss.ID})
+	assert.Equal(t, []entity.Movie{shrek, shrek2}, movies[donkey.ID])
+	assert.Equal(t, []entity.Movie{shrek2}, movies[puss.ID])
+}
+
 func TestGetCharactersByMovieTitle(t *testing.T) {
 	mem := db.New()
-	repo := New(mem)
+	repo := New(mem, nil, nil, nil)
 
-	m, _ := repo.CreateMovie("The Lion King", 1994)
-	c, _ := repo.CreateCharacter("Simba")
-	repo.AddAppearance(m.ID, c.ID)
+	m, _ := repo.CreateMovie(t.Context(), "The Lion King", 1994)
+	c, _ := repo.CreateCharacter(t.Context(), "Simba")
+	repo.AddAppearance(t.Context(), m.ID, c.ID)
 
-	chars, err := repo.GetCharactersByMovieTitle("The Lion King")
+	chars, err := repo.GetCharactersByMovieTitle(t.Context(), "The Lion King")
 	assert.NoError(t, err)
 	assert.Len(t, chars, 1)
 	assert.Equal(t, "Simba", chars[0].Name)
 
-	_, err = repo.GetCharactersByMovieTitle("Unknown")
+	_, err = repo.GetCharactersByMovieTitle(t.Context(), "Unknown")
 	assert.Error(t, err)
 }
 
 func TestGetMovieTitlesByCharacterName(t *testing.T) {
 	mem := db.New()
-	repo := New(mem)
+	repo := New(mem, nil, nil, nil)
 
-	m1, _ := repo.CreateMovie("Shrek", 2001)
-	m2, _ := repo.CreateMovie("Shrek 2", 2004)
-	c, _ := repo.CreateCharacter("Puss in Boots")
+	m1, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	m2, _ := repo.CreateMovie(t.Context(), "Shrek 2", 2004)
+	c, _ := repo.CreateCharacter(t.Context(), "Puss in Boots")
 
-	repo.AddAppearance(m1.ID, c.ID)
-	repo.AddAppearance(m2.ID, c.ID)
+	repo.AddAppearance(t.Context(), m1.ID, c.ID)
+	repo.AddAppearance(t.Context(), m2.ID, c.ID)
 
-	titles, err := repo.GetMovieTitlesByCharacterName("Puss in Boots")
+	titles, err := repo.GetMovieTitlesByCharacterName(t.Context(), "Puss in Boots")
 	assert.NoError(t, err)
 	assert.Len(t, titles, 2)
 	assert.Contains(t, titles, "Shrek")
 	assert.Contains(t, titles, "Shrek 2")
 
-	_, err = repo.GetMovieTitlesByCharacterName("Scar")
+	_, err = repo.GetMovieTitlesByCharacterName(t.Context(), "Scar")
 	assert.Error(t, err)
 }
 
 func TestListAllMoviesAndCharacters(t *testing.T) {
 	mem := db.New()
-	repo := New(mem)
+	repo := New(mem, nil, nil, nil)
 
-	repo.CreateMovie("Shrek", 2001)
-	repo.CreateMovie("Shrek 2", 2004)
-	repo.CreateMovie("The Lion King", 1994)
+	repo.CreateMovie(t.Context(), "Shrek", 2001)
+	repo.CreateMovie(t.Context(), "Shrek 2", 2004)
+	repo.CreateMovie(t.Context(), "The Lion King", 1994)
 
-	repo.CreateCharacter("Shrek")
-	repo.CreateCharacter("Donkey")
-	repo.CreateCharacter("Simba")
+	repo.CreateCharacter(t.Context(), "Shrek")
+	repo.CreateCharacter(t.Context(), "Donkey")
+	repo.CreateCharacter(t.Context(), "Simba")
 
-	movies, err := repo.ListAllMovies()
+	movies, err := repo.ListAllMovies(t.Context())
 	assert.NoError(t, err)
 	assert.Len(t, movies, 3)
 
-	chars, err := repo.ListAllCharacters()
+	chars, err := repo.ListAllCharacters(t.Context())
 	assert.NoError(t, err)
 	assert.Len(t, chars, 3)
 
 	memEmpty := db.New()
-	repoEmpty := New(memEmpty)
+	repoEmpty := New(memEmpty, nil, nil, nil)
 
-	_, err = repoEmpty.ListAllMovies()
-	assert.Error(t, err)
+	_, err = repoEmpty.ListAllMovies(t.Context())
+	assert.ErrorIs(t, err, ErrNotFound)
 
-	_, err = repoEmpty.ListAllCharacters()
+	_, err = repoEmpty.ListAllCharacters(t.Context())
 	assert.Error(t, err)
 }
 
 func TestUpdateCharacter(t *testing.T) {
 	mem := db.New()
-	repo := New(mem)
+	repo := New(mem, nil, nil, nil)
 
-	char, _ := repo.CreateCharacter("Donkey")
-	err := repo.UpdateCharacter(char.ID, "Donkey the Brave")
+	char, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	updated, err := repo.UpdateCharacter(t.Context(), char.ID, "Donkey the Brave", char.Version)
 	assert.NoError(t, err)
+	assert.Equal(t, char.Version+1, updated.Version)
 
 	val, ok := repo.DB.Characters.Load(char.ID)
 	assert.True(t, ok)
@@ -158,22 +205,71 @@ func TestUpdateCharacter(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "Donkey the Brave", updatedChar.Name)
 
-	err = repo.UpdateCharacter(uuid.New(), "Ghost")
-	assert.Error(t, err)
+	_, err = repo.UpdateCharacter(t.Context(), char.ID, "Donkey the Lost", char.Version)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
 
-	err = repo.UpdateCharacter(char.ID, "")
-	assert.Error(t, err)
+	_, err = repo.UpdateCharacter(t.Context(), uuid.New(), "Ghost", AnyVersion)
+	assert.ErrorIs(t, err, ErrCharacterNotFound)
+
+	_, err = repo.UpdateCharacter(t.Context(), char.ID, "", AnyVersion)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestUpdateMovie(t *testing.T) {
+	repo := New(db.New(), nil, nil, nil)
+
+	movie, _ := repo.CreateMovie(t.Context(), "Shrek", 2000)
+	updated, err := repo.UpdateMovie(t.Context(), movie.ID, "", 2001, movie.Version)
+	require.NoError(t, err)
+	assert.Equal(t, "Shrek", updated.Title)
+	assert.Equal(t, 2001, updated.Year)
+	assert.Equal(t, int64(2), updated.Version)
+
+	_, err = repo.UpdateMovie(t.Context(), movie.ID, "Shrek 2", 0, movie.Version)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+
+	_, err = repo.UpdateMovie(t.Context(), uuid.New(), "Ghost", 0, AnyVersion)
+	assert.ErrorIs(t, err, ErrMovieNotFound)
+}
+
+func TestConcurrentUpdatesKeepEveryVersion(t *testing.T) {
+	repo := New(db.New(), nil, nil, nil)
+	char, _ := repo.CreateCharacter(t.Context(), "Donkey")
+
+	// Every writer retries on a conflict, so each update has to land once.
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Go(func() {
+			for {
+				current, err := repo.GetCharacter(t.Context(), char.ID)
+				assert.NoError(t, err)
+				_, err = repo.UpdateCharacter(t.Context(), char.ID, fmt.Sprintf("Donkey %d", i), current.Version)
+				if !errors.Is(err, ErrVersionMismatch) {
+					assert.NoError(t, err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	final, err := repo.GetCharacter(t.Context(), char.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(51), final.Version)
 }
 
 func TestDeleteMovie(t *testing.T) {
 	mem := db.New()
-	repo := New(mem)
+	repo := New(mem, nil, nil, nil)
 
-	movie, _ := repo.CreateMovie("Shrek Forever After", 2010)
-	char, _ := repo.CreateCharacter("Rumpelstiltskin")
-	repo.AddAppearance(movie.ID, char.ID)
+	movie, _ := repo.CreateMovie(t.Context(), "Shrek Forever After", 2010)
+	char, _ := repo.CreateCharacter(t.Context(), "Rumpelstiltskin")
+	repo.AddAppearance(t.Context(), movie.ID, char.ID)
 
-	err := repo.DeleteMovie(movie.ID)
+	err := repo.DeleteMovie(t.Context(), movie.ID, movie.Version+1)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+
+	err = repo.DeleteMovie(t.Context(), movie.ID, movie.Version)
 	assert.NoError(t, err)
 	_, ok := repo.DB.Movies.Load(movie.ID)
 	assert.False(t, ok)
@@ -182,19 +278,19 @@ func TestDeleteMovie(t *testing.T) {
 		assert.NotEqual(t, a.MovieID, movie.ID)
 	}
 
-	err = repo.DeleteMovie(uuid.New())
-	assert.Error(t, err)
+	err = repo.DeleteMovie(t.Context(), uuid.New(), AnyVersion)
+	assert.ErrorIs(t, err, ErrMovieNotFound)
 }
 
 func TestDeleteCharacter(t *testing.T) {
 	mem := db.New()
-	repo := New(mem)
+	repo := New(mem, nil, nil, nil)
 
-	movie, _ := repo.CreateMovie("The Lion King", 1994)
-	char, _ := repo.CreateCharacter("Scar")
-	repo.AddAppearance(movie.ID, char.ID)
+	movie, _ := repo.CreateMovie(t.Context(), "The Lion King", 1994)
+	char, _ := repo.CreateCharacter(t.Context(), "Scar")
+	repo.AddAppearance(t.Context(), movie.ID, char.ID)
 
-	err := repo.DeleteCharacter(char.ID)
+	err := repo.DeleteCharacter(t.Context(), char.ID, AnyVersion)
 	assert.NoError(t, err)
 	_, ok := repo.DB.Characters.Load(char.ID)
 	assert.False(t, ok)
@@ -203,6 +299,297 @@ func TestDeleteCharacter(t *testing.T) {
 		assert.NotEqual(t, a.CharacterID, char.ID)
 	}
 
-	err = repo.DeleteCharacter(uuid.New())
+	err = repo.DeleteCharacter(t.Context(), uuid.New(), AnyVersion)
 	assert.Error(t, err)
 }
+
+func TestTrash(t *testing.T) {
+	repo := New(db.New(), nil, nil, nil)
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	shrek2, _ := repo.CreateMovie(t.Context(), "Shrek 2", 2004)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	fiona, _ := repo.CreateCharacter(t.Context(), "Fiona")
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek.ID, donkey.ID))
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek.ID, fiona.ID))
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek2.ID, donkey.ID))
+
+	require.NoError(t, repo.DeleteCharacter(t.Context(), fiona.ID, AnyVersion))
+	require.NoError(t, repo.DeleteMovie(t.Context(), shrek.ID, 1))
+	_, err := repo.GetMovie(t.Context(), shrek.ID)
+	assert.ErrorIs(t, err, ErrMovieNotFound)
+	trash := repo.ListTrash(t.Context())
+	require.Len(t, trash, 2)
+	assert.ElementsMatch(t, []uuid.UUID{shrek.ID, fiona.ID}, []uuid.UUID{trash[0].ID(), trash[1].ID()})
+	for _, d := range trash {
+		assert.Len(t, d.Appearances, 1)
+		assert.False(t, d.DeletedAt().IsZero())
+	}
+
+	_, err = repo.RestoreMovie(t.Context(), fiona.ID)
+	assert.ErrorIs(t, err, ErrMovieNotFound)
+	restored, err := repo.RestoreMovie(t.Context(), shrek.ID)
+	require.NoError(t, err)
+	assert.Nil(t, restored.DeletedAt)
+	assert.Equal(t, int64(2), restored.Version)
+	chars, _ := repo.GetCharactersByMovie(t.Context(), shrek.ID)
+	assert.Equal(t, []entity.Character{donkey}, chars)
+	_, err = repo.RestoreMovie(t.Context(), shrek.ID)
+	assert.ErrorIs(t, err, ErrMovieNotFound)
+	_, err = repo.RestoreCharacter(t.Context(), fiona.ID)
+	require.NoError(t, err)
+	chars, _ = repo.GetCharactersByMovie(t.Context(), shrek.ID)
+	assert.Len(t, chars, 2)
+
+	// An appearance whose movie is still in the trash waits for that movie.
+	require.NoError(t, repo.DeleteCharacter(t.Context(), donkey.ID, AnyVersion))
+	require.NoError(t, repo.DeleteMovie(t.Context(), shrek2.ID, AnyVersion))
+	_, err = repo.RestoreCharacter(t.Context(), donkey.ID)
+	require.NoError(t, err)
+	movies, _ := repo.GetMoviesByCharacter(t.Context(), donkey.ID)
+	assert.Equal(t, []string{"Shrek"}, titles(movies))
+	_, err = repo.RestoreMovie(t.Context(), shrek2.ID)
+	require.NoError(t, err)
+	movies, _ = repo.GetMoviesByCharacter(t.Context(), donkey.ID)
+	assert.ElementsMatch(t, []string{"Shrek", "Shrek 2"}, titles(movies))
+
+	require.NoError(t, repo.DeleteCharacter(t.Context(), fiona.ID, AnyVersion))
+	assert.Zero(t, repo.PurgeTrash(t.Context(), time.Now().Add(-time.Hour)))
+	assert.Equal(t, 1, repo.PurgeTrash(t.Context(), time.Now().Add(time.Second)))
+	assert.Empty(t, repo.ListTrash(t.Context()))
+	_, err = repo.RestoreCharacter(t.Context(), fiona.ID)
+	assert.ErrorIs(t, err, ErrCharacterNotFound)
+}
+
+func titles(movies []entity.Movie) []string {
+	var result []string
+	for _, m := range movies {
+		result = append(result, m.Title)
+	}
+	return result
+}
+
+func TestGraph(t *testing.T) {
+	repo := New(db.New(), nil, nil, nil)
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	shrek2, _ := repo.CreateMovie(t.Context(), "Shrek 2", 2004)
+	puss, _ := repo.CreateMovie(t.Context(), "Puss in Boots", 2011)
+	empire, _ := repo.CreateMovie(t.Context(), "The Empire Strikes Back", 1980)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	fiona, _ := repo.CreateCharacter(t.Context(), "Fiona")
+	cat, _ := repo.CreateCharacter(t.Context(), "Puss")
+	kitty, _ := repo.CreateCharacter(t.Context(), "Kitty")
+	yoda, _ := repo.CreateCharacter(t.Context(), "Yoda")
+	for _, a := range [][2]uuid.UUID{
+		{shrek.ID, donkey.ID}, {shrek.ID, fiona.ID}, {shrek2.ID, donkey.ID}, {shrek2.ID, fiona.ID},
+		{shrek2.ID, cat.ID}, {shrek2.ID, cat.ID}, {puss.ID, cat.ID}, {puss.ID, kitty.ID}, {empire.ID, yoda.ID},
+	} {
+		require.NoError(t, repo.AddAppearance(t.Context(), a[0], a[1]))
+	}
+
+	coStars, err := repo.CoStars(t.Context(), donkey.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, coStars, 2)
+	assert.Equal(t, fiona.ID, coStars[0].Character.ID)
+	assert.Equal(t, []string{"Shrek", "Shrek 2"}, titles(coStars[0].Movies))
+	assert.Equal(t, cat.ID, coStars[1].Character.ID)
+	assert.Equal(t, []string{"Shrek 2"}, titles(coStars[1].Movies))
+	coStars, _ = repo.CoStars(t.Context(), donkey.ID, 1)
+	assert.Len(t, coStars, 1)
+	_, err = repo.CoStars(t.Context(), uuid.New(), 0)
+	assert.ErrorIs(t, err, ErrCharacterNotFound)
+
+	path, err := repo.ShortestPath(t.Context(), fiona.ID, kitty.ID, MaxPathDepth)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Character{fiona, cat, kitty}, path.Characters)
+	assert.Equal(t, []string{"Shrek 2", "Puss in Boots"}, titles(path.Movies))
+	_, err = repo.ShortestPath(t.Context(), fiona.ID, kitty.ID, 1)
+	assert.ErrorIs(t, err, ErrPathNotFound)
+	_, err = repo.ShortestPath(t.Context(), fiona.ID, yoda.ID, MaxPathDepth)
+	assert.ErrorIs(t, err, ErrPathNotFound)
+	path, err = repo.ShortestPath(t.Context(), yoda.ID, yoda.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Character{yoda}, path.Characters)
+	assert.Empty(t, path.Movies)
+	_, err = repo.ShortestPath(t.Context(), fiona.ID, kitty.ID, MaxPathDepth+1)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	lonely, _ := repo.CreateCharacter(t.Context(), "Lonely")
+	components := repo.Components(t.Context())
+	require.Len(t, components, 3)
+	assert.Equal(t, []entity.Character{donkey, fiona, kitty, cat}, components[0].Characters)
+	assert.Equal(t, []string{"Shrek", "Shrek 2", "Puss in Boots"}, titles(components[0].Movies))
+	assert.Equal(t, []entity.Character{yoda}, components[1].Characters)
+	assert.Equal(t, []entity.Character{lonely}, components[2].Characters)
+	assert.Empty(t, components[2].Movies)
+}
+
+func TestFranchise(t *testing.T) {
+	repo := New(db.New(), nil, nil, audit.New())
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	shrek2, _ := repo.CreateMovie(t.Context(), "Shrek 2", 2004)
+	puss, _ := repo.CreateMovie(t.Context(), "Puss in Boots", 2011)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	fiona, _ := repo.CreateCharacter(t.Context(), "Fiona")
+	dragon, _ := repo.CreateCharacter(t.Context(), "Dragon")
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek2.ID, fiona.ID))
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek.ID, donkey.ID))
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek2.ID, donkey.ID))
+
+	_, err := repo.CreateFranchise(t.Context(), "", nil)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	_, err = repo.CreateFranchise(t.Context(), "Shrek", []entity.Installment{{MovieID: shrek.ID, Kind: "remake"}})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	_, err = repo.CreateFranchise(t.Context(), "Shrek", []entity.Installment{{MovieID: shrek.ID, Kind: entity.Original}, {MovieID: shrek.ID, Kind: entity.Sequel}})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	_, err = repo.CreateFranchise(t.Context(), "Shrek", []entity.Installment{{MovieID: uuid.New(), Kind: entity.Original}})
+	assert.ErrorIs(t, err, ErrMovieNotFound)
+
+	franchise, err := repo.CreateFranchise(t.Context(), "Shrek", []entity.Installment{{MovieID: shrek.ID, Kind: entity.Original}, {MovieID: shrek2.ID, Kind: entity.Sequel}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), franchise.Version)
+	_, err = repo.UpdateFranchise(t.Context(), franchise.ID, "Shrek", nil, 2)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	franchise, err = repo.UpdateFranchise(t.Context(), franchise.ID, "Shrek", append(franchise.Installments, entity.Installment{MovieID: puss.ID, Kind: entity.SpinOff}), 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), franchise.Version)
+	franchise, err = repo.LinkFranchiseCharacter(t.Context(), franchise.ID, dragon.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{dragon.ID}, franchise.Characters)
+	again, err := repo.LinkFranchiseCharacter(t.Context(), franchise.ID, dragon.ID)
+	require.NoError(t, err)
+	assert.Equal(t, franchise, again)
+	_, err = repo.LinkFranchiseCharacter(t.Context(), franchise.ID, uuid.New())
+	assert.ErrorIs(t, err, ErrCharacterNotFound)
+	assert.Equal(t, []entity.Franchise{franchise}, repo.ListFranchises(t.Context()))
+
+	characters, err := repo.FranchiseCharacters(t.Context(), franchise.ID)
+	require.NoError(t, err)
+	require.Len(t, characters, 3)
+	assert.Equal(t, donkey, characters[0].Character)
+	assert.Equal(t, []Installment{{Position: 1, Kind: entity.Original, Movie: shrek}, {Position: 2, Kind: entity.Sequel, Movie: shrek2}}, characters[0].Installments)
+	assert.Equal(t, fiona, characters[1].Character)
+	assert.Equal(t, dragon, characters[2].Character)
+	assert.True(t, characters[2].Linked)
+	assert.Empty(t, characters[2].Installments)
+
+	// A movie in the trash drops out, keeping the positions of the others.
+	require.NoError(t, repo.DeleteMovie(t.Context(), shrek.ID, AnyVersion))
+	timeline, err := repo.CharacterTimeline(t.Context(), donkey.ID)
+	require.NoError(t, err)
+	require.Len(t, timeline, 1)
+	assert.False(t, timeline[0].Linked)
+	assert.Equal(t, []Installment{{Position: 2, Kind: entity.Sequel, Movie: shrek2}}, timeline[0].Installments)
+	timeline, _ = repo.CharacterTimeline(t.Context(), dragon.ID)
+	require.Len(t, timeline, 1)
+	assert.True(t, timeline[0].Linked)
+
+	_, err = repo.UnlinkFranchiseCharacter(t.Context(), franchise.ID, dragon.ID)
+	require.NoError(t, err)
+	_, err = repo.UnlinkFranchiseCharacter(t.Context(), franchise.ID, dragon.ID)
+	assert.ErrorIs(t, err, ErrCharacterNotFound)
+	timeline, _ = repo.CharacterTimeline(t.Context(), dragon.ID)
+	assert.Empty(t, timeline)
+
+	assert.ErrorIs(t, repo.DeleteFranchise(t.Context(), franchise.ID, 1), ErrVersionMismatch)
+	require.NoError(t, repo.DeleteFranchise(t.Context(), franchise.ID, AnyVersion))
+	_, err = repo.GetFranchise(t.Context(), franchise.ID)
+	assert.ErrorIs(t, err, ErrFranchiseNotFound)
+	_, err = repo.GetCharacter(t.Context(), donkey.ID)
+	assert.NoError(t, err)
+	assert.Len(t, repo.Audit.Find(audit.Filter{EntityType: audit.Franchise}), 5)
+
+	// Creating into a missing franchise leaves no character behind.
+	_, err = repo.CreateCharacterInFranchise(t.Context(), "Gingy", franchise.ID)
+	assert.ErrorIs(t, err, ErrFranchiseNotFound)
+	assert.Len(t, repo.Snapshot(t.Context()).Characters, 3)
+	franchise, _ = repo.CreateFranchise(t.Context(), "Shrek", nil)
+	gingy, err := repo.CreateCharacterInFranchise(t.Context(), "Gingy", franchise.ID)
+	require.NoError(t, err)
+	franchise, _ = repo.GetFranchise(t.Context(), franchise.ID)
+	assert.Equal(t, []uuid.UUID{gingy.ID}, franchise.Characters)
+	assert.Equal(t, int64(2), franchise.Version)
+}
+
+func TestSnapshotWaitsForChanges(t *testing.T) {
+	repo := New(db.New(), nil, nil, nil)
+	babe, _ := repo.CreateMovie(t.Context(), "Babe", 1995)
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek.ID, donkey.ID))
+
+	// A change in progress holds the read lock until it is done.
+	repo.DB.Writes.RLock()
+	taken := make(chan Snapshot)
+	go func() { taken <- repo.Snapshot(t.Context()) }()
+	select {
+	case <-taken:
+		t.Fatal("snapshot taken during a change")
+	case <-time.After(50 * time.Millisecond):
+	}
+	repo.DB.Writes.RUnlock()
+
+	s := <-taken
+	assert.Equal(t, []entity.Movie{babe, shrek}, s.Movies)
+	assert.Equal(t, []entity.Character{donkey}, s.Characters)
+	assert.Equal(t, []entity.Appearance{{MovieID: shrek.ID, CharacterID: donkey.ID}}, s.Appearances)
+}
+
+func TestBatch(t *testing.T) {
+	bus := events.New(config.Default())
+	t.Cleanup(bus.Close)
+	repo := New(db.New(), nil, bus, audit.New())
+	babe, _ := repo.CreateMovie(t.Context(), "Babe", 1995)
+	sub, _ := bus.Subscribe(1)
+	defer sub.Close()
+
+	var shrek entity.Movie
+	err := repo.Batch(t.Context(), func(tx *Tx) error {
+		var err error
+		shrek, err = tx.CreateMovie("Shrek", 2001)
+		require.NoError(t, err)
+		donkey, err := tx.CreateCharacter("Donkey")
+		require.NoError(t, err)
+		require.NoError(t, tx.AddAppearance(shrek.ID, donkey.ID))
+		_, err = tx.UpdateMovie(shrek.ID, "Shrek the First", 0, 1)
+		require.NoError(t, err)
+		// Staged changes stay out of the store until the batch commits.
+		_, stored := repo.DB.Movies.Load(shrek.ID)
+		assert.False(t, stored)
+		return tx.DeleteMovie(babe.ID, 1)
+	})
+	require.NoError(t, err)
+	s := repo.Snapshot(t.Context())
+	require.Len(t, s.Movies, 1)
+	assert.Equal(t, "Shrek the First", s.Movies[0].Title)
+	assert.Equal(t, int64(2), s.Movies[0].Version)
+	assert.Len(t, s.Appearances, 1)
+	require.Len(t, repo.ListTrash(t.Context()), 1)
+	assert.Equal(t, babe.ID, repo.ListTrash(t.Context())[0].ID())
+	var types []events.Type
+	for range 5 {
+		types = append(types, (<-sub.C).Type)
+	}
+	assert.Equal(t, []events.Type{events.MovieCreated, events.CharacterCreated, events.AppearanceLinked, events.MovieUpdated, events.MovieDeleted}, types)
+	entries := repo.Audit.Find(audit.Filter{})
+	require.Len(t, entries, 6)
+	assert.Equal(t, "movie.deleted", entries[0].Operation)
+	assert.Contains(t, entries[0].Diff, "deleted_at")
+
+	// A failing call drops everything staged before it.
+	err = repo.Batch(t.Context(), func(tx *Tx) error {
+		_, err := tx.CreateMovie("Shrek 2", 2004)
+		require.NoError(t, err)
+		require.NoError(t, tx.RemoveAppearance(s.Appearances[0].MovieID, s.Appearances[0].CharacterID))
+		require.NoError(t, tx.DeleteCharacter(s.Characters[0].ID, AnyVersion))
+		_, err = tx.UpdateMovie(shrek.ID, "Shrek 3", 0, 1)
+		return err
+	})
+	require.ErrorIs(t, err, ErrVersionMismatch)
+	assert.Equal(t, s, repo.Snapshot(t.Context()))
+	assert.Len(t, repo.Audit.Find(audit.Filter{}), 6)
+	select {
+	case e := <-sub.C:
+		t.Fatalf("rolled back batch published %s", e.Type)
+	default:
+	}
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"example.com/go_basics/go/audit"
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/entity"
+	"example.com/go_basics/go/events"
+	"example.com/go_basics/go/logging"
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+func deletedAt() *time.Time {
+	now := time.Now().UTC()
+	return &now
+}
+
+// RestoreMovie takes the movie out of the trash with the appearances its
+// deletion dropped. Appearances of characters that are in the trash
+// themselves come back with those characters.
+func (r *Repository) RestoreMovie(ctx context.Context, id uuid.UUID) (entity.Movie, error) {
+	ctx, end := r.observe(ctx, "RestoreMovie")
+	defer end()
+	r.DB.Writes.RLock()
+	defer r.DB.Writes.RUnlock()
+	r.DB.Mutex.Lock()
+	d, ok := r.DB.Trash[id]
+	if !ok || d.Movie == nil {
+		r.DB.Mutex.Unlock()
+		return entity.Movie{}, fmt.Errorf("%w in the trash [ID: %s]", ErrMovieNotFound, id)
+	}
+	movie := *d.Movie
+	movie.DeletedAt = nil
+	movie.Version++
+	r.DB.Movies.Store(id, movie)
+	linked := r.relink(d, func(a entity.Appearance) uuid.UUID { return a.CharacterID }, &r.DB.Characters)
+	r.DB.Mutex.Unlock()
+
+	r.publish(events.MovieRestored, movie)
+	r.Audit.Append(auditEntry(ctx, events.MovieRestored, id, *d.Movie, movie))
+	for _, a := range linked {
+		r.publish(events.AppearanceLinked, a)
+		r.Audit.Append(linkEntry(ctx, events.AppearanceLinked, a))
+	}
+	logging.FromContext(ctx).Debug("movie restored", zap.Stringer("movie_id", id), zap.Int("appearances", len(linked)))
+	return movie, nil
+}
+
+// RestoreCharacter takes the character out of the trash with the
+// appearances its deletion dropped. Appearances of movies that are in the
+// trash themselves come back with those movies.
+func (r *Repository) RestoreCharacter(ctx context.Context, id uuid.UUID) (entity.Character, error) {
+	ctx, end := r.observe(ctx, "RestoreCharacter")
+	defer end()
+	r.DB.Writes.RLock()
+	defer r.DB.Writes.RUnlock()
+	r.DB.Mutex.Lock()
+	d, ok := r.DB.Trash[id]
+	if !ok || d.Character == nil {
+		r.DB.Mutex.Unlock()
+		return entity.Character{}, fmt.Errorf("%w in the trash [ID: %s]", ErrCharacterNotFound, id)
+	}
+	character := *d.Character
+	character.DeletedAt = nil
+	character.Version++
+	r.DB.Characters.Store(id, character)
+	linked := r.relink(d, func(a entity.Appearance) uuid.UUID { return a.MovieID }, &r.DB.Movies)
+	r.DB.Mutex.Unlock()
+
+	r.publish(events.CharacterRestored, character)
+	r.Audit.Append(auditEntry(ctx, events.CharacterRestored, id, *d.Character, character))
+	for _, a := range linked {
+		r.publish(events.AppearanceLinked, a)
+		r.Audit.Append(linkEntry(ctx, events.AppearanceLinked, a))
+	}
+	logging.FromContext(ctx).Debug("character restored", zap.Stringer("character_id", id), zap.Int("appearances", len(linked)))
+	return character, nil
+}
+
+// relink takes d out of the trash and links its appearances again where the
+// other end is stored. Where the other end is in the trash, it keeps the
+// appearance for its own restore; where it was purged, the appearance is
+// dropped. The caller holds Mutex.
+func (r *Repository) relink(d db.Deleted, other func(entity.Appearance) uuid.UUID, stored *sync.Map) []entity.Appearance {
+	delete(r.DB.Trash, d.ID())
+	var linked []entity.Appearance
+	for _, a := range d.Appearances {
+		id := other(a)
+		if _, ok := stored.Load(id); ok {
+			linked = append(linked, a)
+		} else if o, ok := r.DB.Trash[id]; ok {
+			o.Appearances = append(slices.Clip(o.Appearances), a)
+			r.DB.Trash[id] = o
+		}
+	}
+	r.DB.Appearances = append(r.DB.Appearances, linked...)
+	return linked
+}
+
+// ListTrash returns the movies and characters in the trash, the latest
+// deletion first.
+func (r *Repository) ListTrash(ctx context.Context) []db.Deleted {
+	ctx, end := r.observe(ctx, "ListTrash")
+	defer end()
+	r.DB.Mutex.Lock()
+	result := make([]db.Deleted, 0, len(r.DB.Trash))
+	for _, d := range r.DB.Trash {
+		d.Appearances = slices.Clone(d.Appearances)
+		result = append(result, d)
+	}
+	r.DB.Mutex.Unlock()
+	slices.SortFunc(result, func(a, b db.Deleted) int {
+		return cmp.Or(b.DeletedAt().Compare(a.DeletedAt()), cmp.Compare(a.ID().String(), b.ID().String()))
+	})
+	logging.FromContext(ctx).Debug("trash listed", zap.Int("count", len(result)))
+	return result
+}
+
+// PurgeTrash removes what was deleted before the given time for good and
+// returns how many movies and characters that were.
+func (r *Repository) PurgeTrash(ctx context.Context, before time.Time) int {
+	ctx, end := r.observe(ctx, "PurgeTrash")
+	defer end()
+	r.DB.Mutex.Lock()
+	var purged []audit.Entry
+	for id, d := range r.DB.Trash {
+		if !d.DeletedAt().Before(before) {
+			continue
+		}
+		delete(r.DB.Trash, id)
+		if d.Movie != nil {
+			purged = append(purged, audit.NewEntry(ctx, "movie.purged", audit.Movie, id, *d.Movie, nil))
+		} else {
+			purged = append(purged, audit.NewEntry(ctx, "character.purged", audit.Character, id, *d.Character, nil))
+		}
+	}
+	r.DB.Mutex.Unlock()
+	r.Audit.Append(purged...)
+	if len(purged) > 0 {
+		logging.FromContext(ctx).Info("trash purged", zap.Int("count", len(purged)), zap.Time("before", before))
+	}
+	return len(purged)
+}
+
+// StartPurge purges the trash of what is past the retention every purge
+// interval while the app runs.
+func StartPurge(lc fx.Lifecycle, cfg *config.Config, r *Repository, logger *zap.Logger) {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	ctx := audit.WithActor(logging.WithContext(context.Background(), logger.Named("trash")), "trash purge")
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(cfg.Trash.PurgeInterval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ticker.C:
+						r.PurgeTrash(ctx, time.Now().Add(-cfg.Trash.Retention))
+					case <-stop:
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stop)
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+}
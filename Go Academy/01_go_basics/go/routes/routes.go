@@ -1,22 +1,60 @@
 package routes
 
 import (
-	"log"
+	"slices"
 
 	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/auth"
 	"example.com/go_basics/go/handlers"
+	"example.com/go_basics/go/health"
+	"example.com/go_basics/go/logging"
+	"example.com/go_basics/go/metrics"
+	"example.com/go_basics/go/mtls"
+	"example.com/go_basics/go/ratelimit"
+	"example.com/go_basics/go/signing"
+	"example.com/go_basics/go/validation"
 
 	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"go.uber.org/zap"
 )
 
-func NewEchoRouter(h *handlers.Handlers) *echo.Echo {
+func NewEchoRouter(h *handlers.Handlers, signer *signing.Signer, logger *zap.Logger, m *metrics.Metrics, hc *health.Health, a *auth.Auth, rl *ratelimit.Limiter) *echo.Echo {
 	e := echo.New()
+	e.HTTPErrorHandler = handlers.HTTPErrorHandler
 
-	_, err := api.GetSwagger()
+	spec, err := api.GetSwagger()
 	if err != nil {
-		log.Fatalf("Failed to load OpenAPI spec: %v", err)
+		logger.Fatal("Failed to load OpenAPI spec", zap.Error(err))
 	}
 
+	// Scrapes and probes are neither traced, rate limited nor logged above
+	// debug level.
+	operational := []string{metrics.Path, health.LivePath, health.ReadyPath}
+
+	// Without a server name the span reports the Host header as server.address.
+	e.Use(otelecho.Middleware("", otelecho.WithSkipper(func(c echo.Context) bool {
+		return slices.Contains(operational, c.Path())
+	})))
+	if m != nil {
+		e.Use(m.Middleware())
+		e.GET(metrics.Path, m.Handler())
+	}
+	if hc != nil {
+		hc.Register(e)
+	}
+	e.Use(logging.Middleware(logger, operational...))
+	e.Use(mtls.Middleware())
+	if a != nil {
+		e.Use(a.Middleware(spec))
+	}
+	if rl != nil {
+		e.Use(rl.Middleware(spec, operational...))
+	}
+	if signer != nil {
+		e.Use(signing.Middleware(signer))
+	}
+	e.Use(validation.Middleware(spec))
 	api.RegisterHandlers(e, h)
 
 	return e
@@ -0,0 +1,62 @@
+package signing
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Requested reports whether the client asked for a signed response with
+// ?signed=true or an Accept-Signature header.
+func Requested(r *http.Request) bool {
+	return r.URL.Query().Get("signed") == "true" || r.Header.Get("Accept-Signature") != ""
+}
+
+// Middleware signs response bodies for clients that ask for it.
+func Middleware(s *Signer) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !Requested(c.Request()) {
+				return next(c)
+			}
+
+			res := c.Response()
+			original := res.Writer
+			buf := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+			res.Writer = buf
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+			res.Writer = original
+
+			res.Header().Add(echo.HeaderVary, "Accept-Signature")
+			if buf.body.Len() > 0 {
+				jws, signErr := s.Sign(buf.body.Bytes())
+				if signErr != nil {
+					return signErr
+				}
+				res.Header().Set(Header, jws)
+			}
+			original.WriteHeader(buf.status)
+			_, writeErr := original.Write(buf.body.Bytes())
+			return writeErr
+		}
+	}
+}
+
+// bufferedWriter holds the response until the body can be signed.
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
@@ -0,0 +1,153 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"example.com/go_basics/go/pki"
+)
+
+// Header carries the detached JWS of a signed response body.
+const Header = "X-JWS-Signature"
+
+var ErrInvalidSignature = errors.New("invalid response signature")
+
+type jwsHeader struct {
+	Alg string   `json:"alg"`
+	Typ string   `json:"typ,omitempty"`
+	X5C []string `json:"x5c"`
+}
+
+// Signer produces detached RS256 JWS (RFC 7515 appendix F) with a key
+// issued by our CA. The certificate chain travels in the x5c header so
+// consumers only need the CA certificate to verify.
+type Signer struct {
+	key       *rsa.PrivateKey
+	protected string
+}
+
+func New(ca *pki.Authority) (*Signer, error) {
+	chain, key, err := ca.IssueSigner()
+	if err != nil {
+		return nil, err
+	}
+	return NewSigner(key, chain)
+}
+
+func NewSigner(key *rsa.PrivateKey, chain []*x509.Certificate) (*Signer, error) {
+	header := jwsHeader{Alg: "RS256", Typ: "JOSE"}
+	for _, cert := range chain {
+		header.X5C = append(header.X5C, base64.StdEncoding.EncodeToString(cert.Raw))
+	}
+	data, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{key: key, protected: base64.RawURLEncoding.EncodeToString(data)}, nil
+}
+
+// Sign returns the compact serialization with the payload left out.
+func (s *Signer) Sign(payload []byte) (string, error) {
+	digest := sha256.Sum256(signingInput(s.protected, payload))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return s.protected + ".." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func signingInput(protected string, payload []byte) []byte {
+	return []byte(protected + "." + base64.RawURLEncoding.EncodeToString(payload))
+}
+
+// Verify checks a detached JWS over payload and that the signing certificate
+// chains to roots. It returns the signing certificate.
+func Verify(payload []byte, jws string, roots *x509.CertPool) (*x509.Certificate, error) {
+	parts := strings.Split(jws, ".")
+	if len(parts) != 3 || parts[1] != "" {
+		return nil, fmt.Errorf("%w: not a detached compact JWS", ErrInvalidSignature)
+	}
+	data, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	var header jwsHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if header.Alg != "RS256" || len(header.X5C) == 0 {
+		return nil, fmt.Errorf("%w: unsupported header", ErrInvalidSignature)
+	}
+
+	var chain []*x509.Certificate
+	for _, c := range header.X5C {
+		der, err := base64.StdEncoding.DecodeString(c)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+		}
+		chain = append(chain, cert)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	// Movie certificates carry no extended key usage and would pass the
+	// code signing check alone, so the signer unit is required as well.
+	if !slices.Contains(chain[0].Subject.OrganizationalUnit, pki.UnitSigner) {
+		return nil, fmt.Errorf("%w: %q is not a response signer", ErrInvalidSignature, chain[0].Subject.CommonName)
+	}
+	chains, err := chain[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	// The API signer is issued by the CA itself. A signer under a movie
+	// intermediate was minted by whoever holds that movie certificate.
+	if !slices.ContainsFunc(chains, func(c []*x509.Certificate) bool { return len(c) == 2 }) {
+		return nil, fmt.Errorf("%w: %q is not issued by the CA", ErrInvalidSignature, chain[0].Subject.CommonName)
+	}
+
+	pub, ok := chain[0].PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: signer key is not RSA", ErrInvalidSignature)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	digest := sha256.Sum256(signingInput(parts[0], payload))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return chain[0], nil
+}
+
+// VerifyResponse reads a signed response body and verifies its signature.
+func VerifyResponse(resp *http.Response, roots *x509.CertPool) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := Verify(body, resp.Header.Get(Header), roots); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
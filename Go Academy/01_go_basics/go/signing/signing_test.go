@@ -0,0 +1,128 @@
+package signing
+
+import (
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"example.com/go_basics/go/pki"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, *x509.CertPool) {
+	caCert, caKey, err := pki.Issue(pki.CATemplate(), nil, nil)
+	require.NoError(t, err)
+	cert, key, err := pki.Issue(pki.SignerTemplate(), caCert, caKey)
+	require.NoError(t, err)
+	signer, err := NewSigner(key, []*x509.Certificate{cert, caCert})
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.Use(Middleware(signer))
+	e.GET("/movies", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, echo.Map{"title": "Shrek", "release_year": 2001})
+	})
+	e.DELETE("/movies", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	e.GET("/missing", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusNotFound, "movie not found")
+	})
+
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	return httptest.NewServer(e), roots
+}
+
+func TestSignedResponses(t *testing.T) {
+	server, roots := newTestServer(t)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/movies?signed=true")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	jws := resp.Header.Get(Header)
+	require.NotEmpty(t, jws)
+
+	body, err := VerifyResponse(resp, roots)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"title":"Shrek","release_year":2001}`, string(body))
+
+	_, err = Verify([]byte(`{"title":"Shrek 2","release_year":2004}`), jws, roots)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	_, err = Verify(body, jws, x509.NewCertPool())
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/missing", nil)
+	req.Header.Set("Accept-Signature", "jws")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_, err = VerifyResponse(resp, roots)
+	assert.NoError(t, err)
+}
+
+func TestUnsignedResponses(t *testing.T) {
+	server, _ := newTestServer(t)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/movies")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Empty(t, resp.Header.Get(Header))
+
+	req, _ := http.NewRequest(http.MethodDelete, server.URL+"/movies?signed=true", nil)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(Header))
+}
+
+func TestVerifyRejectsOtherCertificates(t *testing.T) {
+	caCert, caKey, err := pki.Issue(pki.CATemplate(), nil, nil)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	movieCert, movieKey, err := pki.Issue(pki.MovieTemplate("Shrek"), caCert, caKey)
+	require.NoError(t, err)
+	characterCert, characterKey, err := pki.Issue(pki.CharacterTemplate("Donkey"), movieCert, movieKey)
+	require.NoError(t, err)
+	// A movie holder minting a signer of its own.
+	forgedCert, forgedKey, err := pki.Issue(pki.SignerTemplate(), movieCert, movieKey)
+	require.NoError(t, err)
+	// Without the client-only usage of movie certificates, only the chain
+	// length stops such a signer.
+	legacyTemplate := pki.MovieTemplate("Shrek")
+	legacyTemplate.ExtKeyUsage = nil
+	legacyCert, legacyKey, err := pki.Issue(legacyTemplate, caCert, caKey)
+	require.NoError(t, err)
+	legacyForgedCert, legacyForgedKey, err := pki.Issue(pki.SignerTemplate(), legacyCert, legacyKey)
+	require.NoError(t, err)
+
+	payload := []byte(`{"title":"Shrek"}`)
+	for name, signer := range map[string]func() (*Signer, error){
+		"movie": func() (*Signer, error) { return NewSigner(movieKey, []*x509.Certificate{movieCert, caCert}) },
+		"character": func() (*Signer, error) {
+			return NewSigner(characterKey, []*x509.Certificate{characterCert, movieCert, caCert})
+		},
+		"signer under a movie": func() (*Signer, error) {
+			return NewSigner(forgedKey, []*x509.Certificate{forgedCert, movieCert, caCert})
+		},
+		"signer under a movie without usages": func() (*Signer, error) {
+			return NewSigner(legacyForgedKey, []*x509.Certificate{legacyForgedCert, legacyCert, caCert})
+		},
+	} {
+		s, err := signer()
+		require.NoError(t, err)
+		jws, err := s.Sign(payload)
+		require.NoError(t, err)
+		_, err = Verify(payload, jws, roots)
+		assert.ErrorIs(t, err, ErrInvalidSignature, name)
+	}
+}
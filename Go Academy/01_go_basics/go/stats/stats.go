@@ -0,0 +1,135 @@
+// Package stats computes aggregate figures of the catalog for dashboards:
+// movies per year, cast sizes, the busiest characters and what is not
+// linked at all. Figures are computed from one snapshot and kept until the
+// store changes.
+package stats
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"example.com/go_basics/go/entity"
+	"example.com/go_basics/go/logging"
+	"example.com/go_basics/go/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Report holds the figures of one snapshot. Appearances and cast sizes
+// count a character linked to a movie twice once.
+type Report struct {
+	Movies      int
+	Characters  int
+	Appearances int
+	// AverageCast is the mean number of characters per movie.
+	AverageCast float64
+	// MoviesPerYear counts the movies by release year, the earliest first.
+	MoviesPerYear []YearCount
+	// TopCharacters are the characters with appearances, the most movies
+	// first.
+	TopCharacters []CharacterCount
+	// EmptyMovies have no characters, the earliest first.
+	EmptyMovies []entity.Movie
+	// OrphanCharacters appear in no movie, by name.
+	OrphanCharacters []entity.Character
+	// Changes is the count of store changes the report is computed from.
+	Changes uint64
+}
+
+// YearCount is the number of movies released in a year or, per decade, in
+// the ten years from Year.
+type YearCount struct {
+	Year   int
+	Movies int
+}
+
+// CharacterCount is the number of movies a character appears in.
+type CharacterCount struct {
+	Character entity.Character
+	Movies    int
+}
+
+// Compute derives the report from a snapshot in one pass over each of its
+// lists.
+func Compute(s repository.Snapshot) Report {
+	r := Report{Movies: len(s.Movies), Characters: len(s.Characters), Changes: s.Changes}
+	cast := map[uuid.UUID]int{}
+	movies := map[uuid.UUID]int{}
+	seen := map[[2]uuid.UUID]bool{}
+	for _, a := range s.Appearances {
+		if link := [2]uuid.UUID{a.MovieID, a.CharacterID}; !seen[link] {
+			seen[link] = true
+			cast[a.MovieID]++
+			movies[a.CharacterID]++
+		}
+	}
+	r.Appearances = len(seen)
+	if r.Movies > 0 {
+		r.AverageCast = float64(r.Appearances) / float64(r.Movies)
+	}
+
+	years := map[int]int{}
+	for _, m := range s.Movies {
+		years[m.Year]++
+		if cast[m.ID] == 0 {
+			r.EmptyMovies = append(r.EmptyMovies, m)
+		}
+	}
+	for year, n := range years {
+		r.MoviesPerYear = append(r.MoviesPerYear, YearCount{Year: year, Movies: n})
+	}
+	slices.SortFunc(r.MoviesPerYear, func(a, b YearCount) int { return cmp.Compare(a.Year, b.Year) })
+	slices.SortStableFunc(r.EmptyMovies, func(a, b entity.Movie) int { return cmp.Compare(a.Year, b.Year) })
+
+	for _, c := range s.Characters {
+		if n := movies[c.ID]; n > 0 {
+			r.TopCharacters = append(r.TopCharacters, CharacterCount{Character: c, Movies: n})
+		} else {
+			r.OrphanCharacters = append(r.OrphanCharacters, c)
+		}
+	}
+	slices.SortStableFunc(r.TopCharacters, func(a, b CharacterCount) int { return cmp.Compare(b.Movies, a.Movies) })
+	return r
+}
+
+// MoviesPerDecade counts the movies by the decade of their release, the
+// earliest first.
+func (r Report) MoviesPerDecade() []YearCount {
+	var result []YearCount
+	for _, y := range r.MoviesPerYear {
+		decade := y.Year - y.Year%10
+		if n := len(result); n > 0 && result[n-1].Year == decade {
+			result[n-1].Movies += y.Movies
+		} else {
+			result = append(result, YearCount{Year: decade, Movies: y.Movies})
+		}
+	}
+	return result
+}
+
+// Cache keeps the last report until the store changes.
+type Cache struct {
+	repo   *repository.Repository
+	mu     sync.Mutex
+	report *Report
+}
+
+func New(repo *repository.Repository) *Cache {
+	return &Cache{repo: repo}
+}
+
+// Report returns the report of the store, computing it again only when the
+// store changed since the last one.
+func (c *Cache) Report(ctx context.Context) Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.report != nil && c.report.Changes == c.repo.Changes() {
+		return *c.report
+	}
+	r := Compute(c.repo.Snapshot(ctx))
+	c.report = &r
+	logging.FromContext(ctx).Debug("stats computed", zap.Uint64("changes", r.Changes), zap.Int("movies", r.Movies), zap.Int("characters", r.Characters))
+	return r
+}
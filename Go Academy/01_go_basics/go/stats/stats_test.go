@@ -0,0 +1,66 @@
+package stats_test
+
+import (
+	"testing"
+
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/entity"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompute(t *testing.T) {
+	repo := repository.New(db.New(), nil, nil, nil)
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	shrek2, _ := repo.CreateMovie(t.Context(), "Shrek 2", 2004)
+	empire, _ := repo.CreateMovie(t.Context(), "The Empire Strikes Back", 1980)
+	hope, _ := repo.CreateMovie(t.Context(), "A New Hope", 1977)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	fiona, _ := repo.CreateCharacter(t.Context(), "Fiona")
+	yoda, _ := repo.CreateCharacter(t.Context(), "Yoda")
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek.ID, donkey.ID))
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek.ID, donkey.ID))
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek.ID, fiona.ID))
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek2.ID, donkey.ID))
+
+	r := stats.Compute(repo.Snapshot(t.Context()))
+	assert.Equal(t, 4, r.Movies)
+	assert.Equal(t, 3, r.Characters)
+	assert.Equal(t, 3, r.Appearances)
+	assert.InDelta(t, 0.75, r.AverageCast, 1e-9)
+	assert.Equal(t, []stats.YearCount{{1977, 1}, {1980, 1}, {2001, 1}, {2004, 1}}, r.MoviesPerYear)
+	assert.Equal(t, []stats.YearCount{{1970, 1}, {1980, 1}, {2000, 2}}, r.MoviesPerDecade())
+	assert.Equal(t, []stats.CharacterCount{{Character: donkey, Movies: 2}, {Character: fiona, Movies: 1}}, r.TopCharacters)
+	assert.Equal(t, []entity.Movie{hope, empire}, r.EmptyMovies)
+	assert.Equal(t, []entity.Character{yoda}, r.OrphanCharacters)
+	assert.Equal(t, repo.Changes(), r.Changes)
+}
+
+func TestCacheKeepsReportUntilChange(t *testing.T) {
+	repo := repository.New(db.New(), nil, nil, nil)
+	cache := stats.New(repo)
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	first := cache.Report(t.Context())
+	assert.Equal(t, 1, first.Movies)
+	// A movie stored without a change counted is not seen until the next one.
+	hidden := entity.NewMovie(entity.WithTitle("Shrek 2"), entity.WithYear(2004))
+	repo.DB.Movies.Store(hidden.ID, hidden)
+	assert.Equal(t, first, cache.Report(t.Context()))
+
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek.ID, donkey.ID))
+	r := cache.Report(t.Context())
+	assert.Greater(t, r.Changes, first.Changes)
+	assert.Equal(t, 2, r.Movies)
+	assert.Equal(t, 1, r.Appearances)
+	assert.Equal(t, []entity.Movie{hidden}, r.EmptyMovies)
+
+	require.NoError(t, repo.Batch(t.Context(), func(tx *repository.Tx) error {
+		return tx.DeleteMovie(shrek.ID, repository.AnyVersion)
+	}))
+	r = cache.Report(t.Context())
+	assert.Equal(t, 1, r.Movies)
+	assert.Equal(t, []entity.Character{donkey}, r.OrphanCharacters)
+}
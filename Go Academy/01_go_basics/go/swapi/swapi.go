@@ -1,28 +1,60 @@
 package swapi
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
 
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/logging"
+	"example.com/go_basics/go/metrics"
+	"example.com/go_basics/go/tracing"
 	"github.com/go-resty/resty/v2"
+	"go.uber.org/zap"
 )
 
 type Client struct {
-	http *resty.Client
+	http    *resty.Client
+	metrics *metrics.Metrics
 }
 
-func New() *Client {
+func New(cfg *config.Config, m *metrics.Metrics) *Client {
 	return &Client{
-		http: resty.New().SetBaseURL("https://swapi.dev/api"),
+		http: tracing.InstrumentResty(resty.New().
+			SetBaseURL(cfg.SWAPI.BaseURL).
+			SetTimeout(cfg.SWAPI.Timeout)),
+		metrics: m,
 	}
 }
 
-func (c *Client) CharacterExists(name string) (bool, error) {
+func (c *Client) CharacterExists(ctx context.Context, name string) (exists bool, err error) {
+	logger := logging.FromContext(ctx)
+	start := time.Now()
+	defer func() {
+		outcome := "not_found"
+		switch {
+		case err != nil:
+			outcome = "error"
+		case exists:
+			outcome = "found"
+		}
+		c.metrics.ObserveSWAPI(outcome, time.Since(start))
+	}()
+
 	resp, err := c.http.R().
+		SetContext(ctx).
 		SetQueryParam("search", name).
 		Get("/people/")
 	if err != nil {
+		logger.Warn("SWAPI request failed", zap.String("name", name), zap.Error(err))
 		return false, err
 	}
+	logger.Debug("SWAPI lookup",
+		zap.String("name", name),
+		zap.Int("status", resp.StatusCode()),
+		zap.Duration("duration", resp.Time()))
 
 	var result struct {
 		Count int `json:"count"`
@@ -33,3 +65,15 @@ func (c *Client) CharacterExists(name string) (bool, error) {
 
 	return result.Count > 0, nil
 }
+
+// Ping checks that the Star Wars API answers. Any response below 500 counts.
+func (c *Client) Ping(ctx context.Context) error {
+	resp, err := c.http.R().SetContext(ctx).Get("/")
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() >= http.StatusInternalServerError {
+		return fmt.Errorf("SWAPI answered %s", resp.Status())
+	}
+	return nil
+}
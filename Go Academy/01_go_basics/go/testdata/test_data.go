@@ -1,45 +1,57 @@
 package testdata
 
 import (
-	"log"
+	"context"
 
+	"example.com/go_basics/go/audit"
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/health"
+	"example.com/go_basics/go/logging"
 	"example.com/go_basics/go/repository"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
-func LoadTestData(repo *repository.Repository) {
-	shrek, err := repo.CreateMovie("Shrek", 2001)
+// LoadTestData seeds the store when enabled and then reports it as loaded
+// to the readiness probe.
+func LoadTestData(cfg *config.Config, repo *repository.Repository, hc *health.Health, logger *zap.Logger) {
+	defer hc.MarkLoaded()
+	if !cfg.TestData.Enabled {
+		return
+	}
+	ctx := audit.WithActor(logging.WithContext(context.Background(), logger), "testdata")
+	shrek, err := repo.CreateMovie(ctx, "Shrek", 2001)
 	if err != nil {
-		log.Printf("Error creating movie Shrek: %v", err)
+		logger.Error("creating movie", zap.String("title", "Shrek"), zap.Error(err))
 	}
-	shrek2, err := repo.CreateMovie("Shrek 2", 2004)
+	shrek2, err := repo.CreateMovie(ctx, "Shrek 2", 2004)
 	if err != nil {
-		log.Printf("Error creating movie Shrek 2: %v", err)
+		logger.Error("creating movie", zap.String("title", "Shrek 2"), zap.Error(err))
 	}
-	lionKing, err := repo.CreateMovie("The Lion King", 1994)
+	lionKing, err := repo.CreateMovie(ctx, "The Lion King", 1994)
 	if err != nil {
-		log.Printf("Error creating movie The Lion King: %v", err)
+		logger.Error("creating movie", zap.String("title", "The Lion King"), zap.Error(err))
 	}
 
-	shrekChar, err := repo.CreateCharacter("Shrek")
+	shrekChar, err := repo.CreateCharacter(ctx, "Shrek")
 	if err != nil {
-		log.Printf("Error creating character Shrek: %v", err)
+		logger.Error("creating character", zap.String("name", "Shrek"), zap.Error(err))
 	}
-	donkey, err := repo.CreateCharacter("Donkey")
+	donkey, err := repo.CreateCharacter(ctx, "Donkey")
 	if err != nil {
-		log.Printf("Error creating character Donkey: %v", err)
+		logger.Error("creating character", zap.String("name", "Donkey"), zap.Error(err))
 	}
-	fiona, err := repo.CreateCharacter("Fiona")
+	fiona, err := repo.CreateCharacter(ctx, "Fiona")
 	if err != nil {
-		log.Printf("Error creating character Fiona: %v", err)
+		logger.Error("creating character", zap.String("name", "Fiona"), zap.Error(err))
 	}
-	simba, err := repo.CreateCharacter("Simba")
+	simba, err := repo.CreateCharacter(ctx, "Simba")
 	if err != nil {
-		log.Printf("Error creating character Simba: %v", err)
+		logger.Error("creating character", zap.String("name", "Simba"), zap.Error(err))
 	}
-	pumbaa, err := repo.CreateCharacter("Pumbaa")
+	pumbaa, err := repo.CreateCharacter(ctx, "Pumbaa")
 	if err != nil {
-		log.Printf("Error creating character Pumbaa: %v", err)
+		logger.Error("creating character", zap.String("name", "Pumbaa"), zap.Error(err))
 	}
 
 	appearances := []struct {
@@ -57,10 +69,11 @@ func LoadTestData(repo *repository.Repository) {
 	}
 
 	for _, a := range appearances {
-		if err := repo.AddAppearance(a.MovieID, a.CharacterID); err != nil {
-			log.Printf("Error linking character %s to movie %s: %v", a.CharacterID, a.MovieID, err)
+		if err := repo.AddAppearance(ctx, a.MovieID, a.CharacterID); err != nil {
+			logger.Error("linking character to movie",
+				zap.Stringer("character_id", a.CharacterID), zap.Stringer("movie_id", a.MovieID), zap.Error(err))
 		}
 	}
 
-	log.Println("Test data loaded successfully")
+	logger.Info("test data loaded")
 }
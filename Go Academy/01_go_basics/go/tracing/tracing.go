@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"example.com/go_basics/go/config"
+	"github.com/go-resty/resty/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+)
+
+const scope = "example.com/go_basics/go/tracing"
+
+// Init installs the global tracer provider for the configured exporter and
+// the W3C trace context propagator. With the none exporter spans are still
+// created, so trace IDs propagate, but nothing is exported.
+func Init(lc fx.Lifecycle, cfg *config.Config) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exporter, err := newExporter(cfg.Tracing)
+	if err != nil {
+		return err
+	}
+	if exporter == nil {
+		return nil
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Tracing.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(cfg.Tracing.ServiceName),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+	lc.Append(fx.Hook{
+		OnStop: tp.Shutdown,
+	})
+	return nil
+}
+
+func newExporter(cfg config.Tracing) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case "none":
+		return nil, nil
+	case "stdout":
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "otlp":
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		// The exporter connects lazily, so the context only bounds setup.
+		return otlptracehttp.New(context.Background(), opts...)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+}
+
+// InstrumentResty wraps every request of c in a client span and injects the
+// trace context into its headers. Requests pick up their parent span from
+// the context set with Request.SetContext.
+func InstrumentResty(c *resty.Client) *resty.Client {
+	tracer := otel.Tracer(scope)
+	return c.
+		OnBeforeRequest(func(_ *resty.Client, r *resty.Request) error {
+			ctx, _ := tracer.Start(r.Context(), "HTTP "+r.Method,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(r.Method),
+					semconv.URLTemplate(r.URL),
+				))
+			otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(r.Header))
+			r.SetContext(ctx)
+			return nil
+		}).
+		OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+			span := trace.SpanFromContext(resp.Request.Context())
+			span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode()))
+			if resp.StatusCode() >= 500 {
+				span.SetStatus(codes.Error, resp.Status())
+			}
+			span.End()
+			return nil
+		}).
+		OnError(func(r *resty.Request, err error) {
+			span := trace.SpanFromContext(r.Context())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			span.End()
+		})
+}
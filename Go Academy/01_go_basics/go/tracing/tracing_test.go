@@ -0,0 +1,85 @@
+package tracing_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/handlers"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/routes"
+	"example.com/go_basics/go/swapi"
+	"example.com/go_basics/go/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestSpanTree(t *testing.T) {
+	require.NoError(t, tracing.Init(fxtest.NewLifecycle(t), config.Default()))
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	var swapiParent string
+	fakeSWAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		swapiParent = r.Header.Get("traceparent")
+		io.WriteString(w, `{"count":1}`)
+	}))
+	t.Cleanup(fakeSWAPI.Close)
+	cfg := config.Default()
+	cfg.SWAPI.BaseURL = fakeSWAPI.URL
+
+	h := handlers.New(repository.New(db.New(), nil, nil, nil), &pki.Authority{Dir: t.TempDir()}, nil, swapi.New(cfg, nil), nil, nil, nil)
+	server := httptest.NewServer(routes.NewEchoRouter(h, nil, zap.NewNop(), nil, nil, nil, nil))
+	t.Cleanup(server.Close)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/characters", strings.NewReader(`{"name":"Luke Skywalker","movie":"Star Wars"}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("traceparent", parent)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, s := range exporter.GetSpans() {
+		spans[s.Name] = s
+	}
+	require.Contains(t, spans, "POST /characters")
+	require.Contains(t, spans, "HTTP GET")
+	require.Contains(t, spans, "repository.CreateCharacter")
+
+	root := spans["POST /characters"]
+	assert.Equal(t, trace.SpanKindServer, root.SpanKind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", root.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", root.Parent.SpanID().String())
+	assert.True(t, root.Parent.IsRemote())
+
+	client := spans["HTTP GET"]
+	assert.Equal(t, trace.SpanKindClient, client.SpanKind)
+	assert.Equal(t, root.SpanContext.SpanID(), client.Parent.SpanID())
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+client.SpanContext.SpanID().String()+"-01", swapiParent)
+
+	assert.Equal(t, root.SpanContext.SpanID(), spans["repository.CreateCharacter"].Parent.SpanID())
+}
+
+func TestUnknownExporter(t *testing.T) {
+	cfg := config.Default()
+	cfg.Tracing.Exporter = "zipkin"
+	assert.ErrorContains(t, tracing.Init(fxtest.NewLifecycle(t), cfg), "zipkin")
+}
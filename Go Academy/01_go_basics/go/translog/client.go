@@ -0,0 +1,105 @@
+package translog
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type InclusionProof struct {
+	LeafIndex int      `json:"leaf_index"`
+	TreeSize  int      `json:"tree_size"`
+	AuditPath [][]byte `json:"audit_path"`
+}
+
+type ConsistencyProof struct {
+	First       int      `json:"first"`
+	Second      int      `json:"second"`
+	Consistency [][]byte `json:"consistency"`
+}
+
+// Client fetches tree heads and proofs from the /log endpoints of the API.
+type Client struct {
+	BaseURL string
+	HTTP    *http.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: baseURL, HTTP: http.DefaultClient}
+}
+
+func (c *Client) SignedTreeHead() (SignedTreeHead, error) {
+	var sth SignedTreeHead
+	err := c.get("/log/sth", nil, &sth)
+	return sth, err
+}
+
+func (c *Client) InclusionProof(leafHash []byte, size int) (InclusionProof, error) {
+	var proof InclusionProof
+	query := url.Values{
+		"hash":      {EncodeHash(leafHash)},
+		"tree_size": {strconv.Itoa(size)},
+	}
+	err := c.get("/log/proof/inclusion", query, &proof)
+	return proof, err
+}
+
+func (c *Client) ConsistencyProof(first, second int) (ConsistencyProof, error) {
+	var proof ConsistencyProof
+	query := url.Values{
+		"first":  {strconv.Itoa(first)},
+		"second": {strconv.Itoa(second)},
+	}
+	err := c.get("/log/proof/consistency", query, &proof)
+	return proof, err
+}
+
+func (c *Client) get(path string, query url.Values, out any) error {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	resp, err := c.HTTP.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// VerifyCertificate checks that the log has a correctly signed tree head and
+// that the certificate is included in it.
+func VerifyCertificate(c *Client, der []byte, pub *rsa.PublicKey) (SignedTreeHead, error) {
+	sth, err := c.SignedTreeHead()
+	if err != nil {
+		return sth, err
+	}
+	if err := VerifySignedTreeHead(sth, pub); err != nil {
+		return sth, fmt.Errorf("tree head signature: %w", err)
+	}
+	hash := LeafHash(der)
+	proof, err := c.InclusionProof(hash, sth.TreeSize)
+	if err != nil {
+		return sth, err
+	}
+	if err := VerifyInclusion(hash, proof.LeafIndex, sth.TreeSize, proof.AuditPath, sth.RootHash); err != nil {
+		return sth, fmt.Errorf("inclusion: %w", err)
+	}
+	return sth, nil
+}
+
+// VerifyExtends checks that the current tree head of the log is consistent
+// with an older tree head the caller trusted before.
+func VerifyExtends(c *Client, old, current SignedTreeHead) error {
+	proof, err := c.ConsistencyProof(old.TreeSize, current.TreeSize)
+	if err != nil {
+		return err
+	}
+	return VerifyConsistency(old.TreeSize, current.TreeSize, old.RootHash, current.RootHash, proof.Consistency)
+}
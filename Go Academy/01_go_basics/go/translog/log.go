@@ -0,0 +1,226 @@
+package translog
+
+import (
+	"bufio"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"example.com/go_basics/go/pki"
+	"go.uber.org/fx"
+)
+
+var (
+	ErrNotFound = errors.New("certificate not found in log")
+	ErrTreeSize = errors.New("invalid tree size")
+)
+
+// SignedTreeHead commits the log to a root hash at a given size.
+type SignedTreeHead struct {
+	TreeSize  int    `json:"tree_size"`
+	Timestamp int64  `json:"timestamp"`
+	RootHash  []byte `json:"root_hash"`
+	Signature []byte `json:"signature"`
+}
+
+// signedData serializes the RFC 6962 TreeHeadSignature structure.
+func (s SignedTreeHead) signedData() []byte {
+	buf := []byte{0, 1} // v1, tree_hash
+	buf = binary.BigEndian.AppendUint64(buf, uint64(s.Timestamp))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(s.TreeSize))
+	return append(buf, s.RootHash...)
+}
+
+func VerifySignedTreeHead(sth SignedTreeHead, pub *rsa.PublicKey) error {
+	digest := sha256.Sum256(sth.signedData())
+	return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sth.Signature)
+}
+
+// Log is an append-only Merkle tree of DER certificates. Entries are kept
+// as one base64 line each in a file, so the tree is rebuilt on restart.
+type Log struct {
+	mu       sync.RWMutex
+	file     *os.File
+	signer   *rsa.PrivateKey
+	leaves   [][]byte
+	byHash   map[string]int
+	bySerial map[string]int
+}
+
+// New opens the log in the CA directory, signs tree heads with the CA key
+// and records every certificate the authority issues.
+func New(lc fx.Lifecycle, ca *pki.Authority) (*Log, error) {
+	_, key, err := pki.LoadCertAndKey(ca.CAPath())
+	if err != nil {
+		return nil, fmt.Errorf("transparency log needs the CA key: %w", err)
+	}
+	l, err := Open(filepath.Join(ca.Dir, "log", "entries"), key)
+	if err != nil {
+		return nil, err
+	}
+	ca.OnIssue(func(cert *x509.Certificate) error {
+		_, err := l.Append(cert.Raw)
+		return err
+	})
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return l.Close()
+		},
+	})
+	return l, nil
+}
+
+func Open(path string, signer *rsa.PrivateKey) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	l := &Log{
+		file:     f,
+		signer:   signer,
+		byHash:   make(map[string]int),
+		bySerial: make(map[string]int),
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		der, err := base64.StdEncoding.DecodeString(scanner.Text())
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("corrupt log entry %d: %w", len(l.leaves), err)
+		}
+		l.add(der)
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Append records a certificate and returns its leaf index. Logging the same
+// certificate twice returns the existing index.
+func (l *Log) Append(der []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if i, ok := l.byHash[string(LeafHash(der))]; ok {
+		return i, nil
+	}
+	if _, err := l.file.WriteString(base64.StdEncoding.EncodeToString(der) + "\n"); err != nil {
+		return 0, err
+	}
+	if err := l.file.Sync(); err != nil {
+		return 0, err
+	}
+	return l.add(der), nil
+}
+
+func (l *Log) add(der []byte) int {
+	i := len(l.leaves)
+	hash := LeafHash(der)
+	l.leaves = append(l.leaves, hash)
+	l.byHash[string(hash)] = i
+	if cert, err := x509.ParseCertificate(der); err == nil {
+		l.bySerial[cert.SerialNumber.String()] = i
+	}
+	return i
+}
+
+func (l *Log) Size() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.leaves)
+}
+
+func (l *Log) SignedTreeHead() (SignedTreeHead, error) {
+	l.mu.RLock()
+	sth := SignedTreeHead{
+		TreeSize:  len(l.leaves),
+		Timestamp: time.Now().UnixMilli(),
+		RootHash:  rootHash(l.leaves),
+	}
+	l.mu.RUnlock()
+	digest := sha256.Sum256(sth.signedData())
+	sig, err := rsa.SignPKCS1v15(rand.Reader, l.signer, crypto.SHA256, digest[:])
+	if err != nil {
+		return SignedTreeHead{}, err
+	}
+	sth.Signature = sig
+	return sth, nil
+}
+
+// LeafIndex finds a leaf by its hash.
+func (l *Log) LeafIndex(hash []byte) (int, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if i, ok := l.byHash[string(hash)]; ok {
+		return i, nil
+	}
+	return 0, ErrNotFound
+}
+
+// SerialIndex finds a leaf by the serial number of the logged certificate.
+func (l *Log) SerialIndex(serial string) (int, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if i, ok := l.bySerial[serial]; ok {
+		return i, nil
+	}
+	return 0, ErrNotFound
+}
+
+// LeafHash returns the hash of the leaf at index.
+func (l *Log) LeafHash(index int) ([]byte, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if index < 0 || index >= len(l.leaves) {
+		return nil, fmt.Errorf("%w: no leaf at index %d of %d", ErrNotFound, index, len(l.leaves))
+	}
+	return l.leaves[index], nil
+}
+
+// InclusionProof returns the audit path for the leaf at index in the tree
+// of the given size.
+func (l *Log) InclusionProof(index, size int) ([][]byte, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if size < 1 || size > len(l.leaves) || index < 0 || index >= size {
+		return nil, ErrTreeSize
+	}
+	return inclusionPath(index, l.leaves[:size])
+}
+
+// ConsistencyProof proves that the tree of size second extends the tree of
+// size first.
+func (l *Log) ConsistencyProof(first, second int) ([][]byte, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if first < 0 || first > second || second > len(l.leaves) {
+		return nil, ErrTreeSize
+	}
+	if first == 0 {
+		return nil, nil
+	}
+	return consistencyPath(first, l.leaves[:second])
+}
+
+// Close flushes the log file to disk and closes it.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return errors.Join(l.file.Sync(), l.file.Close())
+}
@@ -0,0 +1,107 @@
+package translog_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/handlers"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/routes"
+	"example.com/go_basics/go/translog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLogPersistsAcrossRestarts(t *testing.T) {
+	_, key, err := pki.Issue(pki.CATemplate(), nil, nil)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "log", "entries")
+
+	l, err := translog.Open(path, key)
+	require.NoError(t, err)
+	for _, e := range []string{"a", "b", "c"} {
+		_, err := l.Append([]byte(e))
+		require.NoError(t, err)
+	}
+	i, err := l.Append([]byte("b"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, i)
+	before, err := l.SignedTreeHead()
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+
+	l, err = translog.Open(path, key)
+	require.NoError(t, err)
+	defer l.Close()
+	assert.Equal(t, 3, l.Size())
+	_, err = l.Append([]byte("d"))
+	require.NoError(t, err)
+
+	after, err := l.SignedTreeHead()
+	require.NoError(t, err)
+	assert.NoError(t, translog.VerifySignedTreeHead(after, &key.PublicKey))
+	proof, err := l.ConsistencyProof(before.TreeSize, after.TreeSize)
+	require.NoError(t, err)
+	assert.NoError(t, translog.VerifyConsistency(before.TreeSize, after.TreeSize, before.RootHash, after.RootHash, proof))
+
+	after.TreeSize++
+	assert.Error(t, translog.VerifySignedTreeHead(after, &key.PublicKey))
+
+	hash, err := l.LeafHash(3)
+	require.NoError(t, err)
+	assert.Equal(t, translog.LeafHash([]byte("d")), hash)
+	for _, i := range []int{-1, 4} {
+		_, err := l.LeafHash(i)
+		assert.ErrorIs(t, err, translog.ErrNotFound)
+	}
+	_, err = l.InclusionProof(4, 4)
+	assert.ErrorIs(t, err, translog.ErrTreeSize)
+	_, err = l.ConsistencyProof(2, 5)
+	assert.ErrorIs(t, err, translog.ErrTreeSize)
+}
+
+func TestVerifyIssuedCertificate(t *testing.T) {
+	ca := &pki.Authority{Dir: t.TempDir()}
+	caCert, caKey, err := pki.Issue(pki.CATemplate(), nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(ca.CAPath(), pki.EncodeCert(caCert), 0644))
+	require.NoError(t, os.WriteFile(pki.KeyPath(ca.CAPath()), pki.EncodeKey(caKey), 0600))
+
+	l, err := translog.Open(filepath.Join(ca.Dir, "log", "entries"), caKey)
+	require.NoError(t, err)
+	defer l.Close()
+	ca.OnIssue(func(cert *x509.Certificate) error {
+		_, err := l.Append(cert.Raw)
+		return err
+	})
+
+	server := httptest.NewServer(routes.NewEchoRouter(handlers.New(repository.New(db.New(), nil, nil, nil), ca, l, nil, nil, nil, nil), nil, zap.NewNop(), nil, nil, nil, nil))
+	defer server.Close()
+	client := translog.NewClient(server.URL)
+
+	first, err := ca.ServerTLSConfig("localhost")
+	require.NoError(t, err)
+	old, err := client.SignedTreeHead()
+	require.NoError(t, err)
+	second, err := ca.ServerTLSConfig("localhost")
+	require.NoError(t, err)
+
+	for _, cfg := range []*tls.Config{first, second} {
+		_, err := translog.VerifyCertificate(client, cfg.Certificates[0].Leaf.Raw, &caKey.PublicKey)
+		assert.NoError(t, err)
+	}
+	current, err := client.SignedTreeHead()
+	require.NoError(t, err)
+	assert.Equal(t, 2, current.TreeSize)
+	assert.NoError(t, translog.VerifyExtends(client, old, current))
+
+	_, err = translog.VerifyCertificate(client, []byte("never issued"), &caKey.PublicKey)
+	assert.Error(t, err)
+}
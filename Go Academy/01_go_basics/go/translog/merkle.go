@@ -0,0 +1,188 @@
+package translog
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// Hashing follows RFC 6962: leaves and interior nodes use distinct prefixes
+// so a leaf can never be confused with a subtree.
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+var ErrInvalidProof = errors.New("invalid Merkle proof")
+
+func LeafHash(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func EncodeHash(hash []byte) string {
+	return base64.StdEncoding.EncodeToString(hash)
+}
+
+func DecodeHash(s string) ([]byte, error) {
+	hash, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(hash) != sha256.Size {
+		return nil, errors.New("hash must be a base64 encoded SHA-256 digest")
+	}
+	return hash, nil
+}
+
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// splitPoint returns the largest power of two smaller than n.
+func splitPoint(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+func rootHash(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		h := sha256.Sum256(nil)
+		return h[:]
+	case 1:
+		return leaves[0]
+	}
+	k := splitPoint(len(leaves))
+	return nodeHash(rootHash(leaves[:k]), rootHash(leaves[k:]))
+}
+
+// inclusionPath returns the audit path of leaf m in the tree of leaves,
+// PATH(m, D[n]) of RFC 6962.
+func inclusionPath(m int, leaves [][]byte) ([][]byte, error) {
+	if m < 0 || m >= len(leaves) {
+		return nil, fmt.Errorf("%w: no leaf %d in a tree of %d", ErrTreeSize, m, len(leaves))
+	}
+	return auditPath(m, leaves), nil
+}
+
+func auditPath(m int, leaves [][]byte) [][]byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+	k := splitPoint(len(leaves))
+	if m < k {
+		return append(auditPath(m, leaves[:k]), rootHash(leaves[k:]))
+	}
+	return append(auditPath(m-k, leaves[k:]), rootHash(leaves[:k]))
+}
+
+// consistencyPath proves that the tree of the first m leaves is a prefix of
+// the tree of leaves, PROOF(m, D[n]) of RFC 6962.
+func consistencyPath(m int, leaves [][]byte) ([][]byte, error) {
+	if m < 1 || m > len(leaves) {
+		return nil, fmt.Errorf("%w: no tree of %d in a tree of %d", ErrTreeSize, m, len(leaves))
+	}
+	return subproof(m, leaves, true), nil
+}
+
+func subproof(m int, leaves [][]byte, complete bool) [][]byte {
+	n := len(leaves)
+	if m == n {
+		if complete {
+			return nil
+		}
+		return [][]byte{rootHash(leaves)}
+	}
+	k := splitPoint(n)
+	if m <= k {
+		return append(subproof(m, leaves[:k], complete), rootHash(leaves[k:]))
+	}
+	return append(subproof(m-k, leaves[k:], false), rootHash(leaves[:k]))
+}
+
+// VerifyInclusion checks that leafHash is the entry at index in the tree of
+// the given size and root, using the algorithm of RFC 9162 section 2.1.3.2.
+func VerifyInclusion(leafHash []byte, index, size int, proof [][]byte, root []byte) error {
+	if index < 0 || index >= size {
+		return ErrInvalidProof
+	}
+	fn, sn := index, size-1
+	r := leafHash
+	for _, p := range proof {
+		if sn == 0 {
+			return ErrInvalidProof
+		}
+		if fn&1 == 1 || fn == sn {
+			r = nodeHash(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = nodeHash(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 || !bytes.Equal(r, root) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// VerifyConsistency checks that the tree of size second with root2 extends
+// the tree of size first with root1, following RFC 9162 section 2.1.4.2.
+func VerifyConsistency(first, second int, root1, root2 []byte, proof [][]byte) error {
+	switch {
+	case first < 0 || first > second:
+		return ErrInvalidProof
+	case first == second:
+		if len(proof) != 0 || !bytes.Equal(root1, root2) {
+			return ErrInvalidProof
+		}
+		return nil
+	case first == 0:
+		return nil
+	case len(proof) == 0:
+		return ErrInvalidProof
+	}
+	if first&(first-1) == 0 {
+		proof = append([][]byte{root1}, proof...)
+	}
+	fn, sn := first-1, second-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+	fr, sr := proof[0], proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return ErrInvalidProof
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = nodeHash(c, fr)
+			sr = nodeHash(c, sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = nodeHash(sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 || !bytes.Equal(fr, root1) || !bytes.Equal(sr, root2) {
+		return ErrInvalidProof
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package translog
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLeaves(n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		leaves[i] = LeafHash([]byte(fmt.Sprintf("certificate %d", i)))
+	}
+	return leaves
+}
+
+func TestInclusionProofs(t *testing.T) {
+	for size := 1; size <= 17; size++ {
+		leaves := testLeaves(size)
+		root := rootHash(leaves)
+		for i := 0; i < size; i++ {
+			proof, err := inclusionPath(i, leaves)
+			require.NoError(t, err)
+			require.NoError(t, VerifyInclusion(leaves[i], i, size, proof, root), "size %d index %d", size, i)
+			if size > 1 {
+				assert.Error(t, VerifyInclusion(leaves[(i+1)%size], i, size, proof, root))
+			}
+		}
+		assert.Error(t, VerifyInclusion(leaves[0], size, size, nil, root))
+		for _, i := range []int{-1, size} {
+			_, err := inclusionPath(i, leaves)
+			assert.ErrorIs(t, err, ErrTreeSize, "size %d index %d", size, i)
+		}
+	}
+}
+
+func TestConsistencyProofs(t *testing.T) {
+	leaves := testLeaves(17)
+	for second := 1; second <= len(leaves); second++ {
+		root2 := rootHash(leaves[:second])
+		for first := 1; first <= second; first++ {
+			root1 := rootHash(leaves[:first])
+			var proof [][]byte
+			if first < second {
+				var err error
+				proof, err = consistencyPath(first, leaves[:second])
+				require.NoError(t, err)
+			}
+			require.NoError(t, VerifyConsistency(first, second, root1, root2, proof), "%d -> %d", first, second)
+			if first < second {
+				assert.Error(t, VerifyConsistency(first, second, LeafHash([]byte("forged")), root2, proof))
+			}
+		}
+		for _, first := range []int{0, second + 1} {
+			_, err := consistencyPath(first, leaves[:second])
+			assert.ErrorIs(t, err, ErrTreeSize, "%d -> %d", first, second)
+		}
+	}
+}
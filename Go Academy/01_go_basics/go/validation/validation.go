@@ -0,0 +1,262 @@
+package validation
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"example.com/go_basics/go/problem"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func init() {
+	openapi3.DefineStringFormatValidator("uuid", openapi3.NewCallbackValidator(func(s string) error {
+		_, err := uuid.Parse(s)
+		return err
+	}))
+	// Pages such as GraphiQL are checked as strings.
+	openapi3filter.RegisterBodyDecoder("text/html", openapi3filter.PlainBodyDecoder)
+	// So are import and export files, whose records the import reports on
+	// by line.
+	openapi3filter.RegisterBodyDecoder("application/x-ndjson", openapi3filter.PlainBodyDecoder)
+	openapi3filter.RegisterBodyDecoder("text/csv", openapi3filter.PlainBodyDecoder)
+}
+
+var options = &openapi3filter.Options{
+	MultiError:         true,
+	AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+}
+
+// Middleware rejects requests that do not match the operation spec declares
+// for the matched echo route. Routes missing from the spec pass through.
+func Middleware(spec *openapi3.T) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			input, ok := requestInput(spec, c)
+			if !ok {
+				return next(c)
+			}
+			if err := openapi3filter.ValidateRequest(c.Request().Context(), input); err != nil {
+				return requestProblem(err)
+			}
+			return next(c)
+		}
+	}
+}
+
+// ResponseMiddleware fails responses that do not match the spec with a 500
+// problem. It buffers every response, so it is meant for tests.
+func ResponseMiddleware(spec *openapi3.T) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			input, ok := requestInput(spec, c)
+			if !ok {
+				return next(c)
+			}
+
+			res := c.Response()
+			original := res.Writer
+			buf := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+			res.Writer = buf
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+			res.Writer = original
+
+			err := openapi3filter.ValidateResponse(c.Request().Context(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 buf.status,
+				Header:                 res.Header(),
+				Body:                   io.NopCloser(bytes.NewReader(buf.body.Bytes())),
+				Options:                options,
+			})
+			if err != nil {
+				res.Committed = false
+				res.Header().Del(echo.HeaderContentType)
+				return problem.Newf(http.StatusInternalServerError, "response does not match the API spec: %v", err)
+			}
+			original.WriteHeader(buf.status)
+			_, writeErr := original.Write(buf.body.Bytes())
+			return writeErr
+		}
+	}
+}
+
+// Operation returns the spec operation of the route echo matched, or nil for
+// routes missing from the spec.
+func Operation(spec *openapi3.T, c echo.Context) *openapi3.Operation {
+	input, ok := requestInput(spec, c)
+	if !ok {
+		return nil
+	}
+	return input.Route.Operation
+}
+
+// requestInput finds the spec operation for the route echo matched.
+func requestInput(spec *openapi3.T, c echo.Context) (*openapi3filter.RequestValidationInput, bool) {
+	path := specPath(c.Path())
+	item := spec.Paths.Value(path)
+	if item == nil {
+		return nil, false
+	}
+	method := c.Request().Method
+	op := item.GetOperation(method)
+	if op == nil {
+		return nil, false
+	}
+	params := make(map[string]string, len(c.ParamNames()))
+	for i, name := range c.ParamNames() {
+		params[name] = c.ParamValues()[i]
+	}
+	return &openapi3filter.RequestValidationInput{
+		Request:    c.Request(),
+		PathParams: params,
+		Route: &routers.Route{
+			Spec:      spec,
+			Path:      path,
+			PathItem:  item,
+			Method:    method,
+			Operation: op,
+		},
+		Options: options,
+	}, true
+}
+
+// specPath turns an echo route such as /characters/:id into /characters/{id}.
+func specPath(route string) string {
+	segments := strings.Split(route, "/")
+	for i, s := range segments {
+		if name, ok := strings.CutPrefix(s, ":"); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// requestProblem reports every offending parameter and body field. Errors
+// that do not concern a single field, such as malformed JSON, are reported
+// without a field list.
+func requestProblem(err error) *problem.Problem {
+	var fields []problem.FieldError
+	for _, re := range requestErrors(err) {
+		switch {
+		case re.Parameter != nil:
+			fields = append(fields, problem.FieldError{Field: re.Parameter.Name, Message: parameterMessage(re.Err)})
+		case re.RequestBody != nil:
+			if errors.Is(re.Err, openapi3filter.ErrInvalidRequired) {
+				return problem.New(http.StatusBadRequest, "Request body is required")
+			}
+			schemaErrs := schemaErrors(re.Err)
+			if len(schemaErrs) == 0 {
+				return problem.New(http.StatusBadRequest, "Invalid request body")
+			}
+			for _, se := range schemaErrs {
+				fields = append(fields, problem.FieldError{Field: strings.Join(se.JSONPointer(), "."), Message: schemaMessage(se)})
+			}
+		default:
+			return problem.New(http.StatusBadRequest, re.Error())
+		}
+	}
+	if len(fields) == 0 {
+		return problem.New(http.StatusBadRequest, err.Error())
+	}
+	return problem.Validation(fields)
+}
+
+// requestErrors flattens the errors of ValidateRequest. A RequestError wraps
+// a MultiError of its own, so errors.As would descend too far.
+func requestErrors(err error) []*openapi3filter.RequestError {
+	switch err := err.(type) {
+	case openapi3.MultiError:
+		var out []*openapi3filter.RequestError
+		for _, e := range err {
+			out = append(out, requestErrors(e)...)
+		}
+		return out
+	case *openapi3filter.RequestError:
+		return []*openapi3filter.RequestError{err}
+	}
+	return nil
+}
+
+func schemaErrors(err error) []*openapi3.SchemaError {
+	switch err := err.(type) {
+	case openapi3.MultiError:
+		var out []*openapi3.SchemaError
+		for _, e := range err {
+			out = append(out, schemaErrors(e)...)
+		}
+		return out
+	case *openapi3.SchemaError:
+		return []*openapi3.SchemaError{err}
+	}
+	return nil
+}
+
+func parameterMessage(err error) string {
+	switch {
+	case errors.Is(err, openapi3filter.ErrInvalidRequired):
+		return "is required"
+	case errors.Is(err, openapi3filter.ErrInvalidEmptyValue):
+		return "must not be empty"
+	}
+	if schemaErrs := schemaErrors(err); len(schemaErrs) > 0 {
+		return schemaMessage(schemaErrs[0])
+	}
+	return "has an invalid value"
+}
+
+func schemaMessage(se *openapi3.SchemaError) string {
+	s := se.Schema
+	switch se.SchemaField {
+	case "required":
+		return "is required"
+	case "minLength":
+		if s.MinLength == 1 {
+			return "must not be empty"
+		}
+		return fmt.Sprintf("must be at least %d characters", s.MinLength)
+	case "maxLength":
+		return fmt.Sprintf("must be at most %d characters", *s.MaxLength)
+	case "minimum":
+		return fmt.Sprintf("must be at least %g", *s.Min)
+	case "maximum":
+		return fmt.Sprintf("must be at most %g", *s.Max)
+	case "format":
+		if s.Format == "uuid" {
+			return "must be a valid UUID"
+		}
+		return "must be a valid " + s.Format
+	case "type":
+		return "must be of type " + strings.Join(s.Type.Slice(), " or ")
+	case "enum":
+		values := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			values[i] = fmt.Sprint(v)
+		}
+		return "must be one of: " + strings.Join(values, " ")
+	}
+	return se.Reason
+}
+
+// bufferedWriter holds the response until it has been validated.
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
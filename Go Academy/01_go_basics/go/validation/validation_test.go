@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/problem"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEcho(t *testing.T) *echo.Echo {
+	spec, err := api.GetSwagger()
+	require.NoError(t, err)
+	e := echo.New()
+	e.HTTPErrorHandler = func(err error, c echo.Context) {
+		p, ok := problem.From(err)
+		require.True(t, ok, err)
+		require.NoError(t, problem.Write(c, p))
+	}
+	e.Use(Middleware(spec), ResponseMiddleware(spec))
+	return e
+}
+
+func serve(t *testing.T, e *echo.Echo, method, target, body string) (*httptest.ResponseRecorder, problem.Problem) {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	var p problem.Problem
+	if rec.Header().Get("Content-Type") == problem.ContentType {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	}
+	return rec, p
+}
+
+func TestSpecPath(t *testing.T) {
+	assert.Equal(t, "/characters/{id}", specPath("/characters/:id"))
+	assert.Equal(t, "/movies/by-character", specPath("/movies/by-character"))
+}
+
+func TestRequestValidation(t *testing.T) {
+	e := newTestEcho(t)
+	e.POST("/appearances", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	e.GET("/log/proof/consistency", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, api.ConsistencyProof{First: 1, Second: 1, Consistency: [][]byte{}})
+	})
+	e.GET("/unspecified", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+
+	rec, p := serve(t, e, http.MethodPost, "/appearances", `{"movie_id":"shrek"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, problem.TypeValidation, p.Type)
+	assert.ElementsMatch(t, []problem.FieldError{
+		{Field: "character_id", Message: "is required"},
+		{Field: "movie_id", Message: "must be a valid UUID"},
+	}, p.Errors)
+
+	rec, p = serve(t, e, http.MethodPost, "/appearances", `{"movie_id":`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "Invalid request body", p.Detail)
+
+	rec, p = serve(t, e, http.MethodGet, "/log/proof/consistency?first=one", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "first", p.Errors[0].Field)
+
+	rec, _ = serve(t, e, http.MethodGet, "/log/proof/consistency?first=1", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec, _ = serve(t, e, http.MethodGet, "/unspecified", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestResponseValidation(t *testing.T) {
+	e := newTestEcho(t)
+	e.GET("/log/sth", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, echo.Map{"tree_size": "seven"})
+	})
+	e.GET("/certificates", func(c echo.Context) error {
+		return problem.New(http.StatusServiceUnavailable, "certificates unavailable")
+	})
+
+	rec, p := serve(t, e, http.MethodGet, "/log/sth", "")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, p.Detail, "response does not match the API spec")
+
+	rec, p = serve(t, e, http.MethodGet, "/certificates", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "certificates unavailable", p.Detail)
+}
@@ -0,0 +1,455 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/events"
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// Headers of a delivery request.
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+)
+
+// secretPrefix marks the signing secrets of this server.
+const secretPrefix = "whsec_"
+
+// logSize is the number of deliveries kept per webhook.
+const logSize = 100
+
+// queueSize bounds the deliveries waiting for a worker. When it is full the
+// dispatcher stops reading the bus and catches up from its replay buffer.
+const queueSize = 1024
+
+var (
+	ErrNotFound           = errors.New("webhook not found")
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	ErrInvalid            = errors.New("invalid webhook")
+)
+
+// Webhook is a subscription to change events. Without Events it receives
+// every type.
+type Webhook struct {
+	ID        uuid.UUID
+	URL       string
+	Events    []events.Type
+	CreatedAt time.Time
+}
+
+func (w Webhook) wants(t events.Type) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, t)
+}
+
+// Delivery is one attempt to send an event.
+type Delivery struct {
+	ID        uuid.UUID
+	EventID   uint64
+	EventType events.Type
+	Attempt   int
+	// StatusCode is 0 when the receiver did not answer.
+	StatusCode int
+	// Error is empty for successful deliveries.
+	Error       string
+	Duration    time.Duration
+	DeliveredAt time.Time
+}
+
+// DeadLetter is an event that could not be delivered in MaxAttempts.
+type DeadLetter struct {
+	DeliveryID uuid.UUID
+	Event      events.Event
+	Attempts   int
+	LastError  string
+	FailedAt   time.Time
+}
+
+type hook struct {
+	Webhook
+	secret string
+	log    []Delivery // oldest first
+	dead   []DeadLetter
+}
+
+type job struct {
+	hookID     uuid.UUID
+	deliveryID uuid.UUID
+	event      events.Event
+	attempt    int
+}
+
+// Dispatcher follows the change events and POSTs them to the webhooks that
+// subscribed to their type. Failed deliveries are retried with exponential
+// backoff and end up in the dead-letter list of their webhook.
+type Dispatcher struct {
+	cfg    config.Webhooks
+	bus    *events.Bus
+	client *http.Client
+	logger *zap.Logger
+
+	mu    sync.Mutex
+	hooks map[uuid.UUID]*hook
+
+	queue chan job
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// New starts the dispatcher with the app and stops it with the app.
+func New(lc fx.Lifecycle, cfg *config.Config, bus *events.Bus, logger *zap.Logger) *Dispatcher {
+	d := NewDispatcher(cfg, bus, logger)
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			d.Start()
+			return nil
+		},
+		OnStop: d.Stop,
+	})
+	return d
+}
+
+func NewDispatcher(cfg *config.Config, bus *events.Bus, logger *zap.Logger) *Dispatcher {
+	return &Dispatcher{
+		cfg: cfg.Webhooks,
+		bus: bus,
+		client: &http.Client{
+			Timeout: cfg.Webhooks.Timeout,
+			// A redirect counts as a failed delivery rather than sending
+			// the event somewhere the subscriber did not register.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		logger: logger.Named("webhooks"),
+		hooks:  make(map[uuid.UUID]*hook),
+		queue:  make(chan job, queueSize),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start runs the workers and begins following the bus. Events published
+// after Start returns are delivered.
+func (d *Dispatcher) Start() {
+	for range d.cfg.Workers {
+		d.wg.Add(1)
+		go d.work()
+	}
+	sub, _ := d.bus.Subscribe(0)
+	d.wg.Add(1)
+	go d.follow(sub)
+}
+
+// Stop lets running deliveries finish. Queued deliveries and pending
+// retries are dropped.
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	close(d.stop)
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Create subscribes url to the given event types, or to all of them when
+// none are given. The returned secret signs the deliveries and is the only
+// copy.
+func (d *Dispatcher) Create(rawURL string, types []events.Type) (Webhook, string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Webhook{}, "", fmt.Errorf("%w: %q is not an absolute http(s) URL", ErrInvalid, rawURL)
+	}
+	for _, t := range types {
+		if !slices.Contains(events.Types, t) {
+			return Webhook{}, "", fmt.Errorf("%w: unknown event type %q", ErrInvalid, t)
+		}
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return Webhook{}, "", err
+	}
+	h := &hook{
+		Webhook: Webhook{
+			ID:        uuid.New(),
+			URL:       u.String(),
+			Events:    slices.Clone(types),
+			CreatedAt: time.Now().UTC(),
+		},
+		secret: secretPrefix + base64.RawURLEncoding.EncodeToString(b),
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.hooks[h.ID] = h
+	return h.Webhook, h.secret, nil
+}
+
+// List returns every webhook, oldest first.
+func (d *Dispatcher) List() []Webhook {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]Webhook, 0, len(d.hooks))
+	for _, h := range d.hooks {
+		out = append(out, h.Webhook)
+	}
+	slices.SortFunc(out, func(a, b Webhook) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return out
+}
+
+func (d *Dispatcher) Get(id uuid.UUID) (Webhook, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	h, err := d.hook(id)
+	if err != nil {
+		return Webhook{}, err
+	}
+	return h.Webhook, nil
+}
+
+// Delete unsubscribes a webhook. Its queued deliveries are dropped.
+func (d *Dispatcher) Delete(id uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, err := d.hook(id); err != nil {
+		return err
+	}
+	delete(d.hooks, id)
+	return nil
+}
+
+// Deliveries returns the latest delivery attempts of a webhook, newest first.
+func (d *Dispatcher) Deliveries(id uuid.UUID) ([]Delivery, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	h, err := d.hook(id)
+	if err != nil {
+		return nil, err
+	}
+	out := slices.Clone(h.log)
+	slices.Reverse(out)
+	return out, nil
+}
+
+// DeadLetters returns the events a webhook gave up on, oldest first.
+func (d *Dispatcher) DeadLetters(id uuid.UUID) ([]DeadLetter, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	h, err := d.hook(id)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(h.dead), nil
+}
+
+// Redeliver takes a dead letter off the list and sends its event again,
+// with a fresh set of attempts.
+func (d *Dispatcher) Redeliver(id, deliveryID uuid.UUID) error {
+	d.mu.Lock()
+	h, err := d.hook(id)
+	if err != nil {
+		d.mu.Unlock()
+		return err
+	}
+	i := slices.IndexFunc(h.dead, func(dl DeadLetter) bool { return dl.DeliveryID == deliveryID })
+	if i < 0 {
+		d.mu.Unlock()
+		return fmt.Errorf("%w [ID: %s]", ErrDeadLetterNotFound, deliveryID)
+	}
+	dl := h.dead[i]
+	h.dead = slices.Delete(h.dead, i, i+1)
+	d.mu.Unlock()
+	d.enqueue(job{hookID: id, deliveryID: deliveryID, event: dl.Event, attempt: 1})
+	return nil
+}
+
+// hook must be called with d.mu held.
+func (d *Dispatcher) hook(id uuid.UUID) (*hook, error) {
+	h, ok := d.hooks[id]
+	if !ok {
+		return nil, fmt.Errorf("%w [ID: %s]", ErrNotFound, id)
+	}
+	return h, nil
+}
+
+// follow turns events into deliveries. When it falls behind the bus drops
+// it, and it resubscribes after the last event it handled.
+func (d *Dispatcher) follow(sub *events.Subscription) {
+	defer d.wg.Done()
+	var lastID uint64
+	for {
+	receive:
+		for {
+			select {
+			case <-d.stop:
+				sub.Close()
+				return
+			case e, ok := <-sub.C:
+				if !ok {
+					break receive
+				}
+				lastID = d.dispatch(e)
+			}
+		}
+		if d.bus.Closed() {
+			return
+		}
+		var replay []events.Event
+		sub, replay = d.bus.Subscribe(lastID)
+		for _, e := range replay {
+			lastID = d.dispatch(e)
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(e events.Event) uint64 {
+	if e.Type == events.Reset {
+		d.logger.Warn("events were lost before they could be delivered", zap.Uint64("last_event_id", e.ID))
+		return e.ID
+	}
+	d.mu.Lock()
+	var jobs []job
+	for _, h := range d.hooks {
+		if h.wants(e.Type) {
+			jobs = append(jobs, job{hookID: h.ID, deliveryID: uuid.New(), event: e, attempt: 1})
+		}
+	}
+	d.mu.Unlock()
+	for _, j := range jobs {
+		d.enqueue(j)
+	}
+	return e.ID
+}
+
+func (d *Dispatcher) enqueue(j job) {
+	select {
+	case d.queue <- j:
+	case <-d.stop:
+	}
+}
+
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-d.stop:
+			return
+		case j := <-d.queue:
+			d.deliver(j)
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(j job) {
+	d.mu.Lock()
+	h, ok := d.hooks[j.hookID]
+	var target, secret string
+	if ok {
+		target, secret = h.URL, h.secret
+	}
+	d.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	start := time.Now()
+	status, err := d.send(target, secret, j)
+	delivery := Delivery{
+		ID:          j.deliveryID,
+		EventID:     j.event.ID,
+		EventType:   j.event.Type,
+		Attempt:     j.attempt,
+		StatusCode:  status,
+		Duration:    time.Since(start),
+		DeliveredAt: start.UTC(),
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if h, ok = d.hooks[j.hookID]; !ok {
+		return
+	}
+	h.log = append(h.log, delivery)
+	if len(h.log) > logSize {
+		h.log = slices.Delete(h.log, 0, len(h.log)-logSize)
+	}
+	if err == nil {
+		return
+	}
+	if j.attempt >= d.cfg.MaxAttempts {
+		d.logger.Warn("webhook delivery failed for good", zap.Stringer("webhook_id", j.hookID), zap.Uint64("event_id", j.event.ID), zap.Error(err))
+		h.dead = append(h.dead, DeadLetter{
+			DeliveryID: j.deliveryID,
+			Event:      j.event,
+			Attempts:   j.attempt,
+			LastError:  delivery.Error,
+			FailedAt:   time.Now().UTC(),
+		})
+		return
+	}
+	retry := j
+	retry.attempt++
+	time.AfterFunc(d.backoff(j.attempt), func() { d.enqueue(retry) })
+}
+
+// backoff doubles the delay with every attempt, up to MaxBackoff. Half of
+// it is random, so receivers that come back are not hit by every retry at
+// once.
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.cfg.MaxBackoff
+	if attempt < 32 {
+		delay = min(d.cfg.MaxBackoff, d.cfg.Backoff<<(attempt-1))
+	}
+	return delay/2 + mathrand.N(delay/2+1)
+}
+
+func (d *Dispatcher) send(target, secret string, j job) (int, error) {
+	body, err := json.Marshal(j.event)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(j.event.Type))
+	req.Header.Set(HeaderDelivery, j.deliveryID.String())
+	req.Header.Set(HeaderSignature, Sign(secret, time.Now(), body))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
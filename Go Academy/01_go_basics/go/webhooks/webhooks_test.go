@@ -0,0 +1,176 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"example.com/go_basics/go/api"
+	"example.com/go_basics/go/config"
+	"example.com/go_basics/go/db"
+	"example.com/go_basics/go/events"
+	"example.com/go_basics/go/handlers"
+	"example.com/go_basics/go/pki"
+	"example.com/go_basics/go/repository"
+	"example.com/go_basics/go/routes"
+	"example.com/go_basics/go/validation"
+	"example.com/go_basics/go/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// newReceiver answers every delivery with the status returned by status and
+// hands the requests to the test.
+func newReceiver(t *testing.T, status func() int) (*httptest.Server, chan received) {
+	ch := make(chan received, 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ch <- received{header: r.Header, body: body}
+		w.WriteHeader(status())
+	}))
+	t.Cleanup(server.Close)
+	return server, ch
+}
+
+func newDispatcher(t *testing.T, maxAttempts int) (*webhooks.Dispatcher, *repository.Repository) {
+	cfg := config.Default()
+	cfg.Webhooks.MaxAttempts = maxAttempts
+	cfg.Webhooks.Backoff = 10 * time.Millisecond
+	cfg.Webhooks.MaxBackoff = 20 * time.Millisecond
+	bus := events.New(cfg)
+	d := webhooks.NewDispatcher(cfg, bus, zap.NewNop())
+	d.Start()
+	t.Cleanup(func() {
+		require.NoError(t, d.Stop(context.Background()))
+		bus.Close()
+	})
+	return d, repository.New(db.New(), nil, bus, nil)
+}
+
+func next(t *testing.T, ch chan received) received {
+	select {
+	case r := <-ch:
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("no delivery")
+		return received{}
+	}
+}
+
+func TestDeliveriesAreFilteredAndSigned(t *testing.T) {
+	cfg := config.Default()
+	bus := events.New(cfg)
+	d := webhooks.NewDispatcher(cfg, bus, zap.NewNop())
+	d.Start()
+	t.Cleanup(func() {
+		require.NoError(t, d.Stop(context.Background()))
+		bus.Close()
+	})
+	repo := repository.New(db.New(), nil, bus, nil)
+	e := routes.NewEchoRouter(handlers.New(repo, &pki.Authority{Dir: t.TempDir()}, nil, nil, nil, d, nil), nil, zap.NewNop(), nil, nil, nil, nil)
+	spec, err := api.GetSwagger()
+	require.NoError(t, err)
+	e.Use(validation.ResponseMiddleware(spec))
+	receiver, ch := newReceiver(t, func() int { return http.StatusNoContent })
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/admin/webhooks",
+		strings.NewReader(`{"url":"`+receiver.URL+`","events":["appearance.linked"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created api.CreatedWebhook
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.True(t, strings.HasPrefix(created.Secret, "whsec_"))
+
+	shrek, _ := repo.CreateMovie(t.Context(), "Shrek", 2001)
+	donkey, _ := repo.CreateCharacter(t.Context(), "Donkey")
+	require.NoError(t, repo.AddAppearance(t.Context(), shrek.ID, donkey.ID))
+	linked := bus.LastID()
+
+	r := next(t, ch)
+	assert.Equal(t, "appearance.linked", r.header.Get(webhooks.HeaderEvent))
+	require.NoError(t, webhooks.Verify(created.Secret, r.header.Get(webhooks.HeaderSignature), r.body, time.Minute))
+	assert.ErrorIs(t, webhooks.Verify("whsec_other", r.header.Get(webhooks.HeaderSignature), r.body, time.Minute), webhooks.ErrInvalidSignature)
+	var event events.Event
+	require.NoError(t, json.Unmarshal(r.body, &event))
+	assert.Equal(t, linked, event.ID)
+	select {
+	case r := <-ch:
+		t.Fatalf("unexpected %s delivery", r.header.Get(webhooks.HeaderEvent))
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/webhooks/"+created.Webhook.Id.String()+"/deliveries", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var log []api.WebhookDelivery
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &log))
+	require.Len(t, log, 1)
+	assert.Equal(t, r.header.Get(webhooks.HeaderDelivery), log[0].Id.String())
+	assert.Equal(t, http.StatusNoContent, *log[0].StatusCode)
+	assert.Nil(t, log[0].Error)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/webhooks", strings.NewReader(`{"url":"ftp://example.com"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRetriesThenDeadLetter(t *testing.T) {
+	d, repo := newDispatcher(t, 3)
+	var fail atomic.Bool
+	fail.Store(true)
+	receiver, ch := newReceiver(t, func() int {
+		if fail.Load() {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	})
+	w, _, err := d.Create(receiver.URL, nil)
+	require.NoError(t, err)
+
+	_, err = repo.CreateMovie(t.Context(), "Shrek", 2001)
+	require.NoError(t, err)
+	first := next(t, ch).header.Get(webhooks.HeaderDelivery)
+	for range 2 {
+		assert.Equal(t, first, next(t, ch).header.Get(webhooks.HeaderDelivery))
+	}
+
+	var dead []webhooks.DeadLetter
+	require.Eventually(t, func() bool {
+		dead, err = d.DeadLetters(w.ID)
+		return err == nil && len(dead) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 3, dead[0].Attempts)
+	assert.Equal(t, events.MovieCreated, dead[0].Event.Type)
+	assert.Contains(t, dead[0].LastError, "503")
+
+	log, err := d.Deliveries(w.ID)
+	require.NoError(t, err)
+	require.Len(t, log, 3)
+	assert.Equal(t, []int{3, 2, 1}, []int{log[0].Attempt, log[1].Attempt, log[2].Attempt})
+
+	fail.Store(false)
+	require.NoError(t, d.Redeliver(w.ID, dead[0].DeliveryID))
+	assert.Equal(t, first, next(t, ch).header.Get(webhooks.HeaderDelivery))
+	require.Eventually(t, func() bool {
+		log, _ = d.Deliveries(w.ID)
+		return len(log) == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, log[0].Error)
+	dead, _ = d.DeadLetters(w.ID)
+	assert.Empty(t, dead)
+	assert.ErrorIs(t, d.Redeliver(w.ID, log[0].ID), webhooks.ErrDeadLetterNotFound)
+}